@@ -0,0 +1,212 @@
+//go:build !android
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+
+	pmapp "password-manager/internal/app"
+	"password-manager/internal/app/db"
+	"password-manager/internal/app/endpoint"
+	"password-manager/pkg/security"
+)
+
+// Конфигурация headless-сервера: флаги, по умолчанию берутся из окружения
+type config struct {
+	Addr            string
+	DBPath          string
+	Vaults          vaultFlags
+	Registry        string
+	TLSCert         string
+	TLSKey          string
+	KeyFile         string
+	HIBPPath        string
+	SessionTTL      time.Duration
+	ShutdownTimeout time.Duration
+	MigrateDryRun   bool
+}
+
+func loadConfig() config {
+	var cfg config
+	flag.StringVar(&cfg.Addr, "addr", envOr("PM_ADDR", ":8080"), "listen address")
+	flag.StringVar(&cfg.DBPath, "db", envOr("PM_DB", "passwords.db"), "path to the SQLite vault")
+	flag.Var(&cfg.Vaults, "vault", "serve a vault given as name=path; repeatable, the first one is the default (overrides -db)")
+	flag.StringVar(&cfg.Registry, "registry", os.Getenv("PM_REGISTRY"), "serve every vault listed in a registry file (vaults.json of the desktop app)")
+	flag.StringVar(&cfg.TLSCert, "tls-cert", os.Getenv("PM_TLS_CERT"), "TLS certificate file (enables HTTPS together with -tls-key)")
+	flag.StringVar(&cfg.TLSKey, "tls-key", os.Getenv("PM_TLS_KEY"), "TLS private key file")
+	flag.StringVar(&cfg.KeyFile, "key-file", os.Getenv("PM_KEY_FILE"), "file with the master password to unlock the default vault at startup")
+	flag.StringVar(&cfg.HIBPPath, "hibp", os.Getenv("PM_HIBP"), "local Pwned Passwords SHA-1 file (sorted by hash) or range directory for breach checks in GET /report")
+	flag.DurationVar(&cfg.SessionTTL, "session-ttl", 15*time.Minute, "lifetime of bearer tokens issued by POST /session")
+	flag.DurationVar(&cfg.ShutdownTimeout, "shutdown-timeout", 10*time.Second, "graceful shutdown timeout")
+	flag.BoolVar(&cfg.MigrateDryRun, "migrate-dry-run", false, "print pending schema migrations and exit")
+	flag.Parse()
+	return cfg
+}
+
+// -vault name=path, можно несколько раз
+type vaultFlags []string
+
+func (v *vaultFlags) String() string { return strings.Join(*v, ",") }
+
+func (v *vaultFlags) Set(spec string) error {
+	*v = append(*v, spec)
+	return nil
+}
+
+// vaultList: хранилища из -vault, иначе из -registry, иначе одно -db под именем "default".
+// Хранилище по умолчанию всегда первое.
+func (cfg config) vaultList() ([]pmapp.VaultEntry, error) {
+	switch {
+	case len(cfg.Vaults) > 0:
+		reg := &pmapp.Registry{}
+		for _, spec := range cfg.Vaults {
+			name, path, ok := strings.Cut(spec, "=")
+			if !ok {
+				return nil, fmt.Errorf("-vault %q: expected name=path", spec)
+			}
+			if err := reg.Add(name, path); err != nil {
+				return nil, fmt.Errorf("-vault %q: %w", spec, err)
+			}
+		}
+		return reg.Vaults, nil
+	case cfg.Registry != "":
+		reg, err := pmapp.LoadRegistry(cfg.Registry)
+		if err != nil {
+			return nil, err
+		}
+		def, err := reg.DefaultVault()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", cfg.Registry, err)
+		}
+		vaults := []pmapp.VaultEntry{def}
+		for _, v := range reg.Vaults {
+			if v.Name != def.Name {
+				vaults = append(vaults, v)
+			}
+		}
+		return vaults, nil
+	default:
+		return []pmapp.VaultEntry{{Name: pmapp.DefaultVaultName, Path: cfg.DBPath}}, nil
+	}
+}
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func main() {
+	cfg := loadConfig()
+
+	if (cfg.TLSCert == "") != (cfg.TLSKey == "") {
+		log.Fatal("both -tls-cert and -tls-key must be set to enable TLS")
+	}
+
+	vaults, err := cfg.vaultList()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if cfg.MigrateDryRun {
+		for _, v := range vaults {
+			pending, err := db.PendingMigrations(v.Path)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if len(vaults) > 1 {
+				fmt.Printf("[%s]\n", v.Name)
+			}
+			if len(pending) == 0 {
+				fmt.Println("schema is up to date")
+			}
+			for _, name := range pending {
+				fmt.Println(name)
+			}
+		}
+		return
+	}
+
+	e := echo.New()
+	e.HideBanner = true
+	e.Use(middleware.Recover())
+	e.Use(middleware.Logger())
+
+	served := make([]endpoint.Vault, 0, len(vaults))
+	for _, v := range vaults {
+		appInstance := pmapp.InitApp(e, v.Path)
+		appInstance.Vault = v.Name
+		defer appInstance.DB.Close()
+		served = append(served, endpoint.Vault{Name: v.Name, App: appInstance})
+	}
+
+	// Без key-file хранилища остаются заблокированными до вызова POST /session;
+	// key-file открывает только хранилище по умолчанию. Создать новое
+	// хранилище можно только так (или из десктопа) — REST его не создаёт
+	if cfg.KeyFile != "" {
+		if err := unlockFromKeyFile(served[0].App, cfg.KeyFile); err != nil {
+			log.Fatalf("unlock from key file: %v", err)
+		}
+		log.Printf("vault %q unlocked from key file", served[0].Name)
+	}
+
+	// Проверка по утечкам только локально: без -hibp её в отчёте нет
+	if cfg.HIBPPath != "" {
+		if err := security.Breaches.Open(cfg.HIBPPath); err != nil {
+			log.Fatalf("hibp: %v", err)
+		}
+		defer security.Breaches.Close()
+		log.Printf("breach checks use %s", cfg.HIBPPath)
+	}
+
+	endpoint.RegisterVaults(e, served, cfg.SessionTTL)
+
+	go func() {
+		var err error
+		if cfg.TLSCert != "" {
+			err = e.StartTLS(cfg.Addr, cfg.TLSCert, cfg.TLSKey)
+		} else {
+			err = e.Start(cfg.Addr)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		log.Printf("shutdown: %v", err)
+	}
+}
+
+// Key-file содержит мастер-пароль (завершающий перевод строки игнорируется)
+func unlockFromKeyFile(appInstance *pmapp.App, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	password := strings.TrimRight(string(data), "\r\n")
+	if password == "" {
+		return errors.New("key file is empty")
+	}
+	return appInstance.InitializeMasterWithPassword(password)
+}
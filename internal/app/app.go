@@ -3,16 +3,21 @@ package app
 import (
     "errors"
     "log"
+    "sync/atomic"
 
     "github.com/labstack/echo/v4"
     "password-manager/internal/app/db"
+    "password-manager/pkg/security"
     "password-manager/pkg/utils"
 )
 
 type App struct {
     DB     db.Storage
-    Crypto *utils.CryptoService
     Logger echo.Logger
+    Vault  string // имя хранилища в реестре (пусто — без реестра)
+
+    // ключ меняют разблокировка и автоблокировка из других горутин, см. Crypto
+    crypto atomic.Pointer[utils.CryptoService]
 }
 
 // Веб-инициализация
@@ -21,22 +26,54 @@ func InitApp(e *echo.Echo, dbPath string) *App {
     if err != nil {
         log.Fatal(err)
     }
-    return &App{DB: storage, Crypto: nil, Logger: e.Logger}
+    return &App{DB: storage, Logger: e.Logger}
 }
 
 // Десктоп-инициализация
 func InitDesktopApp(dbPath string) *App {
-    storage, err := db.InitDB(dbPath, nil)
+    a, err := OpenDesktopApp(dbPath)
     if err != nil {
         log.Fatal(err)
     }
-    return &App{DB: storage, Crypto: nil, Logger: nil}
+    return a
+}
+
+// То же, но с ошибкой: файл хранилища выбирает пользователь и он может оказаться не базой
+func OpenDesktopApp(dbPath string) (*App, error) {
+    storage, err := db.InitDB(dbPath, nil)
+    if err != nil {
+        return nil, err
+    }
+    return &App{DB: storage}, nil
+}
+
+// Crypto: текущий ключ (nil — заблокировано). Берётся один раз на операцию:
+// после Lock() старый снимок отвечает ErrLocked, а не паникует.
+func (a *App) Crypto() *utils.CryptoService {
+    return a.crypto.Load()
 }
 
 // Установка Crypto после успешной проверки пароля
 func (a *App) SetCryptoFromKey(key []byte) {
-    a.Crypto = utils.NewCryptoService(key)
-    a.DB.SetCrypto(a.Crypto)
+    c := utils.NewCryptoService(key)
+    old := a.crypto.Swap(c)
+    a.DB.SetCrypto(c)
+    old.Wipe()
+}
+
+// Блокировка: ключ затирается, скопированный секрет стирается из буфера,
+// хранилище остаётся открытым
+func (a *App) Lock() {
+    old := a.crypto.Swap(nil)
+    if a.DB != nil {
+        a.DB.SetCrypto(nil)
+    }
+    old.Wipe()
+    utils.Clipboard.Clear()
+}
+
+func (a *App) IsLocked() bool {
+    return a.Crypto() == nil
 }
 
 // Проверка наличия meta (соль+верификатор)
@@ -58,5 +95,49 @@ func (a *App) InitializeMasterWithPassword(password string) error {
         return err
     }
     a.SetCryptoFromKey(key)
+
+    // Одноразово: привязать старые шифртексты к их строкам
+    if err := sqlStore.ResealLegacyRows(); err != nil {
+        log.Printf("reseal legacy entries: %v", err)
+    }
+    // Одноразово: свободные категории → папки
+    if err := sqlStore.MigrateCategories(); err != nil {
+        log.Printf("migrate categories: %v", err)
+    }
+    if n, err := sqlStore.PurgeExpiredTrash(); err != nil {
+        log.Printf("purge trash: %v", err)
+    } else if n > 0 {
+        log.Printf("purged %d expired entries from trash", n)
+    }
     return nil
 }
+
+// Смена мастер-пароля; хранилище должно быть разблокировано
+func (a *App) ChangeMasterPassword(oldPassword, newPassword string) error {
+    crypto := a.Crypto()
+    if crypto == nil {
+        return utils.ErrLocked
+    }
+    sqlStore, ok := a.DB.(*db.SQLStorage)
+    if !ok {
+        return errors.New("invalid storage")
+    }
+    return db.ChangeMasterPassword(sqlStore.DB, crypto, oldPassword, newPassword)
+}
+
+// Повторный ввод мастер-пароля перед опасными действиями (экспорт без шифрования)
+func (a *App) VerifyMasterPassword(password string) error {
+    if a.IsLocked() {
+        return utils.ErrLocked
+    }
+    sqlStore, ok := a.DB.(*db.SQLStorage)
+    if !ok {
+        return errors.New("invalid storage")
+    }
+    rec, err := security.LoadMasterRecord(sqlStore.DB)
+    if err != nil {
+        return err
+    }
+    _, err = rec.Verify(password)
+    return err
+}
@@ -0,0 +1,135 @@
+package app
+
+import (
+    "sync"
+    "time"
+
+    "password-manager/internal/app/model"
+)
+
+// Автоблокировка разблокированного хранилища по политике из meta:
+// бездействие, предельная длина сессии, сон системы.
+// Потерю фокуса окнами отслеживает GUI и вызывает LockNow сам.
+
+type LockReason string
+
+const (
+    LockIdle       LockReason = "idle"
+    LockMaxSession LockReason = "max_session"
+    LockSuspend    LockReason = "suspend"
+    LockFocusLoss  LockReason = "focus_loss"
+    LockManual     LockReason = "manual"
+)
+
+const (
+    autoLockTick = 5 * time.Second
+    // На сколько стенные часы могут обогнать монотонные без сна системы
+    suspendGap = 30 * time.Second
+)
+
+type AutoLock struct {
+    app    *App
+    onLock func(LockReason)
+
+    mu           sync.Mutex
+    policy       model.LockPolicy
+    unlockedAt   time.Time // стенное время, без монотонной части
+    lastActivity time.Time
+
+    stop     chan struct{}
+    stopOnce sync.Once
+}
+
+// StartAutoLock запускает наблюдение за только что разблокированным хранилищем.
+// onLock вызывается один раз, из фоновой горутины, уже после App.Lock().
+func (a *App) StartAutoLock(onLock func(LockReason)) *AutoLock {
+    now := time.Now().Round(0)
+    l := &AutoLock{
+        app:          a,
+        onLock:       onLock,
+        policy:       a.DB.LockPolicy(),
+        unlockedAt:   now,
+        lastActivity: now,
+        stop:         make(chan struct{}),
+    }
+    go l.run()
+    return l
+}
+
+func (l *AutoLock) Policy() model.LockPolicy {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    return l.policy
+}
+
+// SetPolicy сохраняет политику в хранилище и сразу её применяет
+func (l *AutoLock) SetPolicy(p model.LockPolicy) error {
+    if err := l.app.DB.SetLockPolicy(p); err != nil {
+        return err
+    }
+    l.mu.Lock()
+    l.policy = p
+    l.mu.Unlock()
+    return nil
+}
+
+// Touch: активность пользователя (клавиатура, мышь)
+func (l *AutoLock) Touch() {
+    l.mu.Lock()
+    l.lastActivity = time.Now().Round(0)
+    l.mu.Unlock()
+}
+
+// LockNow блокирует хранилище (ключ затирается) и вызывает onLock; повторные вызовы — no-op
+func (l *AutoLock) LockNow(reason LockReason) {
+    l.stopOnce.Do(func() {
+        close(l.stop)
+        l.app.Lock()
+        if l.onLock != nil {
+            l.onLock(reason)
+        }
+    })
+}
+
+// Stop останавливает наблюдение без блокировки (хранилище закрывают иначе)
+func (l *AutoLock) Stop() {
+    l.stopOnce.Do(func() { close(l.stop) })
+}
+
+func (l *AutoLock) run() {
+    ticker := time.NewTicker(autoLockTick)
+    defer ticker.Stop()
+
+    prev := time.Now()
+    for {
+        select {
+        case <-l.stop:
+            return
+        case now := <-ticker.C:
+            // Во сне монотонные часы стоят, а стенные идут
+            slept := now.Round(0).Sub(prev.Round(0))-now.Sub(prev) > suspendGap
+            prev = now
+            if reason, ok := l.due(now.Round(0), slept); ok {
+                l.LockNow(reason)
+                return
+            }
+        }
+    }
+}
+
+// due: пора ли блокировать. Сроки считаются по стенным часам, чтобы
+// время сна тоже шло в зачёт, даже если блокировка при сне выключена.
+func (l *AutoLock) due(now time.Time, slept bool) (LockReason, bool) {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    p := l.policy
+    switch {
+    case slept && p.OnSuspend:
+        return LockSuspend, true
+    case p.MaxSessionMinutes > 0 && now.Sub(l.unlockedAt) >= p.MaxSession():
+        return LockMaxSession, true
+    case p.IdleMinutes > 0 && now.Sub(l.lastActivity) >= p.IdleTimeout():
+        return LockIdle, true
+    }
+    return "", false
+}
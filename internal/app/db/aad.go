@@ -0,0 +1,275 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+)
+
+// Версии формата шифртекстов строки (passwords.enc_version)
+const (
+	// password без AAD, record привязан только к ID строки
+	encVersionLegacy = 0
+	// каждое поле привязано к хранилищу, строке и имени поля
+	encVersionBound = 1
+)
+
+const (
+	fieldPassword = "password"
+	fieldRecord   = "record"
+	fieldTOTP     = "totp"
+	fieldDetails  = "details"
+	fieldName     = "name" // folders/tags.name_enc
+)
+
+// rowAD — дополнительные данные AES-GCM для поля строки passwords: шифртекст,
+// перенесённый в другую строку, поле или хранилище, не расшифруется
+func rowAD(vaultID string, id int64, field string, version int) []byte {
+	return tableAD(vaultID, "passwords", id, field, version)
+}
+
+// tableAD — то же для строки произвольной таблицы (старый формат бывает только у passwords)
+func tableAD(vaultID, table string, id int64, field string, version int) []byte {
+	if version == encVersionLegacy {
+		if field == fieldRecord {
+			return []byte("passwords/" + strconv.FormatInt(id, 10) + "/record")
+		}
+		return nil
+	}
+	return []byte(fmt.Sprintf("pm/v%d/%s/%s/%d/%s", version, vaultID, table, id, field))
+}
+
+// readVaultID: пустая строка — ID ещё не назначен (значит, и привязанных строк нет)
+func readVaultID(q interface {
+	QueryRow(query string, args ...any) *sql.Row
+}) (string, error) {
+	var id sql.NullString
+	err := q.QueryRow(`SELECT vault_id FROM meta WHERE id = 1`).Scan(&id)
+	return id.String, err
+}
+
+// vaultID возвращает ID хранилища, при первом обращении назначая его
+func (s *SQLStorage) vaultID() (string, error) {
+	s.vaultMu.Lock()
+	defer s.vaultMu.Unlock()
+	if s.vault != "" {
+		return s.vault, nil
+	}
+	if _, err := s.DB.Exec(
+		`UPDATE meta SET vault_id = lower(hex(randomblob(16))) WHERE id = 1 AND vault_id IS NULL`,
+	); err != nil {
+		return "", err
+	}
+	id, err := readVaultID(s.DB)
+	if err != nil {
+		return "", err
+	}
+	s.vault = id
+	return id, nil
+}
+
+// encryptField всегда шифрует в текущем формате (encVersionBound)
+func (s *SQLStorage) encryptField(id int64, field, plain string) (string, error) {
+	if err := s.requireCrypto(); err != nil {
+		return "", err
+	}
+	vault, err := s.vaultID()
+	if err != nil {
+		return "", err
+	}
+	return s.cryptoService().Encrypt(plain, rowAD(vault, id, field, encVersionBound))
+}
+
+func (s *SQLStorage) decryptField(id int64, field, encB64 string, version int) (string, error) {
+	if err := s.requireCrypto(); err != nil {
+		return "", err
+	}
+	vault, err := s.vaultID()
+	if err != nil {
+		return "", err
+	}
+	return s.cryptoService().Decrypt(encB64, rowAD(vault, id, field, version))
+}
+
+// DecryptPassword: актуальный пароль записи в открытом виде (для копирования)
+func (s *SQLStorage) DecryptPassword(id int) (string, error) {
+	var enc string
+	var version int
+	err := s.DB.QueryRow("SELECT password, enc_version FROM passwords WHERE id = ?", id).Scan(&enc, &version)
+	if err != nil {
+		return "", err
+	}
+	return s.decryptField(int64(id), fieldPassword, enc, version)
+}
+
+// ResealLegacyRows — одноразовая миграция: перешифровывает строки старого
+// формата с привязкой к строке. Выполняется после разблокировки, т.к. нужен ключ.
+func (s *SQLStorage) ResealLegacyRows() error {
+	if err := s.requireCrypto(); err != nil {
+		return err
+	}
+	// назначить vault_id до транзакции: внутри неё писать в meta нельзя
+	if _, err := s.vaultID(); err != nil {
+		return err
+	}
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	n, err := s.resealRows(tx)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if n > 0 {
+		log.Printf("resealed %d entries with row-bound encryption", n)
+		s.invalidateIndex()
+	}
+	return nil
+}
+
+func (s *SQLStorage) resealRows(tx *sql.Tx) (int, error) {
+	list, err := readEncryptedRows(tx, &passwordsTable, `WHERE enc_version < ?`, encVersionBound)
+	if err != nil {
+		return 0, err
+	}
+	for _, r := range list {
+		err := r.transform(func(field, enc string) (string, error) {
+			plain, err := s.decryptField(r.id, field, enc, r.version)
+			if err != nil {
+				return "", fmt.Errorf("id=%d open %s: %w", r.id, field, err)
+			}
+			return s.encryptField(r.id, field, plain)
+		})
+		if err != nil {
+			return 0, err
+		}
+		if err := r.write(tx, encVersionBound); err != nil {
+			return 0, err
+		}
+	}
+	return len(list), nil
+}
+
+type encryptedColumn struct{ column, field string }
+
+// encryptedTable — таблица с шифртекстами и колонкой enc_version:
+// колонки required не бывают NULL, optional — могут
+type encryptedTable struct {
+	name     string
+	owner    string // колонка с ID записи passwords, см. DecryptError; "0" — строка не запись
+	required []encryptedColumn
+	optional []encryptedColumn
+}
+
+var (
+	passwordsTable = encryptedTable{name: "passwords", owner: "id",
+		required: []encryptedColumn{{"password", fieldPassword}},
+		optional: []encryptedColumn{
+			{"record", fieldRecord},
+			{"totp_secret", fieldTOTP},
+			{"details", fieldDetails},
+		},
+	}
+	historyTable = encryptedTable{name: "password_history", owner: "password_id",
+		required: []encryptedColumn{{"password", fieldPassword}},
+	}
+	// имена папок и тегов в запечатанном хранилище, см. folders.go
+	foldersTable = encryptedTable{name: "folders", owner: "0", optional: []encryptedColumn{{"name_enc", fieldName}}}
+	tagsTable    = encryptedTable{name: "tags", owner: "0", optional: []encryptedColumn{{"name_enc", fieldName}}}
+
+	// Перешифровка и проверка записей при смене ключа проходят по этому списку
+	encryptedTables = []*encryptedTable{&passwordsTable, &historyTable, &foldersTable, &tagsTable}
+)
+
+// encryptedRow — все шифртексты одной строки
+type encryptedRow struct {
+	table    *encryptedTable
+	id       int64
+	owner    int64
+	required []string         // в порядке table.required
+	optional []sql.NullString // в порядке table.optional
+	version  int
+}
+
+// readEncryptedRows читает строки целиком до начала записи:
+// открытый курсор на той же таблице не даёт SQLite их обновлять
+func readEncryptedRows(tx *sql.Tx, t *encryptedTable, where string, args ...any) ([]encryptedRow, error) {
+	cols := "id, " + t.owner + ", enc_version"
+	for _, c := range t.required {
+		cols += ", " + c.column
+	}
+	for _, c := range t.optional {
+		cols += ", " + c.column
+	}
+	rows, err := tx.Query("SELECT "+cols+" FROM "+t.name+" "+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []encryptedRow
+	for rows.Next() {
+		r := encryptedRow{
+			table:    t,
+			required: make([]string, len(t.required)),
+			optional: make([]sql.NullString, len(t.optional)),
+		}
+		dest := []any{&r.id, &r.owner, &r.version}
+		for i := range r.required {
+			dest = append(dest, &r.required[i])
+		}
+		for i := range r.optional {
+			dest = append(dest, &r.optional[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		list = append(list, r)
+	}
+	return list, rows.Err()
+}
+
+// ad: AAD поля этой строки
+func (r *encryptedRow) ad(vaultID, field string) []byte {
+	return tableAD(vaultID, r.table.name, r.id, field, r.version)
+}
+
+// transform применяет fn к обязательным и ко всем непустым необязательным полям
+func (r *encryptedRow) transform(fn func(field, enc string) (string, error)) error {
+	var err error
+	for i, c := range r.table.required {
+		if r.required[i], err = fn(c.field, r.required[i]); err != nil {
+			return err
+		}
+	}
+	for i, c := range r.table.optional {
+		if !r.optional[i].Valid {
+			continue
+		}
+		if r.optional[i].String, err = fn(c.field, r.optional[i].String); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *encryptedRow) write(tx *sql.Tx, version int) error {
+	set := "enc_version = ?"
+	args := []any{version}
+	for i, c := range r.table.required {
+		set += ", " + c.column + " = ?"
+		args = append(args, r.required[i])
+	}
+	for i, c := range r.table.optional {
+		set += ", " + c.column + " = ?"
+		args = append(args, r.optional[i])
+	}
+	_, err := tx.Exec("UPDATE "+r.table.name+" SET "+set+" WHERE id = ?", append(args, r.id)...)
+	return err
+}
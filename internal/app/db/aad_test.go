@@ -0,0 +1,96 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+
+	"password-manager/internal/app/model"
+	"password-manager/pkg/utils"
+)
+
+const testMasterPassword = "correct horse battery staple"
+
+// newTestVault: новое разблокированное хранилище во временном каталоге
+func newTestVault(t *testing.T) *SQLStorage {
+	t.Helper()
+	st, err := InitDB(filepath.Join(t.TempDir(), "vault.db"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := st.(*SQLStorage)
+	t.Cleanup(func() { s.Close() })
+	key, err := LoadOrInitMasterFromDB(s.DB, testMasterPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetCrypto(utils.NewCryptoService(key))
+	return s
+}
+
+func createTestEntry(t *testing.T, s *SQLStorage, p model.Password) int {
+	t.Helper()
+	id, _, err := s.CreatePassword(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return int(id)
+}
+
+// Шифртекст привязан к хранилищу, строке и полю (tableAD): перенесённый
+// в другую строку или другое поле, он не расшифровывается
+func TestCiphertextBoundToRowAndField(t *testing.T) {
+	s := newTestVault(t)
+	notes := "recovery codes"
+	a := createTestEntry(t, s, model.Password{Service: "a", Username: "u", Password: "alpha-secret", Notes: &notes})
+	b := createTestEntry(t, s, model.Password{Service: "b", Username: "u", Password: "bravo-secret"})
+
+	if got, err := s.DecryptPassword(a); err != nil || got != "alpha-secret" {
+		t.Fatalf("before swap: %q, %v", got, err)
+	}
+
+	// пароли двух строк меняются местами
+	var encA, encB string
+	if err := s.DB.QueryRow(`SELECT password FROM passwords WHERE id = ?`, a).Scan(&encA); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DB.QueryRow(`SELECT password FROM passwords WHERE id = ?`, b).Scan(&encB); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.DB.Exec(`UPDATE passwords SET password = ? WHERE id = ?`, encB, a); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.DB.Exec(`UPDATE passwords SET password = ? WHERE id = ?`, encA, b); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []int{a, b} {
+		if got, err := s.DecryptPassword(id); err == nil {
+			t.Errorf("row %d decrypted a swapped password: %q", id, got)
+		}
+	}
+
+	// поля одной строки меняются местами: пароль в details, details в password
+	var details string
+	if err := s.DB.QueryRow(`SELECT details FROM passwords WHERE id = ?`, a).Scan(&details); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.DB.Exec(`UPDATE passwords SET password = ?, details = ? WHERE id = ?`, details, encA, a); err != nil {
+		t.Fatal(err)
+	}
+	if d, err := s.GetDetails(a); err == nil {
+		t.Errorf("password ciphertext decrypted as details: %+v", d)
+	}
+	if got, err := s.DecryptPassword(a); err == nil {
+		t.Errorf("details ciphertext decrypted as password: %q", got)
+	}
+
+	// на своих местах всё снова читается — ошибки выше именно из-за привязки
+	if _, err := s.DB.Exec(`UPDATE passwords SET password = ?, details = ? WHERE id = ?`, encA, details, a); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := s.DecryptPassword(a); err != nil || got != "alpha-secret" {
+		t.Errorf("restored password: %q, %v", got, err)
+	}
+	if d, err := s.GetDetails(a); err != nil || d.Notes != notes {
+		t.Errorf("restored details: %+v, %v", d, err)
+	}
+}
@@ -0,0 +1,26 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"password-manager/pkg/utils"
+)
+
+// ClipboardTimeout: через сколько очищать буфер обмена после копирования секрета
+func (s *SQLStorage) ClipboardTimeout() time.Duration {
+	var seconds int
+	if err := s.DB.QueryRow(`SELECT clipboard_clear_seconds FROM meta WHERE id = 1`).Scan(&seconds); err != nil {
+		return utils.DefaultClipboardTimeout
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func (s *SQLStorage) SetClipboardTimeout(d time.Duration) error {
+	if d < utils.MinClipboardTimeout || d > utils.MaxClipboardTimeout || d%time.Second != 0 {
+		return fmt.Errorf("clipboard timeout must be whole seconds between %d and %d",
+			int(utils.MinClipboardTimeout.Seconds()), int(utils.MaxClipboardTimeout.Seconds()))
+	}
+	_, err := s.DB.Exec(`UPDATE meta SET clipboard_clear_seconds = ? WHERE id = 1`, int(d.Seconds()))
+	return err
+}
@@ -2,39 +2,44 @@ package db
 
 import (
     "database/sql"
+    "fmt"
+    "os"
+    "time"
 
     _ "github.com/mattn/go-sqlite3"
     "password-manager/pkg/utils"
 )
 
 func InitDB(path string, crypto *utils.CryptoService) (Storage, error) {
+    // Резервная копия нужна только для уже существующего файла
+    var backup string
+    if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+        backup = fmt.Sprintf("%s.bak-%s", path, time.Now().UTC().Format("20060102T150405"))
+    }
+
     conn, err := sql.Open("sqlite3", path)
     if err != nil {
         return nil, err
     }
 
-    // Таблица паролей: хранится base64(AES-GCM)
-    if _, err = conn.Exec(`CREATE TABLE IF NOT EXISTS passwords (
-        id INTEGER PRIMARY KEY AUTOINCREMENT,
-        service TEXT NOT NULL,
-        username TEXT NOT NULL,
-        link TEXT NOT NULL,
-        password TEXT NOT NULL,    -- base64(nonce||ciphertext)
-        category TEXT NOT NULL,
-        created_at TEXT NOT NULL
-    )`); err != nil {
+    if _, err = Migrate(conn, MigrateOptions{BackupPath: backup}); err != nil {
+        conn.Close()
         return nil, err
     }
 
-    // Одна запись мастер-пароля: id=1
-    if _, err = conn.Exec(`CREATE TABLE IF NOT EXISTS master_password (
-        id INTEGER PRIMARY KEY,
-        email TEXT NOT NULL,
-        salt TEXT NOT NULL,        -- base64
-        verifier TEXT NOT NULL     -- base64 SHA256(key)
-    )`); err != nil {
+    return NewSQLStorage(conn, crypto), nil
+}
+
+// PendingMigrations — dry-run: какие миграции будут применены к файлу по path.
+// Файл открывается только на чтение и не создаётся.
+func PendingMigrations(path string) ([]string, error) {
+    if _, err := os.Stat(path); os.IsNotExist(err) {
+        return migrationNames(migrations), nil
+    }
+    conn, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+    if err != nil {
         return nil, err
     }
-
-    return NewSQLStorage(conn, crypto), nil
+    defer conn.Close()
+    return Migrate(conn, MigrateOptions{DryRun: true})
 }
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"password-manager/internal/app/model"
+)
+
+// Заметки, пользовательские поля и правила генератора лежат в passwords.details
+// одним AES-GCM блоком (JSON model.EntryDetails); NULL — ничего нет.
+
+func (s *SQLStorage) sealDetails(id int64, d model.EntryDetails) (any, error) {
+	for _, f := range d.Fields {
+		if err := f.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if d.Generator != nil {
+		if err := d.Generator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if d.Notes == "" && len(d.Fields) == 0 && d.Generator == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	return s.encryptField(id, fieldDetails, string(data))
+}
+
+// GetDetails: расшифрованные заметки и поля записи (включая скрытые)
+func (s *SQLStorage) GetDetails(id int) (model.EntryDetails, error) {
+	var d model.EntryDetails
+	var enc sql.NullString
+	var version int
+	err := s.DB.QueryRow("SELECT details, enc_version FROM passwords WHERE id = ?", id).Scan(&enc, &version)
+	if err != nil || !enc.Valid {
+		return d, err
+	}
+	plain, err := s.decryptField(int64(id), fieldDetails, enc.String, version)
+	if err != nil {
+		return d, err
+	}
+	err = json.Unmarshal([]byte(plain), &d)
+	return d, err
+}
+
+// detailsFor собирает новое значение колонки из p: nil-поля берутся из
+// текущей записи (existing == nil — записи ещё нет)
+func (s *SQLStorage) detailsFor(id int64, p model.Password, existing *model.EntryDetails) (any, error) {
+	var d model.EntryDetails
+	if existing != nil {
+		d = *existing
+	}
+	if p.Notes != nil {
+		d.Notes = *p.Notes
+	}
+	if p.Fields != nil {
+		d.Fields = p.Fields
+	}
+	if p.Generator != nil {
+		d.Generator = p.Generator
+		if p.Generator.Length == 0 {
+			d.Generator = nil
+		}
+	}
+	return s.sealDetails(id, d)
+}
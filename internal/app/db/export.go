@@ -0,0 +1,302 @@
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"password-manager/internal/app/model"
+	"password-manager/pkg/security"
+)
+
+// Экспорт хранилища в переносимый контейнер: JSON с заголовком (формат,
+// версия, параметры KDF, шифр) и данными, зашифрованными ключом из
+// отдельной фразы-пароля. Заголовок входит в AAD, так что подменить
+// параметры или версию незаметно нельзя.
+
+const (
+	exportFormat  = "password-manager-export"
+	exportVersion = 1
+	exportCipher  = "aes-256-gcm"
+
+	// контейнер больше этого не читаем
+	maxExportSize = 64 << 20
+)
+
+var (
+	// ErrBadPassphrase: фраза не подошла или контейнер повреждён — GCM их не различает
+	ErrBadPassphrase = errors.New("wrong passphrase or corrupted export")
+	ErrExportFormat  = errors.New("unsupported export format")
+)
+
+type exportKDF struct {
+	Algorithm   string `json:"algorithm"`
+	Iterations  uint32 `json:"iterations"`
+	Memory      uint32 `json:"memory,omitempty"` // KiB
+	Parallelism uint8  `json:"parallelism,omitempty"`
+	Salt        []byte `json:"salt"`
+}
+
+type exportHeader struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	KDF       exportKDF `json:"kdf"`
+	Cipher    string    `json:"cipher"`
+	CreatedAt string    `json:"created_at"`
+}
+
+type exportContainer struct {
+	exportHeader
+	Data []byte `json:"data"` // nonce||ciphertext
+}
+
+type exportPayload struct {
+	Entries []model.ExportEntry `json:"entries"`
+}
+
+func (k exportKDF) params() security.KDFParams {
+	return security.KDFParams{Algorithm: k.Algorithm, Iterations: k.Iterations, Memory: k.Memory, Parallelism: k.Parallelism}
+}
+
+// check не даёт чужому файлу заставить нас выводить ключ часами или с гигабайтами памяти
+func (k exportKDF) check() error {
+	switch k.Algorithm {
+	case security.KDFArgon2id, security.KDFPBKDF2SHA256:
+		if err := k.params().CheckLimits(); err != nil {
+			return fmt.Errorf("%w: %v", ErrExportFormat, err)
+		}
+	default:
+		return fmt.Errorf("%w: kdf %q", ErrExportFormat, k.Algorithm)
+	}
+	if len(k.Salt) < 16 {
+		return fmt.Errorf("%w: salt too short", ErrExportFormat)
+	}
+	return nil
+}
+
+// ad: заголовок в каноническом виде (порядок полей фиксирован структурой)
+func (h exportHeader) ad() ([]byte, error) {
+	return json.Marshal(h)
+}
+
+// Export пишет все записи (включая корзину и историю паролей) в контейнер,
+// зашифрованный ключом из passphrase
+func (s *SQLStorage) Export(w io.Writer, passphrase string) error {
+	if err := s.requireCrypto(); err != nil {
+		return err
+	}
+	if passphrase == "" {
+		return errors.New("export passphrase is empty")
+	}
+	entries, err := s.exportEntries()
+	if err != nil {
+		return err
+	}
+	plain, err := json.Marshal(exportPayload{Entries: entries})
+	if err != nil {
+		return err
+	}
+
+	params := security.DefaultKDFParams()
+	header := exportHeader{
+		Format:  exportFormat,
+		Version: exportVersion,
+		KDF: exportKDF{
+			Algorithm:   params.Algorithm,
+			Iterations:  params.Iterations,
+			Memory:      params.Memory,
+			Parallelism: params.Parallelism,
+			Salt:        security.GenerateSalt(16),
+		},
+		Cipher:    exportCipher,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	key, err := security.DeriveKeyWithParams([]byte(passphrase), header.KDF.Salt, params)
+	if err != nil {
+		return err
+	}
+	ad, err := header.ad()
+	if err != nil {
+		return err
+	}
+	data, err := security.SealAESGCM(key, plain, ad)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(exportContainer{exportHeader: header, Data: data})
+}
+
+// exportEntries: записи в порядке ID, всё расшифровано
+func (s *SQLStorage) exportEntries() ([]model.ExportEntry, error) {
+	list, err := s.GetFilteredPasswords(model.PasswordFilter{IncludeDeleted: true})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+
+	entries := make([]model.ExportEntry, 0, len(list))
+	for _, item := range list {
+		e := model.ExportEntry{
+			Service:   item.Service,
+			Username:  item.Username,
+			Link:      item.Link,
+			Category:  item.Category,
+			Tags:      item.Tags,
+			CreatedAt: item.CreatedAt,
+			UpdatedAt: item.UpdatedAt,
+			DeletedAt: item.DeletedAt,
+		}
+		if e.Password, err = s.DecryptPassword(item.ID); err != nil {
+			return nil, fmt.Errorf("id=%d: %w", item.ID, err)
+		}
+		if item.HasTOTP {
+			t, err := s.GetTOTP(item.ID)
+			if err != nil {
+				return nil, fmt.Errorf("id=%d totp: %w", item.ID, err)
+			}
+			e.TOTPSecret = t.URI()
+		}
+		if item.HasDetails {
+			d, err := s.GetDetails(item.ID)
+			if err != nil {
+				return nil, fmt.Errorf("id=%d details: %w", item.ID, err)
+			}
+			e.Notes, e.Fields, e.Generator = d.Notes, d.Fields, d.Generator
+		}
+
+		history, err := s.GetPasswordHistory(item.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range history {
+			plain, err := s.DecryptHistoryPassword(item.ID, h.ID)
+			if err != nil {
+				return nil, fmt.Errorf("id=%d history %d: %w", item.ID, h.ID, err)
+			}
+			e.History = append(e.History, model.ExportHistory{Password: plain, ChangedAt: h.ChangedAt})
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// Import добавляет записи из контейнера к текущим (ID назначаются заново).
+// Всё или ничего: при любой ошибке хранилище не меняется.
+func (s *SQLStorage) Import(r io.Reader, passphrase string) (int, error) {
+	if err := s.requireCrypto(); err != nil {
+		return 0, err
+	}
+	entries, err := openExport(r, passphrase)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := s.vaultID(); err != nil {
+		return 0, err
+	}
+	sealed := s.SealRecords()
+	now := time.Now().UTC().Format(time.RFC3339)
+	defer s.invalidateIndex()
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	for i, e := range entries {
+		p := model.Password{
+			Service:   e.Service,
+			Username:  e.Username,
+			Link:      e.Link,
+			Password:  e.Password,
+			Category:  legacyFolderPath(e.Category),
+			Notes:     &e.Notes,
+			Tags:      e.Tags,
+			Fields:    e.Fields,
+			Generator: e.Generator,
+		}
+		if e.TOTPSecret != "" {
+			p.TOTPSecret = &e.TOTPSecret
+		}
+		createdAt := e.CreatedAt
+		if createdAt == "" {
+			createdAt = now
+		}
+
+		id, err := s.insertPassword(tx, p, createdAt, sealed)
+		if err != nil {
+			return 0, fmt.Errorf("entry %d: %w", i+1, err)
+		}
+		// история в контейнере новыми сверху, вставляем со старых
+		for j := len(e.History) - 1; j >= 0; j-- {
+			h := e.History[j]
+			if err := s.insertHistory(tx, id, h.Password, h.ChangedAt); err != nil {
+				return 0, err
+			}
+		}
+		if e.UpdatedAt != "" {
+			if _, err := tx.Exec(`UPDATE passwords SET updated_at = ? WHERE id = ?`, e.UpdatedAt, id); err != nil {
+				return 0, err
+			}
+		}
+		if e.DeletedAt != "" {
+			if _, err := tx.Exec(`UPDATE passwords SET deleted_at = ? WHERE id = ?`, e.DeletedAt, id); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// openExport проверяет заголовок, выводит ключ и расшифровывает записи
+func openExport(r io.Reader, passphrase string) ([]model.ExportEntry, error) {
+	raw, err := io.ReadAll(io.LimitReader(r, maxExportSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) > maxExportSize {
+		return nil, fmt.Errorf("%w: file too large", ErrExportFormat)
+	}
+	var c exportContainer
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExportFormat, err)
+	}
+	if c.Format != exportFormat {
+		return nil, ErrExportFormat
+	}
+	if c.Version != exportVersion || c.Cipher != exportCipher {
+		return nil, fmt.Errorf("%w: version %d, cipher %q", ErrExportFormat, c.Version, c.Cipher)
+	}
+	if err := c.KDF.check(); err != nil {
+		return nil, err
+	}
+
+	key, err := security.DeriveKeyWithParams([]byte(passphrase), c.KDF.Salt, c.KDF.params())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExportFormat, err)
+	}
+	ad, err := c.exportHeader.ad()
+	if err != nil {
+		return nil, err
+	}
+	plain, err := security.OpenAESGCM(key, c.Data, ad)
+	if err != nil {
+		return nil, ErrBadPassphrase
+	}
+
+	var payload exportPayload
+	if err := json.Unmarshal(plain, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExportFormat, err)
+	}
+	return payload.Entries, nil
+}
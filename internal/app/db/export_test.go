@@ -0,0 +1,119 @@
+package db
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"password-manager/internal/app/model"
+)
+
+const testExportPassphrase = "export passphrase for tests"
+
+// exportTestVault: хранилище со всем, что переносит экспорт, — папкой,
+// тегами, заметками, полями, TOTP, историей пароля и записью в корзине
+func exportTestVault(t *testing.T) (*SQLStorage, []byte) {
+	t.Helper()
+	s := newTestVault(t)
+	notes := "line1\nline2"
+	totp := "otpauth://totp/GitHub:octo?secret=JBSWY3DPEHPK3PXP&issuer=GitHub"
+	p := model.Password{
+		Service: "github", Username: "octo", Link: "https://github.com", Password: "first-Pass-123",
+		Category: "Work/Dev", Tags: []string{"code", "ci"}, Notes: &notes, TOTPSecret: &totp,
+		Fields: []model.CustomField{{Name: "PIN", Type: model.FieldHidden, Value: "1234"}},
+	}
+	id := createTestEntry(t, s, p)
+	p.Password = "second-Pass-456"
+	if err := s.UpdatePassword(strconv.Itoa(id), p); err != nil {
+		t.Fatal(err)
+	}
+	trashed := createTestEntry(t, s, model.Password{Service: "old", Username: "u", Password: "gone-Pass-789"})
+	if err := s.DeletePassword(strconv.Itoa(trashed)); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := s.Export(&buf, testExportPassphrase); err != nil {
+		t.Fatal(err)
+	}
+	return s, buf.Bytes()
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	src, data := exportTestVault(t)
+	want, err := src.exportEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst := newTestVault(t)
+	n, err := dst.Import(bytes.NewReader(data), testExportPassphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(want) {
+		t.Fatalf("imported %d entries, want %d", n, len(want))
+	}
+	got, err := dst.exportEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("entries differ after round trip:\n got %+v\nwant %+v", got, want)
+	}
+	if len(want) != 2 || len(want[0].History) != 1 || want[1].DeletedAt == "" {
+		t.Errorf("fixture lost history or trash state: %+v", want)
+	}
+}
+
+func TestImportRejectsWrongPassphrase(t *testing.T) {
+	_, data := exportTestVault(t)
+	dst := newTestVault(t)
+	if _, err := dst.Import(bytes.NewReader(data), "not the passphrase"); !errors.Is(err, ErrBadPassphrase) {
+		t.Fatalf("err = %v, want ErrBadPassphrase", err)
+	}
+	assertEmptyVault(t, dst)
+}
+
+func TestImportRejectsTampering(t *testing.T) {
+	_, data := exportTestVault(t)
+
+	tamper := func(edit func(c *exportContainer)) []byte {
+		var c exportContainer
+		if err := json.Unmarshal(data, &c); err != nil {
+			t.Fatal(err)
+		}
+		edit(&c)
+		out, err := json.Marshal(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+	cases := map[string][]byte{
+		"ciphertext": tamper(func(c *exportContainer) { c.Data[len(c.Data)-1] ^= 1 }),
+		// заголовок входит в AAD
+		"header": tamper(func(c *exportContainer) { c.CreatedAt = "2000-01-01T00:00:00Z" }),
+	}
+	for name, tampered := range cases {
+		dst := newTestVault(t)
+		if _, err := dst.Import(bytes.NewReader(tampered), testExportPassphrase); !errors.Is(err, ErrBadPassphrase) {
+			t.Errorf("%s: err = %v, want ErrBadPassphrase", name, err)
+		}
+		assertEmptyVault(t, dst)
+	}
+}
+
+func assertEmptyVault(t *testing.T, s *SQLStorage) {
+	t.Helper()
+	list, err := s.GetFilteredPasswords(model.PasswordFilter{IncludeDeleted: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 0 {
+		t.Errorf("vault has %d entries after a failed import", len(list))
+	}
+}
@@ -0,0 +1,993 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"password-manager/internal/app/model"
+	"password-manager/pkg/security"
+)
+
+// Папки (дерево через parent_id) и теги (многие-ко-многим через password_tags).
+// Имена сравниваются без учёта регистра по name_key. У записи раньше была
+// одна свободная category — теперь это путь её папки, см. MigrateCategories.
+//
+// В запечатанном хранилище (seal_records) открыто не лежит ничего из этого:
+// имя — шифртекст в name_enc, name_key — HMAC имени под ключом хранилища,
+// а папка и теги записи — внутри её sealedRecord (folder_id пуст, строк
+// в password_tags нет). Переключение режима — convertFolders.
+
+const (
+	maxFolderName = 64
+	maxTagName    = 32
+	maxFolderPath = 16 // глубина вложенности
+)
+
+var (
+	ErrFolderNotFound = errors.New("folder not found")
+	ErrFolderExists   = errors.New("a folder with this name already exists here")
+	ErrFolderName     = fmt.Errorf("folder name must be 1-%d characters without %q", maxFolderName, model.FolderSeparator)
+	ErrFolderCycle    = errors.New("a folder cannot be moved or merged into its own subfolder")
+	ErrTagNotFound    = errors.New("tag not found")
+	ErrTagExists      = errors.New("a tag with this name already exists")
+	ErrTagName        = fmt.Errorf("tag name must be 1-%d characters without commas", maxTagName)
+)
+
+// querier — *sql.DB или *sql.Tx
+type querier interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+func nameKey(name string) string {
+	return strings.ToLower(name)
+}
+
+const nameKeyPurpose = "name-key"
+
+// nameKeyMessage: что подписывает HMAC в name_key; таблица входит в него,
+// чтобы одноимённые папка и тег не выдавали друг друга
+func nameKeyMessage(vaultID, table, name string) []byte {
+	return []byte(vaultID + "/" + table + "/" + nameKey(name))
+}
+
+// storedNameKey: значение колонки name_key для имени
+func (s *SQLStorage) storedNameKey(table, name string, sealed bool) (string, error) {
+	if !sealed {
+		return nameKey(name), nil
+	}
+	vault, err := s.vaultID()
+	if err != nil {
+		return "", err
+	}
+	mac, err := s.cryptoService().KeyedHash(nameKeyPurpose, nameKeyMessage(vault, table, name))
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(mac), nil
+}
+
+// nameRow — строка folders или tags с уже расшифрованным именем
+type nameRow struct {
+	id, parent int64 // parent у тегов всегда 0
+	name       string
+	sealed     bool // имя лежит в name_enc
+}
+
+// readNames читает строки таблицы целиком (см. readEncryptedRows) и расшифровывает имена
+func (s *SQLStorage) readNames(q querier, table, where string, args ...any) ([]nameRow, error) {
+	parent := "0"
+	if table == "folders" {
+		parent = "COALESCE(parent_id, 0)"
+	}
+	rows, err := q.Query("SELECT id, "+parent+", name, name_enc, enc_version FROM "+table+" "+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	type raw struct {
+		nameRow
+		enc     sql.NullString
+		version int
+	}
+	var list []raw
+	for rows.Next() {
+		var r raw
+		if err := rows.Scan(&r.id, &r.parent, &r.name, &r.enc, &r.version); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		list = append(list, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	out := make([]nameRow, len(list))
+	for i, r := range list {
+		if r.enc.Valid {
+			vault, err := s.vaultID()
+			if err != nil {
+				return nil, err
+			}
+			if r.name, err = s.cryptoService().Decrypt(r.enc.String, tableAD(vault, table, r.id, fieldName, r.version)); err != nil {
+				return nil, fmt.Errorf("%s id=%d open name: %w", table, r.id, err)
+			}
+			r.sealed = true
+		}
+		out[i] = r.nameRow
+	}
+	return out, nil
+}
+
+// setName записывает имя папки или тега в формате режима sealed
+func (s *SQLStorage) setName(q querier, table string, id int64, name string, sealed bool) error {
+	key, err := s.storedNameKey(table, name, sealed)
+	if err != nil {
+		return err
+	}
+	if !sealed {
+		_, err = q.Exec("UPDATE "+table+" SET name = ?, name_key = ?, name_enc = NULL WHERE id = ?", name, key, id)
+		return err
+	}
+	vault, err := s.vaultID()
+	if err != nil {
+		return err
+	}
+	enc, err := s.cryptoService().Encrypt(name, tableAD(vault, table, id, fieldName, encVersionBound))
+	if err != nil {
+		return err
+	}
+	_, err = q.Exec(
+		"UPDATE "+table+" SET name = '', name_key = ?, name_enc = ?, enc_version = ? WHERE id = ?",
+		key, enc, encVersionBound, id,
+	)
+	return err
+}
+
+// insertName добавляет папку (в parent) или тег; шифртекст привязан к ID,
+// поэтому сначала вставка, потом setName
+func (s *SQLStorage) insertName(q querier, table string, parent int64, name string, sealed bool) (int64, error) {
+	key, err := s.storedNameKey(table, name, sealed)
+	if err != nil {
+		return 0, err
+	}
+	plain := name
+	if sealed {
+		plain = ""
+	}
+	var res sql.Result
+	if table == "folders" {
+		res, err = q.Exec(`INSERT INTO folders (parent_id, name, name_key) VALUES (?, ?, ?)`, nullFolder(parent), plain, key)
+	} else {
+		res, err = q.Exec(`INSERT INTO tags (name, name_key) VALUES (?, ?)`, plain, key)
+	}
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil || !sealed {
+		return id, err
+	}
+	return id, s.setName(q, table, id, name, sealed)
+}
+
+// rekeyNames пересчитывает HMAC в name_key после перешифровки на новый ключ
+func rekeyNames(tx *sql.Tx, vault string, key []byte) error {
+	for _, t := range []*encryptedTable{&foldersTable, &tagsTable} {
+		list, err := readEncryptedRows(tx, t, "WHERE name_enc IS NOT NULL")
+		if err != nil {
+			return err
+		}
+		for _, r := range list {
+			enc, err := base64.StdEncoding.DecodeString(r.optional[0].String)
+			if err != nil {
+				return fmt.Errorf("%s id=%d decode failed: %w", t.name, r.id, err)
+			}
+			name, err := security.OpenAESGCM(key, enc, r.ad(vault, fieldName))
+			if err != nil {
+				return fmt.Errorf("%s id=%d decrypt failed: %w", t.name, r.id, err)
+			}
+			mac := security.KeyedHash(key, nameKeyPurpose, nameKeyMessage(vault, t.name, string(name)))
+			if _, err := tx.Exec("UPDATE "+t.name+" SET name_key = ? WHERE id = ?", hex.EncodeToString(mac), r.id); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// convertFolders переводит имена папок и тегов и принадлежность запечатанных
+// записей в формат режима sealed; уже переведённое не трогается. Открытые
+// записи получают папку и теги из sealedRecord в SetSealRecords.
+func (s *SQLStorage) convertFolders(tx *sql.Tx, sealed bool) error {
+	for _, table := range []string{"folders", "tags"} {
+		list, err := s.readNames(tx, table, "")
+		if err != nil {
+			return err
+		}
+		for _, r := range list {
+			if r.sealed == sealed {
+				continue
+			}
+			if err := s.setName(tx, table, r.id, r.name, sealed); err != nil {
+				return err
+			}
+		}
+	}
+	if !sealed {
+		return nil
+	}
+
+	tags, err := loadEntryTags(tx)
+	if err != nil {
+		return err
+	}
+	folderOf := map[int64]int{}
+	rows, err := tx.Query(`SELECT id, folder_id FROM passwords WHERE record IS NOT NULL AND folder_id IS NOT NULL`)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var id int64
+		var folder int
+		if err := rows.Scan(&id, &folder); err != nil {
+			rows.Close()
+			return err
+		}
+		folderOf[id] = folder
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if _, err := s.rewriteRecords(tx, func(id int64, rec *sealedRecord) bool {
+		folder, ids := folderOf[id], tags[int(id)]
+		if folder == 0 && len(ids) == 0 {
+			return false
+		}
+		if folder != 0 {
+			rec.FolderID = folder
+		}
+		rec.Tags = addTagIDs(rec.Tags, ids...)
+		return true
+	}); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE passwords SET folder_id = NULL WHERE record IS NOT NULL`); err != nil {
+		return err
+	}
+	_, err = tx.Exec(`DELETE FROM password_tags WHERE password_id IN (SELECT id FROM passwords WHERE record IS NOT NULL)`)
+	return err
+}
+
+func cleanFolderName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxFolderName || strings.Contains(name, model.FolderSeparator) {
+		return "", ErrFolderName
+	}
+	return name, nil
+}
+
+// cleanTagName: "#" в начале — не часть имени
+func cleanTagName(name string) (string, error) {
+	name = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(name), "#"))
+	if name == "" || utf8.RuneCountInString(name) > maxTagName || strings.Contains(name, ",") {
+		return "", ErrTagName
+	}
+	return name, nil
+}
+
+// splitFolderPath: "Work / Clients/" → ["Work", "Clients"]; пустой путь — без папки
+func splitFolderPath(path string) ([]string, error) {
+	var parts []string
+	for _, p := range strings.Split(path, model.FolderSeparator) {
+		if strings.TrimSpace(p) == "" {
+			continue
+		}
+		name, err := cleanFolderName(p)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, name)
+	}
+	if len(parts) > maxFolderPath {
+		return nil, fmt.Errorf("%w: more than %d levels", ErrFolderName, maxFolderPath)
+	}
+	return parts, nil
+}
+
+// legacyFolderPath: старая свободная category как путь папки; если путь
+// не проходит проверку — одна папка с обрезанным именем без "/"
+func legacyFolderPath(category string) string {
+	if _, err := splitFolderPath(category); err == nil {
+		return category
+	}
+	name := []rune(strings.TrimSpace(strings.ReplaceAll(category, model.FolderSeparator, "-")))
+	if len(name) > maxFolderName {
+		name = name[:maxFolderName]
+	}
+	return string(name)
+}
+
+// ---------------- Папки ----------------
+
+// loadFolders: все папки с путями (без числа записей, см. entryCounts)
+func (s *SQLStorage) loadFolders(q querier) (map[int]*model.Folder, error) {
+	list, err := s.readNames(q, "folders", "")
+	if err != nil {
+		return nil, err
+	}
+	folders := map[int]*model.Folder{}
+	for _, r := range list {
+		folders[int(r.id)] = &model.Folder{ID: int(r.id), ParentID: int(r.parent), Name: r.name}
+	}
+	for _, f := range folders {
+		f.Path = f.Name
+		for p, depth := folders[f.ParentID], 0; p != nil && depth < maxFolderPath; p, depth = folders[p.ParentID], depth+1 {
+			f.Path = p.Name + model.FolderSeparator + f.Path
+		}
+	}
+	return folders, nil
+}
+
+// entryCounts: число записей вне корзины по папкам и по тегам (ключ — nameKey
+// имени); считается по индексу — в запечатанном хранилище SQL их не видит
+func (s *SQLStorage) entryCounts() (folders map[int]int, tags map[string]int, err error) {
+	list, err := s.indexedPasswords()
+	if err != nil {
+		return nil, nil, err
+	}
+	folders, tags = map[int]int{}, map[string]int{}
+	for _, p := range list {
+		if p.DeletedAt != "" {
+			continue
+		}
+		folders[p.FolderID]++
+		for _, t := range p.Tags {
+			tags[nameKey(t)]++
+		}
+	}
+	return folders, tags, nil
+}
+
+// findFolder ищет папку по пути без учёта регистра
+func findFolder(folders map[int]*model.Folder, path string) (int, bool) {
+	parts, err := splitFolderPath(path)
+	if err != nil || parts == nil {
+		return 0, false
+	}
+	parent := 0
+	for _, name := range parts {
+		found := 0
+		for _, f := range folders {
+			if f.ParentID == parent && nameKey(f.Name) == nameKey(name) {
+				found = f.ID
+				break
+			}
+		}
+		if found == 0 {
+			return 0, false
+		}
+		parent = found
+	}
+	return parent, true
+}
+
+// subtree: папка и все её подпапки
+func subtree(folders map[int]*model.Folder, id int) map[int]bool {
+	ids := map[int]bool{id: true}
+	for changed := true; changed; {
+		changed = false
+		for _, f := range folders {
+			if !ids[f.ID] && ids[f.ParentID] {
+				ids[f.ID] = true
+				changed = true
+			}
+		}
+	}
+	return ids
+}
+
+// GetFolders: все папки, отсортированные по пути
+func (s *SQLStorage) GetFolders() ([]model.Folder, error) {
+	folders, err := s.loadFolders(s.DB)
+	if err != nil {
+		return nil, err
+	}
+	counts, _, err := s.entryCounts()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]model.Folder, 0, len(folders))
+	for _, f := range folders {
+		f.Count = counts[f.ID]
+		list = append(list, *f)
+	}
+	sort.Slice(list, func(i, j int) bool { return nameKey(list[i].Path) < nameKey(list[j].Path) })
+	return list, nil
+}
+
+// folderChild: ID подпапки parent с таким именем (0 — нет)
+func (s *SQLStorage) folderChild(q querier, parent int64, name string, sealed bool) (int64, error) {
+	key, err := s.storedNameKey("folders", name, sealed)
+	if err != nil {
+		return 0, err
+	}
+	var id int64
+	err = q.QueryRow(`SELECT id FROM folders WHERE COALESCE(parent_id, 0) = ? AND name_key = ?`, parent, key).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return id, err
+}
+
+func folderExists(q querier, id int64) error {
+	var n int
+	if err := q.QueryRow(`SELECT COUNT(*) FROM folders WHERE id = ?`, id).Scan(&n); err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrFolderNotFound
+	}
+	return nil
+}
+
+// nullFolder: 0 — верхний уровень / без папки
+func nullFolder(id int64) any {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
+
+// ensureFolderPath возвращает папку по пути, создавая недостающие (0 — путь пуст)
+func (s *SQLStorage) ensureFolderPath(q querier, path string, sealed bool) (int64, error) {
+	parts, err := splitFolderPath(path)
+	if err != nil {
+		return 0, err
+	}
+	var parent int64
+	for _, name := range parts {
+		id, err := s.folderChild(q, parent, name, sealed)
+		if err != nil {
+			return 0, err
+		}
+		if id == 0 {
+			if id, err = s.insertName(q, "folders", parent, name, sealed); err != nil {
+				return 0, err
+			}
+		}
+		parent = id
+	}
+	return parent, nil
+}
+
+// CreateFolder создаёт папку в parentID (0 — на верхнем уровне)
+func (s *SQLStorage) CreateFolder(parentID int, name string) (model.Folder, error) {
+	name, err := cleanFolderName(name)
+	if err != nil {
+		return model.Folder{}, err
+	}
+	if parentID != 0 {
+		if err := folderExists(s.DB, int64(parentID)); err != nil {
+			return model.Folder{}, err
+		}
+	}
+	sealed := s.SealRecords()
+	if id, err := s.folderChild(s.DB, int64(parentID), name, sealed); err != nil {
+		return model.Folder{}, err
+	} else if id != 0 {
+		return model.Folder{}, ErrFolderExists
+	}
+	id, err := s.insertName(s.DB, "folders", int64(parentID), name, sealed)
+	if err != nil {
+		return model.Folder{}, err
+	}
+	folders, err := s.loadFolders(s.DB)
+	if err != nil {
+		return model.Folder{}, err
+	}
+	return *folders[int(id)], nil
+}
+
+// RenameFolder: имя должно быть свободно среди соседних папок
+func (s *SQLStorage) RenameFolder(id int, name string) error {
+	name, err := cleanFolderName(name)
+	if err != nil {
+		return err
+	}
+	var parent int64
+	err = s.DB.QueryRow(`SELECT COALESCE(parent_id, 0) FROM folders WHERE id = ?`, id).Scan(&parent)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrFolderNotFound
+	}
+	if err != nil {
+		return err
+	}
+	sealed := s.SealRecords()
+	if other, err := s.folderChild(s.DB, parent, name, sealed); err != nil {
+		return err
+	} else if other != 0 && other != int64(id) {
+		return ErrFolderExists
+	}
+	defer s.invalidateIndex()
+	return s.setName(s.DB, "folders", int64(id), name, sealed)
+}
+
+// MoveFolder переносит папку со всем содержимым в parentID (0 — на верхний уровень)
+func (s *SQLStorage) MoveFolder(id, parentID int) error {
+	folders, err := s.loadFolders(s.DB)
+	if err != nil {
+		return err
+	}
+	f := folders[id]
+	if f == nil || (parentID != 0 && folders[parentID] == nil) {
+		return ErrFolderNotFound
+	}
+	if parentID != 0 && subtree(folders, id)[parentID] {
+		return ErrFolderCycle
+	}
+	if other, err := s.folderChild(s.DB, int64(parentID), f.Name, s.SealRecords()); err != nil {
+		return err
+	} else if other != 0 && other != int64(id) {
+		return ErrFolderExists
+	}
+	defer s.invalidateIndex()
+	_, err = s.DB.Exec(`UPDATE folders SET parent_id = ? WHERE id = ?`, nullFolder(int64(parentID)), id)
+	return err
+}
+
+// MergeFolders переносит записи и подпапки from в into и удаляет from;
+// одноимённые подпапки сливаются
+func (s *SQLStorage) MergeFolders(from, into int) error {
+	folders, err := s.loadFolders(s.DB)
+	if err != nil {
+		return err
+	}
+	if folders[from] == nil || folders[into] == nil {
+		return ErrFolderNotFound
+	}
+	if subtree(folders, from)[into] {
+		return ErrFolderCycle
+	}
+	return s.mergeFolder(int64(from), int64(into))
+}
+
+// DeleteFolder: записи и подпапки переходят к родительской папке
+func (s *SQLStorage) DeleteFolder(id int) error {
+	var parent int64
+	err := s.DB.QueryRow(`SELECT COALESCE(parent_id, 0) FROM folders WHERE id = ?`, id).Scan(&parent)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrFolderNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return s.mergeFolder(int64(id), parent)
+}
+
+func (s *SQLStorage) mergeFolder(from, into int64) error {
+	sealed := s.SealRecords()
+	defer s.invalidateIndex()
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	moved := map[int]int{}
+	if err := s.mergeFolderTx(tx, from, into, sealed, moved); err != nil {
+		return err
+	}
+	// запечатанные записи хранят папку у себя
+	if _, err := s.rewriteRecords(tx, func(_ int64, rec *sealedRecord) bool {
+		to, ok := moved[rec.FolderID]
+		if ok {
+			rec.FolderID = to
+		}
+		return ok
+	}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// mergeFolderTx: into 0 — верхний уровень; moved собирает from → into
+// (в том числе слитых подпапок) для запечатанных записей
+func (s *SQLStorage) mergeFolderTx(tx *sql.Tx, from, into int64, sealed bool, moved map[int]int) error {
+	moved[int(from)] = int(into)
+	if _, err := tx.Exec(`UPDATE passwords SET folder_id = ? WHERE folder_id = ?`, nullFolder(into), from); err != nil {
+		return err
+	}
+	children, err := s.readNames(tx, "folders", "WHERE parent_id = ?", from)
+	if err != nil {
+		return err
+	}
+	for _, c := range children {
+		same, err := s.folderChild(tx, into, c.name, sealed)
+		if err != nil {
+			return err
+		}
+		if same != 0 {
+			if err := s.mergeFolderTx(tx, c.id, same, sealed, moved); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := tx.Exec(`UPDATE folders SET parent_id = ? WHERE id = ?`, nullFolder(into), c.id); err != nil {
+			return err
+		}
+	}
+	_, err = tx.Exec(`DELETE FROM folders WHERE id = ?`, from)
+	return err
+}
+
+// ---------------- Теги ----------------
+
+// GetTags: все теги по алфавиту с числом записей
+func (s *SQLStorage) GetTags() ([]model.Tag, error) {
+	names, err := s.readNames(s.DB, "tags", "")
+	if err != nil {
+		return nil, err
+	}
+	_, counts, err := s.entryCounts()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]model.Tag, 0, len(names))
+	for _, r := range names {
+		list = append(list, model.Tag{ID: int(r.id), Name: r.name, Count: counts[nameKey(r.name)]})
+	}
+	sort.Slice(list, func(i, j int) bool { return nameKey(list[i].Name) < nameKey(list[j].Name) })
+	return list, nil
+}
+
+func (s *SQLStorage) tagByName(q querier, name string, sealed bool) (int64, error) {
+	key, err := s.storedNameKey("tags", name, sealed)
+	if err != nil {
+		return 0, err
+	}
+	var id int64
+	err = q.QueryRow(`SELECT id FROM tags WHERE name_key = ?`, key).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return id, err
+}
+
+func tagExists(q querier, id int) error {
+	var n int
+	if err := q.QueryRow(`SELECT COUNT(*) FROM tags WHERE id = ?`, id).Scan(&n); err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrTagNotFound
+	}
+	return nil
+}
+
+// tagIDs: ID тегов по именам, недостающие создаются; пустые имена пропускаются
+func (s *SQLStorage) tagIDs(q querier, names []string, sealed bool) ([]int, error) {
+	ids := []int{}
+	for _, name := range names {
+		if strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(name), "#")) == "" {
+			continue
+		}
+		name, err := cleanTagName(name)
+		if err != nil {
+			return nil, err
+		}
+		id, err := s.tagByName(q, name, sealed)
+		if err != nil {
+			return nil, err
+		}
+		if id == 0 {
+			if id, err = s.insertName(q, "tags", 0, name, sealed); err != nil {
+				return nil, err
+			}
+		}
+		ids = addTagIDs(ids, int(id))
+	}
+	return ids, nil
+}
+
+// addTagIDs добавляет к ids недостающие
+func addTagIDs(ids []int, more ...int) []int {
+	for _, id := range more {
+		found := false
+		for _, have := range ids {
+			found = found || have == id
+		}
+		if !found {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// linkTags заменяет теги открытой записи
+func linkTags(q querier, passwordID int64, ids []int) error {
+	if _, err := q.Exec(`DELETE FROM password_tags WHERE password_id = ?`, passwordID); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if _, err := q.Exec(`INSERT OR IGNORE INTO password_tags (password_id, tag_id) VALUES (?, ?)`, passwordID, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadEntryTags: ID тегов открытых записей
+func loadEntryTags(q querier) (map[int][]int, error) {
+	rows, err := q.Query(`SELECT password_id, tag_id FROM password_tags`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	tags := map[int][]int{}
+	for rows.Next() {
+		var id, tag int
+		if err := rows.Scan(&id, &tag); err != nil {
+			return nil, err
+		}
+		tags[id] = append(tags[id], tag)
+	}
+	return tags, rows.Err()
+}
+
+// entryTagIDs: текущие теги записи — из sealedRecord или из password_tags
+func (s *SQLStorage) entryTagIDs(q querier, id int64) ([]int, error) {
+	var record sql.NullString
+	var version int
+	if err := q.QueryRow(`SELECT record, enc_version FROM passwords WHERE id = ?`, id).Scan(&record, &version); err != nil {
+		return nil, err
+	}
+	if record.Valid {
+		rec, err := s.openRecord(id, record.String, version)
+		return rec.Tags, err
+	}
+	tags, err := loadEntryTags(q)
+	return tags[int(id)], err
+}
+
+// RenameTag: если имя занято другим тегом — ErrTagExists (для этого есть MergeTags)
+func (s *SQLStorage) RenameTag(id int, name string) error {
+	name, err := cleanTagName(name)
+	if err != nil {
+		return err
+	}
+	if err := tagExists(s.DB, id); err != nil {
+		return err
+	}
+	sealed := s.SealRecords()
+	if other, err := s.tagByName(s.DB, name, sealed); err != nil {
+		return err
+	} else if other != 0 && other != int64(id) {
+		return ErrTagExists
+	}
+	defer s.invalidateIndex()
+	return s.setName(s.DB, "tags", int64(id), name, sealed)
+}
+
+// MergeTags: записи с тегом from получают into, тег from удаляется
+func (s *SQLStorage) MergeTags(from, into int) error {
+	if from == into {
+		return nil
+	}
+	for _, id := range []int{from, into} {
+		if err := tagExists(s.DB, id); err != nil {
+			return err
+		}
+	}
+	defer s.invalidateIndex()
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(
+		`INSERT OR IGNORE INTO password_tags (password_id, tag_id) SELECT password_id, ? FROM password_tags WHERE tag_id = ?`, into, from,
+	); err != nil {
+		return err
+	}
+	if err := s.deleteTagTx(tx, from, into); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteTag снимает тег со всех записей
+func (s *SQLStorage) DeleteTag(id int) error {
+	if err := tagExists(s.DB, id); err != nil {
+		return err
+	}
+	defer s.invalidateIndex()
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := s.deleteTagTx(tx, id, 0); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// deleteTagTx: в запечатанных записях тег заменяется на into (0 — снимается)
+func (s *SQLStorage) deleteTagTx(tx *sql.Tx, id, into int) error {
+	if _, err := s.rewriteRecords(tx, func(_ int64, rec *sealedRecord) bool {
+		kept, found := []int{}, false
+		for _, t := range rec.Tags {
+			if t == id {
+				found = true
+				continue
+			}
+			kept = append(kept, t)
+		}
+		if found && into != 0 {
+			kept = addTagIDs(kept, into)
+		}
+		rec.Tags = kept
+		return found
+	}); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM password_tags WHERE tag_id = ?`, id); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`DELETE FROM tags WHERE id = ?`, id)
+	return err
+}
+
+// ---------------- Записи ----------------
+
+// attachFolders подставляет путь папки в Category и теги записей;
+// sealedTags — теги из запечатанных записей, см. scanPassword
+func (s *SQLStorage) attachFolders(q querier, list []model.PasswordListItem, sealedTags map[int][]int) error {
+	if len(list) == 0 {
+		return nil
+	}
+	folders, err := s.loadFolders(q)
+	if err != nil {
+		return err
+	}
+	rows, err := s.readNames(q, "tags", "")
+	if err != nil {
+		return err
+	}
+	names := map[int]string{}
+	for _, r := range rows {
+		names[int(r.id)] = r.name
+	}
+	tags, err := loadEntryTags(q)
+	if err != nil {
+		return err
+	}
+	for i := range list {
+		// без папки остаётся старая category, пока не прошла MigrateCategories
+		if f := folders[list[i].FolderID]; f != nil {
+			list[i].Category = f.Path
+		}
+		var entry []string
+		for _, id := range append(tags[list[i].ID], sealedTags[list[i].ID]...) {
+			if name, ok := names[id]; ok {
+				entry = append(entry, name)
+			}
+		}
+		sort.Slice(entry, func(a, b int) bool { return nameKey(entry[a]) < nameKey(entry[b]) })
+		list[i].Tags = entry
+	}
+	return nil
+}
+
+// folderScope: папки, подходящие под f.Category и f.Folder (nil — любые);
+// ok == false — такой папки нет, под фильтр ничего не попадёт
+func (s *SQLStorage) folderScope(f model.PasswordFilter) (ids map[int]bool, ok bool, err error) {
+	if f.Category == "" && f.Folder == "" {
+		return nil, true, nil
+	}
+	folders, err := s.loadFolders(s.DB)
+	if err != nil {
+		return nil, false, err
+	}
+	if f.Category != "" {
+		id, found := findFolder(folders, f.Category)
+		if !found {
+			return nil, false, nil
+		}
+		ids = map[int]bool{id: true}
+	}
+	if f.Folder != "" {
+		id, found := findFolder(folders, f.Folder)
+		if !found {
+			return nil, false, nil
+		}
+		sub := subtree(folders, id)
+		if ids != nil {
+			for id := range ids {
+				if !sub[id] {
+					delete(ids, id)
+				}
+			}
+		} else {
+			ids = sub
+		}
+	}
+	return ids, len(ids) > 0, nil
+}
+
+// MigrateCategories — после разблокировки, пока не отмечено в meta: category
+// записи (открытая или в запечатанной записи) становится папкой, "Work" и
+// "work" — одной; из запечатанных записей старое значение удаляется. Заодно
+// запечатывает папки и теги, созданные открытыми до миграции 15.
+func (s *SQLStorage) MigrateCategories() error {
+	var done bool
+	if err := s.DB.QueryRow(`SELECT categories_migrated FROM meta WHERE id = 1`).Scan(&done); err != nil || done {
+		return err
+	}
+	if err := s.requireCrypto(); err != nil {
+		return err
+	}
+	sealed := s.SealRecords()
+	list, err := s.loadPasswords("WHERE folder_id IS NULL")
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := s.convertFolders(tx, sealed); err != nil {
+		return err
+	}
+	moved := map[int64]int64{}
+	for _, item := range list {
+		if strings.TrimSpace(item.Category) == "" {
+			continue
+		}
+		// "/" в старой категории — такой же разделитель, как в пути папки
+		id, err := s.ensureFolderPath(tx, legacyFolderPath(item.Category), sealed)
+		if err != nil {
+			return fmt.Errorf("id=%d category %q: %w", item.ID, item.Category, err)
+		}
+		moved[int64(item.ID)] = id
+		if _, err := tx.Exec(
+			`UPDATE passwords SET folder_id = ?, category = '' WHERE id = ? AND record IS NULL`, nullFolder(id), item.ID,
+		); err != nil {
+			return err
+		}
+	}
+	if _, err := s.rewriteRecords(tx, func(id int64, rec *sealedRecord) bool {
+		if rec.Category == "" {
+			return false
+		}
+		if folder, ok := moved[id]; ok {
+			rec.FolderID = int(folder)
+		}
+		rec.Category = ""
+		return true
+	}); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE meta SET categories_migrated = 1 WHERE id = 1`); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	s.invalidateIndex()
+	return nil
+}
@@ -0,0 +1,126 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"password-manager/internal/app/model"
+)
+
+// ErrHistoryNotFound: такой версии у этой записи нет
+var ErrHistoryNotFound = errors.New("history entry not found")
+
+// pushHistory сохраняет прежний пароль записи
+func (s *SQLStorage) pushHistory(tx *sql.Tx, passwordID int64, plain string) error {
+	return s.insertHistory(tx, passwordID, plain, time.Now().UTC().Format(time.RFC3339))
+}
+
+// insertHistory: как и в insertPassword, шифртекст привязан к ID строки,
+// поэтому сначала вставка, потом шифрование
+func (s *SQLStorage) insertHistory(tx *sql.Tx, passwordID int64, plain, changedAt string) error {
+	vault, err := s.vaultID()
+	if err != nil {
+		return err
+	}
+	res, err := tx.Exec(
+		`INSERT INTO password_history (password_id, password, enc_version, changed_at) VALUES (?, '', ?, ?)`,
+		passwordID, encVersionBound, changedAt,
+	)
+	if err != nil {
+		return err
+	}
+	hid, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	enc, err := s.cryptoService().Encrypt(plain, tableAD(vault, historyTable.name, hid, fieldPassword, encVersionBound))
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`UPDATE password_history SET password = ? WHERE id = ?`, enc, hid)
+	return err
+}
+
+// GetPasswordHistory: прежние пароли записи, новые сверху (без самих значений)
+func (s *SQLStorage) GetPasswordHistory(id int) ([]model.HistoryItem, error) {
+	rows, err := s.DB.Query(
+		`SELECT id, password_id, changed_at FROM password_history WHERE password_id = ? ORDER BY id DESC`, id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []model.HistoryItem{}
+	for rows.Next() {
+		var h model.HistoryItem
+		if err := rows.Scan(&h.ID, &h.PasswordID, &h.ChangedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, h)
+	}
+	return list, rows.Err()
+}
+
+// DecryptHistoryPassword: прежний пароль hid записи id в открытом виде
+func (s *SQLStorage) DecryptHistoryPassword(id, hid int) (string, error) {
+	if err := s.requireCrypto(); err != nil {
+		return "", err
+	}
+	vault, err := s.vaultID()
+	if err != nil {
+		return "", err
+	}
+	var enc string
+	var version int
+	err = s.DB.QueryRow(
+		`SELECT password, enc_version FROM password_history WHERE id = ? AND password_id = ?`, hid, id,
+	).Scan(&enc, &version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrHistoryNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return s.cryptoService().Decrypt(enc, tableAD(vault, historyTable.name, int64(hid), fieldPassword, version))
+}
+
+// RestorePassword делает версию hid текущим паролем; текущий при этом
+// сам уходит в историю, так что восстановление тоже можно откатить
+func (s *SQLStorage) RestorePassword(id, hid int) error {
+	restored, err := s.DecryptHistoryPassword(id, hid)
+	if err != nil {
+		return err
+	}
+	if err := s.RequireLive(id); err != nil {
+		return err
+	}
+	current, err := s.DecryptPassword(id)
+	if err != nil {
+		return err
+	}
+	if restored == current {
+		return nil
+	}
+	defer s.invalidateIndex()
+	encrypted, err := s.encryptField(int64(id), fieldPassword, restored)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := s.pushHistory(tx, int64(id), current); err != nil {
+		return err
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	if _, err := tx.Exec(`UPDATE passwords SET password = ?, updated_at = ? WHERE id = ?`, encrypted, now, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
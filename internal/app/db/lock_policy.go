@@ -0,0 +1,26 @@
+package db
+
+import "password-manager/internal/app/model"
+
+// LockPolicy: политика автоблокировки хранилища; до создания meta — значения по умолчанию
+func (s *SQLStorage) LockPolicy() model.LockPolicy {
+	var p model.LockPolicy
+	err := s.DB.QueryRow(
+		`SELECT lock_idle_minutes, lock_on_focus_loss, lock_on_suspend, lock_max_session_minutes FROM meta WHERE id = 1`,
+	).Scan(&p.IdleMinutes, &p.OnFocusLoss, &p.OnSuspend, &p.MaxSessionMinutes)
+	if err != nil {
+		return model.DefaultLockPolicy
+	}
+	return p
+}
+
+func (s *SQLStorage) SetLockPolicy(p model.LockPolicy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	_, err := s.DB.Exec(
+		`UPDATE meta SET lock_idle_minutes = ?, lock_on_focus_loss = ?, lock_on_suspend = ?, lock_max_session_minutes = ? WHERE id = 1`,
+		p.IdleMinutes, p.OnFocusLoss, p.OnSuspend, p.MaxSessionMinutes,
+	)
+	return err
+}
@@ -0,0 +1,321 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"password-manager/pkg/utils"
+)
+
+// migration — один шаг схемы. Шаги применяются по возрастанию version,
+// каждый в своей транзакции вместе с записью в schema_version.
+type migration struct {
+	version int
+	name    string
+	up      func(tx *sql.Tx) error
+}
+
+// Новые шаги добавлять только в конец, уже выпущенные не менять
+var migrations = []migration{
+	{1, "create passwords and meta", func(tx *sql.Tx) error {
+		// Таблица паролей: хранится base64(AES-GCM)
+		if _, err := tx.Exec(`CREATE TABLE IF NOT EXISTS passwords (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			service TEXT NOT NULL,
+			username TEXT NOT NULL,
+			link TEXT NOT NULL,
+			password TEXT NOT NULL,    -- base64(nonce||ciphertext)
+			category TEXT NOT NULL,
+			created_at TEXT NOT NULL
+		)`); err != nil {
+			return err
+		}
+		// Соль и верификатор мастер-пароля: одна запись id=1
+		_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS meta (
+			id INTEGER PRIMARY KEY CHECK (id = 1),
+			salt TEXT NOT NULL,
+			verifier TEXT NOT NULL
+		)`)
+		return err
+	}},
+	{2, "drop unused master_password table", func(tx *sql.Tx) error {
+		_, err := tx.Exec(`DROP TABLE IF EXISTS master_password`)
+		return err
+	}},
+	{3, "store kdf parameters in meta", func(tx *sql.Tx) error {
+		// Существующие хранилища выведены PBKDF2-SHA256 с 200k итераций
+		for _, col := range []struct{ name, decl string }{
+			{"kdf_algorithm", "TEXT NOT NULL DEFAULT 'pbkdf2-sha256'"},
+			{"kdf_iterations", "INTEGER NOT NULL DEFAULT 200000"},
+			{"kdf_memory", "INTEGER NOT NULL DEFAULT 0"},
+			{"kdf_parallelism", "INTEGER NOT NULL DEFAULT 0"},
+		} {
+			if err := addColumn(tx, "meta", col.name, col.decl); err != nil {
+				return err
+			}
+		}
+		return nil
+	}},
+	{4, "add wrapped vault key to meta", func(tx *sql.Tx) error {
+		// NULL — записи ещё зашифрованы ключом из пароля, обёртка появится при разблокировке
+		return addColumn(tx, "meta", "wrapped_key", "TEXT")
+	}},
+	{5, "add sealed record column", func(tx *sql.Tx) error {
+		// record: base64(AES-GCM(JSON метаданных)), NULL — метаданные открыты
+		if err := addColumn(tx, "passwords", "record", "TEXT"); err != nil {
+			return err
+		}
+		return addColumn(tx, "meta", "seal_records", "INTEGER NOT NULL DEFAULT 0")
+	}},
+	{6, "add vault id and ciphertext format version", func(tx *sql.Tx) error {
+		// enc_version 0 — шифртексты без привязки к строке; перешифровка
+		// в текущий формат происходит при первой разблокировке (нужен ключ)
+		if err := addColumn(tx, "passwords", "enc_version", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+			return err
+		}
+		if err := addColumn(tx, "meta", "vault_id", "TEXT"); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`UPDATE meta SET vault_id = lower(hex(randomblob(16))) WHERE vault_id IS NULL`)
+		return err
+	}},
+	{7, "add totp secret column", func(tx *sql.Tx) error {
+		// base64(AES-GCM(otpauth URI)), NULL — у записи нет 2FA
+		return addColumn(tx, "passwords", "totp_secret", "TEXT")
+	}},
+	{8, "add notes and custom fields column", func(tx *sql.Tx) error {
+		// base64(AES-GCM(JSON {notes, fields})), NULL — нет ни заметок, ни полей
+		return addColumn(tx, "passwords", "details", "TEXT")
+	}},
+	{9, "create password history", func(tx *sql.Tx) error {
+		// Прежние пароли записи; шифртекст привязан к строке истории
+		if _, err := tx.Exec(`CREATE TABLE IF NOT EXISTS password_history (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			password_id INTEGER NOT NULL,
+			password TEXT NOT NULL,    -- base64(nonce||ciphertext)
+			enc_version INTEGER NOT NULL,
+			changed_at TEXT NOT NULL
+		)`); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_password_history_password_id ON password_history (password_id)`)
+		return err
+	}},
+	{10, "add trash", func(tx *sql.Tx) error {
+		// deleted_at: запись в корзине; через trash_retention_days удаляется совсем (0 — никогда)
+		if err := addColumn(tx, "passwords", "deleted_at", "TEXT"); err != nil {
+			return err
+		}
+		return addColumn(tx, "meta", "trash_retention_days", "INTEGER NOT NULL DEFAULT 30")
+	}},
+	{11, "add auto-lock policy to meta", func(tx *sql.Tx) error {
+		// По умолчанию — прежние 2 минуты бездействия плюс блокировка при сне системы
+		for _, col := range []struct{ name, decl string }{
+			{"lock_idle_minutes", "INTEGER NOT NULL DEFAULT 2"},
+			{"lock_on_minimize", "INTEGER NOT NULL DEFAULT 0"},
+			{"lock_on_suspend", "INTEGER NOT NULL DEFAULT 1"},
+			{"lock_max_session_minutes", "INTEGER NOT NULL DEFAULT 0"},
+		} {
+			if err := addColumn(tx, "meta", col.name, col.decl); err != nil {
+				return err
+			}
+		}
+		return nil
+	}},
+	{12, "add clipboard clear timeout to meta", func(tx *sql.Tx) error {
+		// Раньше было 15 с в GUI и 10 с в REST — оставляем 15
+		return addColumn(tx, "meta", "clipboard_clear_seconds", "INTEGER NOT NULL DEFAULT 15")
+	}},
+	{13, "add password updated_at", func(tx *sql.Tx) error {
+		// updated_at — когда пароль записи последний раз менялся; для старых
+		// записей это последняя смена в истории, а без истории — создание
+		if err := addColumn(tx, "passwords", "updated_at", "TEXT"); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`UPDATE passwords SET updated_at = COALESCE(
+			(SELECT MAX(changed_at) FROM password_history h WHERE h.password_id = passwords.id),
+			created_at
+		) WHERE updated_at IS NULL`)
+		return err
+	}},
+	{14, "create folders and tags", func(tx *sql.Tx) error {
+		// name_key — имя в нижнем регистре, уникальность по нему: "Work" и
+		// "work" — одна папка. Старые category переносятся в папки после
+		// разблокировки (запечатанные без ключа не прочитать), см. folders.go
+		for _, stmt := range []string{
+			`CREATE TABLE IF NOT EXISTS folders (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				parent_id INTEGER,    -- NULL — верхний уровень
+				name TEXT NOT NULL,
+				name_key TEXT NOT NULL
+			)`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS idx_folders_parent_name ON folders (COALESCE(parent_id, 0), name_key)`,
+			`CREATE TABLE IF NOT EXISTS tags (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				name TEXT NOT NULL,
+				name_key TEXT NOT NULL UNIQUE
+			)`,
+			`CREATE TABLE IF NOT EXISTS password_tags (
+				password_id INTEGER NOT NULL,
+				tag_id INTEGER NOT NULL,
+				PRIMARY KEY (password_id, tag_id)
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_password_tags_tag_id ON password_tags (tag_id)`,
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		if err := addColumn(tx, "passwords", "folder_id", "INTEGER"); err != nil {
+			return err
+		}
+		return addColumn(tx, "meta", "categories_migrated", "INTEGER NOT NULL DEFAULT 0")
+	}},
+	{15, "seal folder and tag names", func(tx *sql.Tx) error {
+		// В запечатанном хранилище имя лежит в name_enc, а name_key — HMAC.
+		// Шаг после разблокировки повторяется: он же запечатает папки и
+		// теги, созданные до этой миграции открытыми, см. MigrateCategories
+		for _, table := range []string{"folders", "tags"} {
+			if err := addColumn(tx, table, "name_enc", "TEXT"); err != nil {
+				return err
+			}
+			if err := addColumn(tx, table, "enc_version", "INTEGER NOT NULL DEFAULT 1"); err != nil {
+				return err
+			}
+		}
+		_, err := tx.Exec(`UPDATE meta SET categories_migrated = 0 WHERE id = 1`)
+		return err
+	}},
+	{16, "rename lock_on_minimize to lock_on_focus_loss", func(tx *sql.Tx) error {
+		// GUI видит только потерю фокуса, сворачивание от неё не отличить
+		_, err := tx.Exec(`ALTER TABLE meta RENAME COLUMN lock_on_minimize TO lock_on_focus_loss`)
+		return err
+	}},
+}
+
+// addColumn — ALTER TABLE ADD COLUMN, пропускающий уже существующую колонку
+func addColumn(tx *sql.Tx, table, column, decl string) error {
+	var exists int
+	if err := tx.QueryRow(
+		`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, column,
+	).Scan(&exists); err != nil {
+		return err
+	}
+	if exists > 0 {
+		return nil
+	}
+	_, err := tx.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, decl))
+	return err
+}
+
+// MigrateOptions управляет запуском миграций
+type MigrateOptions struct {
+	// DryRun: только вернуть список ожидающих шагов, ничего не менять
+	DryRun bool
+	// BackupPath: если задан и есть что применять — копия БД через VACUUM INTO
+	BackupPath string
+}
+
+func ensureSchemaVersion(db *sql.DB) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_version (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TEXT NOT NULL
+	)`)
+	return err
+}
+
+// SchemaVersion возвращает номер последней применённой миграции (0 — ни одной).
+// Ничего не создаёт, поэтому годится и для dry-run.
+func SchemaVersion(db *sql.DB) (int, error) {
+	var exists int
+	if err := db.QueryRow(
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_version'`,
+	).Scan(&exists); err != nil || exists == 0 {
+		return 0, err
+	}
+	var v int
+	err := db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_version`).Scan(&v)
+	return v, err
+}
+
+func pendingMigrations(current int) []migration {
+	var pending []migration
+	for _, m := range migrations {
+		if m.version > current {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+func migrationNames(list []migration) []string {
+	names := make([]string, len(list))
+	for i, m := range list {
+		names[i] = fmt.Sprintf("%03d %s", m.version, m.name)
+	}
+	return names
+}
+
+// Migrate применяет ожидающие миграции по порядку и возвращает их описания.
+// В режиме DryRun возвращает то же описание, но БД не трогает.
+func Migrate(db *sql.DB, opts MigrateOptions) ([]string, error) {
+	current, err := SchemaVersion(db)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := pendingMigrations(current)
+	if len(pending) == 0 {
+		return nil, nil
+	}
+	names := migrationNames(pending)
+	if opts.DryRun {
+		return names, nil
+	}
+
+	if opts.BackupPath != "" {
+		// копия хранилища не должна быть доступна всем, как при umask 022:
+		// VACUUM INTO пишет в пустой файл, созданный заранее с правами 0600
+		f, err := utils.CreatePrivateFile(opts.BackupPath)
+		if err != nil {
+			return nil, fmt.Errorf("backup before migration: %w", err)
+		}
+		f.Close()
+		if _, err := db.Exec(`VACUUM INTO ?`, opts.BackupPath); err != nil {
+			return nil, fmt.Errorf("backup before migration: %w", err)
+		}
+		log.Printf("schema v%d backed up to %s", current, opts.BackupPath)
+	}
+
+	if err := ensureSchemaVersion(db); err != nil {
+		return nil, err
+	}
+	for _, m := range pending {
+		if err := applyMigration(db, m); err != nil {
+			return nil, fmt.Errorf("migration %03d %s: %w", m.version, m.name, err)
+		}
+	}
+	return names, nil
+}
+
+func applyMigration(db *sql.DB, m migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := m.up(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec(
+		`INSERT INTO schema_version (version, name, applied_at) VALUES (?, ?, ?)`,
+		m.version, m.name, time.Now().UTC().Format(time.RFC3339),
+	); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
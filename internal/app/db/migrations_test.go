@@ -0,0 +1,121 @@
+package db
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// baselineDB: файл со схемой до появления миграций — passwords и meta без
+// schema_version, как их создавала самая первая версия
+func baselineDB(t *testing.T) (*sql.DB, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "vault.db")
+	conn, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	for _, stmt := range []string{
+		`CREATE TABLE passwords (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			service TEXT NOT NULL,
+			username TEXT NOT NULL,
+			link TEXT NOT NULL,
+			password TEXT NOT NULL,
+			category TEXT NOT NULL,
+			created_at TEXT NOT NULL
+		)`,
+		`CREATE TABLE meta (
+			id INTEGER PRIMARY KEY CHECK (id = 1),
+			salt TEXT NOT NULL,
+			verifier TEXT NOT NULL
+		)`,
+		`INSERT INTO passwords (service, username, link, password, category, created_at)
+			VALUES ('gh', 'u', '', 'c2VjcmV0', '', '2024-01-01T00:00:00Z')`,
+	} {
+		if _, err := conn.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return conn, path
+}
+
+func latestVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+func TestMigrateDryRun(t *testing.T) {
+	conn, _ := baselineDB(t)
+
+	names, err := Migrate(conn, MigrateOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != len(migrations) {
+		t.Fatalf("dry run lists %d steps, want %d", len(names), len(migrations))
+	}
+	// dry-run ничего не создаёт, даже schema_version
+	if v, err := SchemaVersion(conn); err != nil || v != 0 {
+		t.Fatalf("schema version after dry run = %d, %v; want 0", v, err)
+	}
+	var n int
+	if err := conn.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'schema_version'`).Scan(&n); err != nil || n != 0 {
+		t.Fatalf("schema_version table exists after dry run (%d, %v)", n, err)
+	}
+}
+
+func TestMigrateBaseline(t *testing.T) {
+	conn, path := baselineDB(t)
+	backup := path + ".bak"
+
+	names, err := Migrate(conn, MigrateOptions{BackupPath: backup})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != len(migrations) {
+		t.Fatalf("applied %d steps, want %d", len(names), len(migrations))
+	}
+	if v, err := SchemaVersion(conn); err != nil || v != latestVersion() {
+		t.Fatalf("schema version = %d, %v; want %d", v, err, latestVersion())
+	}
+	var rows int
+	if err := conn.QueryRow(`SELECT COUNT(*) FROM schema_version`).Scan(&rows); err != nil || rows != len(migrations) {
+		t.Fatalf("schema_version has %d rows, %v; want %d", rows, err, len(migrations))
+	}
+	var service string
+	if err := conn.QueryRow(`SELECT service FROM passwords WHERE id = 1`).Scan(&service); err != nil || service != "gh" {
+		t.Fatalf("existing entry after migration: %q, %v", service, err)
+	}
+
+	// копия — до миграций и только для владельца
+	info, err := os.Stat(backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" {
+		if mode := info.Mode().Perm(); mode != 0o600 {
+			t.Errorf("backup mode = %o, want 600", mode)
+		}
+	}
+	bak, err := sql.Open("sqlite3", backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bak.Close()
+	if v, err := SchemaVersion(bak); err != nil || v != 0 {
+		t.Errorf("backup schema version = %d, %v; want 0", v, err)
+	}
+
+	// повторный запуск — ничего не ожидает и копию не делает
+	os.Remove(backup)
+	names, err = Migrate(conn, MigrateOptions{BackupPath: backup})
+	if err != nil || len(names) != 0 {
+		t.Fatalf("second run = %v, %v; want nothing to apply", names, err)
+	}
+	if _, err := os.Stat(backup); !os.IsNotExist(err) {
+		t.Errorf("backup written although nothing was pending")
+	}
+}
@@ -0,0 +1,203 @@
+package db
+
+import (
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"password-manager/internal/app/model"
+)
+
+// Экспорт БЕЗ шифрования — для аудита и переезда. Колонки и структура
+// как у Bitwarden, так что файл читают и Bitwarden, и наш импорт.
+// Корзина и история паролей не выгружаются.
+
+const (
+	PlainCSV  = "csv"
+	PlainJSON = "json"
+)
+
+var ErrPlainFormat = fmt.Errorf("plain export format must be %q or %q", PlainCSV, PlainJSON)
+
+var bitwardenCSVHeader = []string{
+	"folder", "favorite", "type", "name", "notes", "fields", "reprompt",
+	"login_uri", "login_username", "login_password", "login_totp",
+}
+
+type plainEntry struct {
+	model.PasswordListItem
+	password string
+	totp     string
+	details  model.EntryDetails
+}
+
+// ExportPlain пишет расшифрованные записи в w в формате PlainCSV или PlainJSON.
+// Мастер-пароль проверяет вызывающий (см. App.VerifyMasterPassword).
+func (s *SQLStorage) ExportPlain(w io.Writer, format string) error {
+	if format != PlainCSV && format != PlainJSON {
+		return ErrPlainFormat
+	}
+	if err := s.requireCrypto(); err != nil {
+		return err
+	}
+	entries, err := s.plainEntries()
+	if err != nil {
+		return err
+	}
+	if format == PlainCSV {
+		return writeBitwardenCSV(w, entries)
+	}
+	return writeBitwardenJSON(w, entries)
+}
+
+func (s *SQLStorage) plainEntries() ([]plainEntry, error) {
+	list, err := s.GetAllPasswords()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]plainEntry, 0, len(list))
+	for _, item := range list {
+		e := plainEntry{PasswordListItem: item}
+		if e.password, err = s.DecryptPassword(item.ID); err != nil {
+			return nil, fmt.Errorf("id=%d: %w", item.ID, err)
+		}
+		if item.HasTOTP {
+			t, err := s.GetTOTP(item.ID)
+			if err != nil {
+				return nil, fmt.Errorf("id=%d totp: %w", item.ID, err)
+			}
+			e.totp = t.URI()
+		}
+		if item.HasDetails {
+			if e.details, err = s.GetDetails(item.ID); err != nil {
+				return nil, fmt.Errorf("id=%d details: %w", item.ID, err)
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func writeBitwardenCSV(w io.Writer, entries []plainEntry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(bitwardenCSVHeader); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		// поля у Bitwarden в CSV — строки "имя: значение"
+		var fields []string
+		for _, f := range e.details.Fields {
+			fields = append(fields, f.Name+": "+f.Value)
+		}
+		if err := cw.Write([]string{
+			e.Category, "", "login", e.Service, e.details.Notes, strings.Join(fields, "\n"), "0",
+			e.Link, e.Username, e.password, e.totp,
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+type bitwardenFolder struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type bitwardenField struct {
+	Name     string  `json:"name"`
+	Value    string  `json:"value"`
+	Type     int     `json:"type"` // 0 — текст, 1 — скрытое
+	LinkedID *string `json:"linkedId"`
+}
+
+type bitwardenURI struct {
+	Match *int   `json:"match"`
+	URI   string `json:"uri"`
+}
+
+type bitwardenLogin struct {
+	URIs     []bitwardenURI `json:"uris"`
+	Username string         `json:"username"`
+	Password string         `json:"password"`
+	TOTP     *string        `json:"totp"`
+}
+
+type bitwardenItem struct {
+	ID             string           `json:"id"`
+	OrganizationID *string          `json:"organizationId"`
+	FolderID       *string          `json:"folderId"`
+	Type           int              `json:"type"` // 1 — логин
+	Reprompt       int              `json:"reprompt"`
+	Name           string           `json:"name"`
+	Notes          *string          `json:"notes"`
+	Favorite       bool             `json:"favorite"`
+	Fields         []bitwardenField `json:"fields,omitempty"`
+	Login          bitwardenLogin   `json:"login"`
+	CollectionIDs  []string         `json:"collectionIds"`
+	CreationDate   string           `json:"creationDate,omitempty"`
+}
+
+type bitwardenExport struct {
+	Encrypted bool              `json:"encrypted"`
+	Folders   []bitwardenFolder `json:"folders"`
+	Items     []bitwardenItem   `json:"items"`
+}
+
+func writeBitwardenJSON(w io.Writer, entries []plainEntry) error {
+	exp := bitwardenExport{Folders: []bitwardenFolder{}, Items: []bitwardenItem{}}
+	folderIDs := map[string]string{}
+	for _, e := range entries {
+		item := bitwardenItem{
+			ID:           strconv.Itoa(e.ID),
+			Type:         1,
+			Name:         e.Service,
+			Login:        bitwardenLogin{Username: e.Username, Password: e.password},
+			CreationDate: e.CreatedAt,
+		}
+		if e.Category != "" {
+			id, ok := folderIDs[e.Category]
+			if !ok {
+				id = newUUID()
+				folderIDs[e.Category] = id
+				exp.Folders = append(exp.Folders, bitwardenFolder{ID: id, Name: e.Category})
+			}
+			item.FolderID = &id
+		}
+		if e.Link != "" {
+			item.Login.URIs = []bitwardenURI{{URI: e.Link}}
+		}
+		if e.totp != "" {
+			item.Login.TOTP = &e.totp
+		}
+		if e.details.Notes != "" {
+			item.Notes = &e.details.Notes
+		}
+		for _, f := range e.details.Fields {
+			kind := 0
+			if f.Type == model.FieldHidden {
+				kind = 1
+			}
+			item.Fields = append(item.Fields, bitwardenField{Name: f.Name, Value: f.Value, Type: kind})
+		}
+		exp.Items = append(exp.Items, item)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(exp)
+}
+
+// newUUID: случайный UUID v4 для папок Bitwarden
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
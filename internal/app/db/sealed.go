@@ -0,0 +1,254 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"password-manager/internal/app/model"
+)
+
+// sealedRecord — то, что при включённом seal_records лежит в passwords.record
+// одним AES-GCM блоком вместо открытых колонок service/username/link, folder_id
+// и строк password_tags. Сам пароль по-прежнему хранится отдельным
+// шифртекстом в колонке password.
+type sealedRecord struct {
+	Service  string `json:"service"`
+	Username string `json:"username"`
+	Link     string `json:"link"`
+	// старая свободная category: есть только до MigrateCategories
+	Category string `json:"category,omitempty"`
+	FolderID int    `json:"folder_id,omitempty"`
+	Tags     []int  `json:"tags,omitempty"` // ID из tags
+}
+
+func (s *SQLStorage) sealRecord(id int64, r sealedRecord) (string, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	return s.encryptField(id, fieldRecord, string(data))
+}
+
+func (s *SQLStorage) openRecord(id int64, encB64 string, version int) (sealedRecord, error) {
+	var r sealedRecord
+	plain, err := s.decryptField(id, fieldRecord, encB64, version)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal([]byte(plain), &r)
+	return r, err
+}
+
+// rewriteRecords перезапечатывает записи, которые fn изменила (вернула true),
+// и возвращает их число. Строки старого формата к этому моменту уже
+// перешифрованы ResealLegacyRows.
+func (s *SQLStorage) rewriteRecords(tx *sql.Tx, fn func(id int64, rec *sealedRecord) bool) (int, error) {
+	rows, err := tx.Query(`SELECT id, record FROM passwords WHERE record IS NOT NULL AND enc_version = ?`, encVersionBound)
+	if err != nil {
+		return 0, err
+	}
+	type row struct {
+		id     int64
+		record string
+	}
+	var list []row
+	for rows.Next() {
+		var r row
+		if err := rows.Scan(&r.id, &r.record); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		list = append(list, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, r := range list {
+		rec, err := s.openRecord(r.id, r.record, encVersionBound)
+		if err != nil {
+			return 0, fmt.Errorf("id=%d open record: %w", r.id, err)
+		}
+		if !fn(r.id, &rec) {
+			continue
+		}
+		enc, err := s.sealRecord(r.id, rec)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec(`UPDATE passwords SET record = ? WHERE id = ?`, enc, r.id); err != nil {
+			return 0, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// SealRecords: включено ли шифрование метаданных для этого хранилища
+func (s *SQLStorage) SealRecords() bool {
+	var enabled bool
+	if err := s.DB.QueryRow(`SELECT seal_records FROM meta WHERE id = 1`).Scan(&enabled); err != nil {
+		return false
+	}
+	return enabled
+}
+
+// SetSealRecords включает/выключает шифрование метаданных и сразу
+// переводит все записи в нужный формат — одной транзакцией
+func (s *SQLStorage) SetSealRecords(enabled bool) error {
+	if err := s.requireCrypto(); err != nil {
+		return err
+	}
+	if _, err := s.vaultID(); err != nil {
+		return err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE meta SET seal_records = ? WHERE id = 1`, enabled); err != nil {
+		return err
+	}
+	// Ниже всё в текущем формате, поэтому старые строки — сначала
+	if _, err := s.resealRows(tx); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(`SELECT id, service, username, link, category, record FROM passwords`)
+	if err != nil {
+		return err
+	}
+	type row struct {
+		id     int64
+		rec    sealedRecord
+		record sql.NullString
+	}
+	var list []row
+	for rows.Next() {
+		var r row
+		if err := rows.Scan(&r.id, &r.rec.Service, &r.rec.Username, &r.rec.Link, &r.rec.Category, &r.record); err != nil {
+			rows.Close()
+			return err
+		}
+		list = append(list, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, r := range list {
+		sealed := r.record.Valid
+		switch {
+		case enabled && !sealed:
+			enc, err := s.sealRecord(r.id, r.rec)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Exec(
+				`UPDATE passwords SET service = '', username = '', link = '', category = '', record = ? WHERE id = ?`,
+				enc, r.id,
+			); err != nil {
+				return err
+			}
+		case !enabled && sealed:
+			rec, err := s.openRecord(r.id, r.record.String, encVersionBound)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Exec(
+				`UPDATE passwords SET service = ?, username = ?, link = ?, category = ?, folder_id = ?, record = NULL WHERE id = ?`,
+				rec.Service, rec.Username, rec.Link, rec.Category, nullFolder(int64(rec.FolderID)), r.id,
+			); err != nil {
+				return err
+			}
+			if err := linkTags(tx, r.id, rec.Tags); err != nil {
+				return err
+			}
+		}
+	}
+	// папка и теги только что запечатанных записей переезжают в record,
+	// имена папок и тегов — в name_enc (или обратно)
+	if err := s.convertFolders(tx, enabled); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	s.invalidateIndex()
+	return nil
+}
+
+// ---------------- In-memory index ----------------
+
+// Расшифрованные записи держатся в памяти и перестраиваются после
+// любого изменения — SQL LIKE по зашифрованным колонкам не работает.
+
+func (s *SQLStorage) invalidateIndex() {
+	s.indexMu.Lock()
+	s.index, s.search, s.totp = nil, nil, nil
+	s.indexMu.Unlock()
+}
+
+func (s *SQLStorage) indexedPasswords() ([]model.PasswordListItem, error) {
+	s.indexMu.Lock()
+	defer s.indexMu.Unlock()
+	if s.index == nil {
+		list, err := s.loadPasswords("")
+		if err != nil {
+			return nil, err
+		}
+		if list == nil {
+			list = []model.PasswordListItem{}
+		}
+		s.index = list
+	}
+	out := make([]model.PasswordListItem, len(s.index))
+	copy(out, s.index)
+	return out, nil
+}
+
+// filterPasswords повторяет семантику SQL-фильтра: подстрока без учёта
+// регистра для service/username, папка из folders (см. folderScope; nil —
+// любая), тег без учёта регистра, записи из корзины — только с IncludeDeleted
+func filterPasswords(list []model.PasswordListItem, f model.PasswordFilter, folders map[int]bool) []model.PasswordListItem {
+	service, username := strings.ToLower(f.Service), strings.ToLower(f.Username)
+	tag := nameKey(strings.TrimPrefix(strings.TrimSpace(f.Tag), "#"))
+	out := []model.PasswordListItem{}
+	for _, p := range list {
+		if p.DeletedAt != "" && !f.IncludeDeleted {
+			continue
+		}
+		if service != "" && !strings.Contains(strings.ToLower(p.Service), service) {
+			continue
+		}
+		if username != "" && !strings.Contains(strings.ToLower(p.Username), username) {
+			continue
+		}
+		if folders != nil && !folders[p.FolderID] {
+			continue
+		}
+		if tag != "" && !hasTag(p.Tags, tag) {
+			continue
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+func hasTag(tags []string, key string) bool {
+	for _, t := range tags {
+		if nameKey(t) == key {
+			return true
+		}
+	}
+	return false
+}
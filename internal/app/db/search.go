@@ -0,0 +1,86 @@
+package db
+
+import (
+	"sort"
+	"strings"
+
+	"password-manager/internal/app/model"
+	"password-manager/pkg/utils"
+)
+
+// Полнотекстовый нечёткий поиск (см. utils.FuzzyDoc). Заметки зашифрованы,
+// поэтому документы собираются в памяти после разблокировки и сбрасываются
+// вместе с индексом записей (invalidateIndex) — после любого изменения и
+// при блокировке.
+
+// Веса полей: совпадение в имени сервиса важнее, чем в заметках
+const (
+	searchWeightService  = 5
+	searchWeightTags     = 4
+	searchWeightUsername = 3
+	searchWeightLink     = 2
+	searchWeightNotes    = 1
+)
+
+func (s *SQLStorage) searchDocs() (map[int]*utils.FuzzyDoc, error) {
+	// как и indexedPasswords: сборка под замком, чтобы invalidateIndex
+	// не потерялся посреди неё
+	s.indexMu.Lock()
+	defer s.indexMu.Unlock()
+	if s.search != nil {
+		return s.search, nil
+	}
+	if err := s.requireCrypto(); err != nil {
+		return nil, err
+	}
+
+	list, err := s.loadPasswords("")
+	if err != nil {
+		return nil, err
+	}
+	docs := make(map[int]*utils.FuzzyDoc, len(list))
+	for _, item := range list {
+		doc := &utils.FuzzyDoc{}
+		doc.Add(item.Service, searchWeightService)
+		doc.Add(strings.Join(item.Tags, " "), searchWeightTags)
+		doc.Add(item.Username, searchWeightUsername)
+		doc.Add(item.Link, searchWeightLink)
+		if item.HasDetails {
+			d, err := s.GetDetails(item.ID)
+			if err != nil {
+				return nil, err
+			}
+			doc.Add(d.Notes, searchWeightNotes)
+		}
+		docs[item.ID] = doc
+	}
+	s.search = docs
+	return docs, nil
+}
+
+// rankByQuery оставляет записи, подходящие под запрос, лучшие — сверху
+func (s *SQLStorage) rankByQuery(list []model.PasswordListItem, query string) ([]model.PasswordListItem, error) {
+	terms := utils.FuzzyTerms(query)
+	if len(terms) == 0 {
+		return list, nil
+	}
+	docs, err := s.searchDocs()
+	if err != nil {
+		return nil, err
+	}
+
+	scores := map[int]int{}
+	out := []model.PasswordListItem{}
+	for _, item := range list {
+		doc := docs[item.ID]
+		if doc == nil {
+			continue
+		}
+		if score := doc.Score(terms); score > 0 {
+			scores[item.ID] = score
+			out = append(out, item)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return scores[out[i].ID] > scores[out[j].ID] })
+	return out, nil
+}
@@ -4,9 +4,12 @@ import (
     "crypto/sha256"
     "database/sql"
     "encoding/base64"
-    "errors"
     "fmt"
     "log"
+    "strconv"
+    "strings"
+    "sync"
+    "sync/atomic"
     "time"
 
     "password-manager/internal/app/model"
@@ -18,12 +21,27 @@ import (
 // type Storage interface { ... }
 
 type SQLStorage struct {
-    DB     *sql.DB
-    Crypto *utils.CryptoService
+    DB *sql.DB
+    // nil — заблокировано; SetCrypto вызывается из других горутин
+    crypto atomic.Pointer[utils.CryptoService]
+
+    // расшифрованный индекс записей (nil — устарел), см. sealed.go;
+    // документы нечёткого поиска и TOTP-ключи сбрасываются вместе с ним,
+    // см. search.go и totp.go
+    indexMu sync.Mutex
+    index   []model.PasswordListItem
+    search  map[int]*utils.FuzzyDoc
+    totp    map[int]*security.TOTP
+
+    // кэш meta.vault_id, см. aad.go
+    vaultMu sync.Mutex
+    vault   string
 }
 
 func NewSQLStorage(db *sql.DB, crypto *utils.CryptoService) Storage {
-    return &SQLStorage{DB: db, Crypto: crypto}
+    s := &SQLStorage{DB: db}
+    s.crypto.Store(crypto)
+    return s
 }
 
 // Реализация новых методов интерфейса
@@ -34,144 +52,376 @@ func (s *SQLStorage) HasMeta() bool {
 }
 
 func (s *SQLStorage) SetCrypto(c *utils.CryptoService) {
-    s.Crypto = c
+    s.crypto.Store(c)
+    s.invalidateIndex()
+}
+
+// cryptoService: снимок текущего ключа; после блокировки снимок отвечает ErrLocked
+func (s *SQLStorage) cryptoService() *utils.CryptoService {
+    return s.crypto.Load()
 }
 
 // ---------------- Generic guards ----------------
 
 func (s *SQLStorage) requireCrypto() error {
-    if s.Crypto == nil {
-        return errors.New("locked: master password not verified or set")
+    if s.cryptoService() == nil {
+        return utils.ErrLocked
     }
     return nil
 }
 
 // ---------------- Passwords ----------------
 
+// p.Password — открытый пароль: шифруется здесь, с привязкой к ID новой строки
 func (s *SQLStorage) CreatePassword(p model.Password) (int64, string, error) {
     if err := s.requireCrypto(); err != nil {
         return 0, "", err
     }
+    if _, err := s.vaultID(); err != nil {
+        return 0, "", err
+    }
 
     createdAt := time.Now().UTC().Format(time.RFC3339)
+    sealed := s.SealRecords()
+    defer s.invalidateIndex()
 
-    res, err := s.DB.Exec(
-        "INSERT INTO passwords (service, username, link, password, category, created_at) VALUES (?, ?, ?, ?, ?, ?)",
-        p.Service, p.Username, p.Link, p.Password, p.Category, createdAt,
-    )
+    tx, err := s.DB.Begin()
     if err != nil {
         return 0, "", err
     }
+    defer tx.Rollback()
 
-    newID, _ := res.LastInsertId()
+    newID, err := s.insertPassword(tx, p, createdAt, sealed)
+    if err != nil {
+        return 0, "", err
+    }
+    if err := tx.Commit(); err != nil {
+        return 0, "", err
+    }
     return newID, createdAt, nil
 }
 
+// CreatePasswords: пакетная вставка (импорт). Ошибка на любой записи
+// откатывает все — повторный импорт не плодит дубликатов.
+func (s *SQLStorage) CreatePasswords(list []model.Password) (int, error) {
+    if err := s.requireCrypto(); err != nil {
+        return 0, err
+    }
+    if _, err := s.vaultID(); err != nil {
+        return 0, err
+    }
+
+    createdAt := time.Now().UTC().Format(time.RFC3339)
+    sealed := s.SealRecords()
+    defer s.invalidateIndex()
+
+    tx, err := s.DB.Begin()
+    if err != nil {
+        return 0, err
+    }
+    defer tx.Rollback()
+
+    for i, p := range list {
+        if _, err := s.insertPassword(tx, p, createdAt, sealed); err != nil {
+            return 0, fmt.Errorf("entry %d (%s): %w", i+1, p.Service, err)
+        }
+    }
+    if err := tx.Commit(); err != nil {
+        return 0, err
+    }
+    return len(list), nil
+}
+
+// insertPassword: вставка записи внутри открытой транзакции (vault_id уже назначен).
+// Шифртекст привязан к ID, поэтому сначала вставка, потом шифрование.
+func (s *SQLStorage) insertPassword(tx *sql.Tx, p model.Password, createdAt string, sealed bool) (int64, error) {
+    meta := p
+    if sealed {
+        meta = model.Password{}
+    }
+    // category теперь путь папки (см. folders.go), сама колонка не заполняется
+    folderID, err := s.ensureFolderPath(tx, p.Category, sealed)
+    if err != nil {
+        return 0, err
+    }
+    tags, err := s.tagIDs(tx, p.Tags, sealed)
+    if err != nil {
+        return 0, err
+    }
+    folder := nullFolder(folderID)
+    if sealed {
+        folder = nil
+    }
+    res, err := tx.Exec(
+        "INSERT INTO passwords (service, username, link, password, category, folder_id, created_at, updated_at, enc_version) VALUES (?, ?, ?, '', '', ?, ?, ?, ?)",
+        meta.Service, meta.Username, meta.Link, folder, createdAt, createdAt, encVersionBound,
+    )
+    if err != nil {
+        return 0, err
+    }
+    newID, err := res.LastInsertId()
+    if err != nil {
+        return 0, err
+    }
+
+    encrypted, err := s.encryptField(newID, fieldPassword, p.Password)
+    if err != nil {
+        return 0, err
+    }
+    var record any
+    if sealed {
+        rec := sealedRecord{Service: p.Service, Username: p.Username, Link: p.Link, FolderID: int(folderID), Tags: tags}
+        if record, err = s.sealRecord(newID, rec); err != nil {
+            return 0, err
+        }
+    }
+    var totp any
+    if p.TOTPSecret != nil {
+        if totp, err = s.sealTOTP(newID, *p.TOTPSecret); err != nil {
+            return 0, err
+        }
+    }
+    details, err := s.detailsFor(newID, p, nil)
+    if err != nil {
+        return 0, err
+    }
+    if _, err := tx.Exec(
+        "UPDATE passwords SET password = ?, record = ?, totp_secret = ?, details = ? WHERE id = ?",
+        encrypted, record, totp, details, newID,
+    ); err != nil {
+        return 0, err
+    }
+    if !sealed {
+        if err := linkTags(tx, newID, tags); err != nil {
+            return 0, err
+        }
+    }
+    return newID, nil
+}
+
+// p.Password — открытый пароль, как и в CreatePassword.
+// Прежний пароль, если он меняется, сохраняется в password_history.
 func (s *SQLStorage) UpdatePassword(id string, p model.Password) error {
     if err := s.requireCrypto(); err != nil {
         return err
     }
-    encrypted, err := s.Crypto.Encrypt(p.Password)
+    defer s.invalidateIndex()
+
+    rowID, err := strconv.ParseInt(id, 10, 64)
+    if err != nil {
+        return err
+    }
+    // запись в корзине не правится — сначала её нужно восстановить
+    if err := s.RequireLive(int(rowID)); err != nil {
+        return err
+    }
+    previous, err := s.DecryptPassword(int(rowID))
+    if err != nil {
+        return err
+    }
+    encrypted, err := s.encryptField(rowID, fieldPassword, p.Password)
     if err != nil {
         return err
     }
-    _, err = s.DB.Exec(
-        "UPDATE passwords SET service = ?, username = ?, link = ?, password = ?, category = ? WHERE id = ?",
-        p.Service, p.Username, p.Link, encrypted, p.Category, id,
-    )
-    return err
-}
 
-func (s *SQLStorage) DeletePassword(id string) error {
-    _, err := s.DB.Exec("DELETE FROM passwords WHERE id = ?", id)
-    return err
+    // TOTP меняется, только если передан (nil — оставить как есть)
+    set, args := "", []any{}
+    if p.TOTPSecret != nil {
+        totp, err := s.sealTOTP(rowID, *p.TOTPSecret)
+        if err != nil {
+            return err
+        }
+        set, args = ", totp_secret = ?", append(args, totp)
+    }
+    // Заметки/поля/правила генератора — аналогично, недостающее берётся из текущей записи
+    if p.Notes != nil || p.Fields != nil || p.Generator != nil {
+        existing, err := s.GetDetails(int(rowID))
+        if err != nil {
+            return err
+        }
+        details, err := s.detailsFor(rowID, p, &existing)
+        if err != nil {
+            return err
+        }
+        set, args = set+", details = ?", append(args, details)
+    }
+    // updated_at — возраст пароля, правка остальных полей его не сбрасывает
+    if previous != p.Password {
+        set, args = set+", updated_at = ?", append(args, time.Now().UTC().Format(time.RFC3339))
+    }
+
+    sealed := s.SealRecords()
+    tx, err := s.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    if previous != p.Password {
+        if err := s.pushHistory(tx, rowID, previous); err != nil {
+            return err
+        }
+    }
+    folderID, err := s.ensureFolderPath(tx, p.Category, sealed)
+    if err != nil {
+        return err
+    }
+    // Tags == nil — теги не меняются
+    var tags []int
+    if p.Tags != nil {
+        tags, err = s.tagIDs(tx, p.Tags, sealed)
+    } else {
+        tags, err = s.entryTagIDs(tx, rowID)
+    }
+    if err != nil {
+        return err
+    }
+
+    query := "UPDATE passwords SET service = ?, username = ?, link = ?, password = ?, category = '', folder_id = ?, record = NULL, enc_version = ?" + set + " WHERE id = ?"
+    params := append(append([]any{p.Service, p.Username, p.Link, encrypted, nullFolder(folderID), encVersionBound}, args...), rowID)
+    if sealed {
+        rec := sealedRecord{Service: p.Service, Username: p.Username, Link: p.Link, FolderID: int(folderID), Tags: tags}
+        record, err := s.sealRecord(rowID, rec)
+        if err != nil {
+            return err
+        }
+        query = "UPDATE passwords SET service = '', username = '', link = '', password = ?, category = '', folder_id = NULL, record = ?, enc_version = ?" + set + " WHERE id = ?"
+        params = append(append([]any{encrypted, record, encVersionBound}, args...), rowID)
+    }
+    if _, err := tx.Exec(query, params...); err != nil {
+        return err
+    }
+    if !sealed {
+        if err := linkTags(tx, rowID, tags); err != nil {
+            return err
+        }
+    }
+    return tx.Commit()
 }
 
 func (s *SQLStorage) GetAllPasswords() ([]model.PasswordListItem, error) {
-    rows, err := s.DB.Query("SELECT id, service, username, link, category, created_at, password FROM passwords")
+    return s.GetFilteredPasswords(model.PasswordFilter{})
+}
+
+// loadPasswords читает записи, расшифровывая запечатанные
+func (s *SQLStorage) loadPasswords(where string, args ...any) ([]model.PasswordListItem, error) {
+    rows, err := s.DB.Query("SELECT "+passwordColumns+" FROM passwords "+where, args...)
     if err != nil {
         return nil, err
     }
     defer rows.Close()
 
     var list []model.PasswordListItem
+    sealedTags := map[int][]int{}
     for rows.Next() {
-        var item model.PasswordListItem
-        var encrypted string
-        if err := rows.Scan(&item.ID, &item.Service, &item.Username, &item.Link, &item.Category, &item.CreatedAt, &encrypted); err != nil {
+        item, tags, err := s.scanPassword(rows)
+        if err != nil {
             return nil, err
         }
+        list = append(list, item)
+        sealedTags[item.ID] = tags
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+    rows.Close()
+    return list, s.attachFolders(s.DB, list, sealedTags)
+}
 
-        // IMPORTANT: do not leak encrypted base64 into display Password field
-        item.Password = "" // leave empty so UI doesn't show encrypted nonsense
+// Колонки в порядке, который ожидает scanPassword
+const passwordColumns = "id, service, username, link, category, created_at, COALESCE(updated_at, created_at), record, enc_version, totp_secret IS NOT NULL, details IS NOT NULL, COALESCE(deleted_at, ''), COALESCE(folder_id, 0)"
 
-        list = append(list, item)
+type rowScanner interface {
+    Scan(dest ...any) error
+}
+
+// scanPassword: см. passwordColumns; tags — ID тегов запечатанной записи
+// (у открытой они в password_tags), имена подставит attachFolders
+func (s *SQLStorage) scanPassword(r rowScanner) (item model.PasswordListItem, tags []int, err error) {
+    var record sql.NullString
+    var version int
+    if err := r.Scan(&item.ID, &item.Service, &item.Username, &item.Link, &item.Category, &item.CreatedAt, &item.UpdatedAt, &record, &version, &item.HasTOTP, &item.HasDetails, &item.DeletedAt, &item.FolderID); err != nil {
+        return item, nil, err
     }
-    return list, nil
+    if record.Valid {
+        rec, err := s.openRecord(int64(item.ID), record.String, version)
+        if err != nil {
+            return item, nil, fmt.Errorf("id=%d open record: %w", item.ID, err)
+        }
+        // rec.Category — до MigrateCategories, потом путь папки подставит attachFolders
+        item.Service, item.Username, item.Link, item.Category = rec.Service, rec.Username, rec.Link, rec.Category
+        item.FolderID, tags = rec.FolderID, rec.Tags
+    }
+
+    // IMPORTANT: do not leak encrypted base64 into display Password field
+    item.Password = "" // leave empty so UI doesn't show encrypted nonsense
+    return item, tags, nil
 }
 
-func (s *SQLStorage) GetFilteredPasswords(service, username, category string) ([]model.PasswordListItem, error) {
-    query := "SELECT id, service, username, link, category, created_at, password FROM passwords WHERE 1=1"
+// GetFilteredPasswords: записи в корзине возвращаются только с f.IncludeDeleted
+func (s *SQLStorage) GetFilteredPasswords(f model.PasswordFilter) ([]model.PasswordListItem, error) {
+    scope, ok, err := s.folderScope(f)
+    if err != nil {
+        return nil, err
+    }
+    if !ok {
+        return []model.PasswordListItem{}, nil
+    }
+    // Запечатанные колонки пусты — фильтруем по расшифрованному индексу
+    if s.SealRecords() {
+        list, err := s.indexedPasswords()
+        if err != nil {
+            return nil, err
+        }
+        return s.rankByQuery(filterPasswords(list, f, scope), f.Query)
+    }
+
+    query := "WHERE 1=1"
     var args []interface{}
-    if service != "" {
+    if !f.IncludeDeleted {
+        query += " AND deleted_at IS NULL"
+    }
+    if f.Service != "" {
         query += " AND service LIKE ?"
-        args = append(args, "%"+service+"%")
+        args = append(args, "%"+f.Service+"%")
     }
-    if username != "" {
+    if f.Username != "" {
         query += " AND username LIKE ?"
-        args = append(args, "%"+username+"%")
+        args = append(args, "%"+f.Username+"%")
     }
-    if category != "" {
-        query += " AND category = ?"
-        args = append(args, category)
+    if scope != nil {
+        ids := make([]string, 0, len(scope))
+        for id := range scope {
+            ids = append(ids, strconv.Itoa(id))
+        }
+        query += " AND folder_id IN (" + strings.Join(ids, ", ") + ")"
     }
-
-    rows, err := s.DB.Query(query, args...)
+    if f.Tag != "" {
+        query += " AND id IN (SELECT pt.password_id FROM password_tags pt JOIN tags t ON t.id = pt.tag_id WHERE t.name_key = ?)"
+        args = append(args, nameKey(strings.TrimPrefix(strings.TrimSpace(f.Tag), "#")))
+    }
+    list, err := s.loadPasswords(query, args...)
     if err != nil {
         return nil, err
     }
-    defer rows.Close()
-
-    var list []model.PasswordListItem
-    for rows.Next() {
-        var item model.PasswordListItem
-        var encrypted string
-        if err := rows.Scan(
-            &item.ID,
-            &item.Service,
-            &item.Username,
-            &item.Link,
-            &item.Category,
-            &item.CreatedAt,
-            &encrypted,
-        ); err != nil {
-            return nil, err
-        }
-
-        // Do not set encrypted value into item.Password
-        item.Password = ""
-
-        list = append(list, item)
+    if list == nil {
+        list = []model.PasswordListItem{}
     }
-    return list, nil
+    return s.rankByQuery(list, f.Query)
 }
 
 // Только метаданные (без пароля), если нужно
 func (s *SQLStorage) GetPasswordByID(id string) (model.PasswordListItem, error) {
-    var p model.PasswordListItem
-    err := s.DB.QueryRow("SELECT id, service, username, link, category, created_at FROM passwords WHERE id = ?", id).
-        Scan(&p.ID, &p.Service, &p.Username, &p.Link, &p.Category, &p.CreatedAt)
-    return p, err
-}
-
-// Для копирования: всегда берём актуальный шифртекст из БД
-func (s *SQLStorage) GetEncryptedPasswordByID(id int) (string, error) {
-    var enc string
-    err := s.DB.QueryRow("SELECT password FROM passwords WHERE id = ?", id).Scan(&enc)
+    row := s.DB.QueryRow("SELECT "+passwordColumns+" FROM passwords WHERE id = ?", id)
+    item, tags, err := s.scanPassword(row)
     if err != nil {
-        return "", err
+        return item, err
     }
-    return enc, nil
+    list := []model.PasswordListItem{item}
+    if err := s.attachFolders(s.DB, list, map[int][]int{item.ID: tags}); err != nil {
+        return item, err
+    }
+    return list[0], nil
 }
 
 // ---------------- Meta: соль и верификатор ----------------
@@ -185,52 +435,99 @@ func EnsureMeta(db *sql.DB) error {
     return err
 }
 
+// LoadOrInitMasterFromDB проверяет мастер-пароль и возвращает ключ хранилища
+// (случайный, хранится в meta обёрнутым ключом из пароля).
+// Заодно при необходимости переводит хранилище на envelope-шифрование
+// и поднимает параметры KDF.
 func LoadOrInitMasterFromDB(db *sql.DB, masterPassword string) ([]byte, error) {
     if err := EnsureMeta(db); err != nil {
         return nil, err
     }
 
-    var saltB64, verB64 string
-    err := db.QueryRow("SELECT salt, verifier FROM meta WHERE id=1").Scan(&saltB64, &verB64)
+    rec, err := security.LoadMasterRecord(db)
     if err == sql.ErrNoRows {
-        salt := security.GenerateSalt(16)
-        key := security.DeriveKey([]byte(masterPassword), salt)
-        ver := sha256.Sum256(key)
-
-        _, err = db.Exec(
-            "INSERT INTO meta (id, salt, verifier) VALUES (1, ?, ?)",
-            base64.StdEncoding.EncodeToString(salt),
-            base64.StdEncoding.EncodeToString(ver[:]),
-        )
+        // Новое хранилище: Argon2id и случайный ключ хранилища
+        dataKey, err := security.NewDataKey()
+        if err != nil {
+            return nil, err
+        }
+        rec, _, err := security.NewMasterRecord(masterPassword, security.DefaultKDFParams(), dataKey)
         if err != nil {
             return nil, err
         }
-        log.Printf("DEBUG: set salt=%s", base64.StdEncoding.EncodeToString(salt))
-        log.Printf("DEBUG: set key hash=%x", ver)
-        return key, nil
+        if err := security.SaveMasterRecord(db, rec); err != nil {
+            return nil, err
+        }
+        return dataKey, nil
     }
     if err != nil {
         return nil, err
     }
 
-    salt, err := base64.StdEncoding.DecodeString(saltB64)
+    kek, err := rec.Verify(masterPassword)
     if err != nil {
         return nil, err
     }
-    expectedVer, err := base64.StdEncoding.DecodeString(verB64)
+
+    dataKey, err := rec.DataKey(kek)
     if err != nil {
         return nil, err
     }
 
-    key := security.DeriveKey([]byte(masterPassword), salt)
-    actualVer := sha256.Sum256(key)
-    if !security.BytesEqual(actualVer[:], expectedVer) {
-        return nil, errors.New("invalid master password")
+    target := security.DefaultKDFParams()
+    if dataKey != nil && !rec.KDF.WeakerThan(target) {
+        return dataKey, nil
     }
 
-    log.Printf("DEBUG: verify salt=%s", saltB64)
-    log.Printf("DEBUG: verify key hash=%x", actualVer)
-    return key, nil
+    // Пароль верный — можно перевыпустить meta: завести ключ хранилища
+    // (перешифровав записи) и/или усилить KDF
+    upgraded, err := upgradeMaster(db, rec, kek, dataKey, masterPassword, target)
+    if err != nil {
+        log.Printf("master upgrade failed: %v", err)
+        if dataKey == nil {
+            return kek, nil
+        }
+        return dataKey, nil
+    }
+    log.Printf("master upgraded: kdf=%s, vault key wrapped", target.Algorithm)
+    return upgraded, nil
+}
+
+// upgradeMaster: одной транзакцией перешифровывает записи на новый ключ
+// хранилища (если его ещё не было) и сохраняет meta с параметрами target
+func upgradeMaster(db *sql.DB, rec *security.MasterRecord, kek, dataKey []byte, masterPassword string, target security.KDFParams) ([]byte, error) {
+    params := rec.KDF
+    if params.WeakerThan(target) {
+        params = target
+    }
+
+    tx, err := db.Begin()
+    if err != nil {
+        return nil, err
+    }
+    if dataKey == nil {
+        if dataKey, err = security.NewDataKey(); err != nil {
+            tx.Rollback()
+            return nil, err
+        }
+        if err := reencryptRows(tx, kek, dataKey); err != nil {
+            tx.Rollback()
+            return nil, err
+        }
+    }
+    next, _, err := security.NewMasterRecord(masterPassword, params, dataKey)
+    if err != nil {
+        tx.Rollback()
+        return nil, err
+    }
+    if err := security.SaveMasterRecord(tx, next); err != nil {
+        tx.Rollback()
+        return nil, err
+    }
+    if err := tx.Commit(); err != nil {
+        return nil, err
+    }
+    return dataKey, nil
 }
 
 func UpdateVerifier(db *sql.DB, key []byte) error {
@@ -240,55 +537,117 @@ func UpdateVerifier(db *sql.DB, key []byte) error {
 }
 
 func ReencryptAll(db *sql.DB, oldKey, newKey []byte) error {
-    rows, err := db.Query("SELECT id, password FROM passwords")
+    tx, err := db.Begin()
     if err != nil {
         return err
     }
-    defer rows.Close()
+    if err := reencryptRows(tx, oldKey, newKey); err != nil {
+        tx.Rollback()
+        return err
+    }
+    return tx.Commit()
+}
 
-    tx, err := db.Begin()
+// reencryptRows: перешифровка всех записей новым ключом.
+// Привязка (AAD) и версия формата каждой строки сохраняются.
+func reencryptRows(tx *sql.Tx, oldKey, newKey []byte) error {
+    vault, err := readVaultID(tx)
     if err != nil {
         return err
     }
-
-    for rows.Next() {
-        var id int
-        var encB64 string
-        if err := rows.Scan(&id, &encB64); err != nil {
-            tx.Rollback()
-            return err
-        }
-
-        enc, err := base64.StdEncoding.DecodeString(encB64)
+    for _, t := range encryptedTables {
+        list, err := readEncryptedRows(tx, t, "")
         if err != nil {
-            tx.Rollback()
             return err
         }
-
-        pt, err := security.DecryptAESGCM(oldKey, enc)
-        if err != nil {
-            tx.Rollback()
-            return fmt.Errorf("id=%d decrypt failed: %w", id, err)
+        for _, r := range list {
+            err := r.transform(func(field, encB64 string) (string, error) {
+                enc, err := base64.StdEncoding.DecodeString(encB64)
+                if err != nil {
+                    return "", fmt.Errorf("%s id=%d decode failed: %w", t.name, r.id, err)
+                }
+                ad := r.ad(vault, field)
+                pt, err := security.OpenAESGCM(oldKey, enc, ad)
+                if err != nil {
+                    return "", fmt.Errorf("%s id=%d decrypt failed: %w", t.name, r.id, err)
+                }
+                newEnc, err := security.SealAESGCM(newKey, pt, ad)
+                if err != nil {
+                    return "", fmt.Errorf("%s id=%d encrypt failed: %w", t.name, r.id, err)
+                }
+                return base64.StdEncoding.EncodeToString(newEnc), nil
+            })
+            if err != nil {
+                return err
+            }
+            if err := r.write(tx, r.version); err != nil {
+                return err
+            }
         }
+    }
+    // HMAC имён зависит от ключа — пересчитать
+    return rekeyNames(tx, vault, newKey)
+}
 
-        newEnc, err := security.EncryptAESGCM(newKey, pt)
-        if err != nil {
-            tx.Rollback()
-            return fmt.Errorf("id=%d encrypt failed: %w", id, err)
-        }
+// DecryptError: записи, которые не удалось расшифровать текущим ключом
+type DecryptError struct {
+    IDs []int
+}
+
+func (e *DecryptError) Error() string {
+    return fmt.Sprintf("%d entries failed to decrypt: ids %v", len(e.IDs), e.IDs)
+}
+
+// ChangeMasterPassword: проверка старого пароля, перевыпуск meta
+// и проверка всех записей — одной транзакцией. Если хоть одна запись не
+// расшифровывается ключом crypto, ничего не меняется и возвращается *DecryptError.
+func ChangeMasterPassword(db *sql.DB, crypto *utils.CryptoService, oldPassword, newPassword string) error {
+    tx, err := db.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
 
-        _, err = tx.Exec("UPDATE passwords SET password=? WHERE id=?", base64.StdEncoding.EncodeToString(newEnc), id)
+    // Сначала старый пароль, затем записи: откат отменит и новую обёртку
+    if err := security.ChangeMasterPassword(tx, oldPassword, newPassword); err != nil {
+        return err
+    }
+    if err := verifyRows(tx, crypto); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
+
+func verifyRows(tx *sql.Tx, crypto *utils.CryptoService) error {
+    vault, err := readVaultID(tx)
+    if err != nil {
+        return err
+    }
+    // ID записей passwords; сбой в истории приписывается её записи
+    var failed []int
+    seen := map[int64]bool{}
+    for _, t := range encryptedTables {
+        list, err := readEncryptedRows(tx, t, "")
         if err != nil {
-            tx.Rollback()
             return err
         }
+        for _, r := range list {
+            err := r.transform(func(field, enc string) (string, error) {
+                return crypto.Decrypt(enc, r.ad(vault, field))
+            })
+            if err != nil && t.owner == "0" {
+                return fmt.Errorf("%s id=%d: %w", t.name, r.id, err)
+            }
+            if err != nil && !seen[r.owner] {
+                seen[r.owner] = true
+                failed = append(failed, int(r.owner))
+            }
+        }
     }
-    if err := rows.Err(); err != nil {
-        tx.Rollback()
-        return err
+    if len(failed) > 0 {
+        return &DecryptError{IDs: failed}
     }
-
-    return tx.Commit()
+    return nil
 }
 
 func (s *SQLStorage) Close() error {
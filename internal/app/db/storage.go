@@ -1,7 +1,11 @@
 package db
 
 import (
+    "io"
+    "time"
+
     "password-manager/internal/app/model"
+    "password-manager/pkg/security"
     "password-manager/pkg/utils"
 )
 
@@ -10,13 +14,57 @@ type Storage interface {
     GetAllPasswords() ([]model.PasswordListItem, error)
     GetPasswordByID(id string) (model.PasswordListItem, error)
     CreatePassword(p model.Password) (int64, string, error)
+    CreatePasswords(list []model.Password) (int, error) // все или ничего, одной транзакцией
     UpdatePassword(id string, p model.Password) error
-    DeletePassword(id string) error
-    GetFilteredPasswords(service, username, category string) ([]model.PasswordListItem, error)
-    GetEncryptedPasswordByID(id int) (string, error)
+    DeletePassword(id string) error // переносит в корзину
+    GetFilteredPasswords(f model.PasswordFilter) ([]model.PasswordListItem, error)
+    DecryptPassword(id int) (string, error)
+    GetTOTP(id int) (*security.TOTP, error)
+    GetDetails(id int) (model.EntryDetails, error)
+    RequireLive(id int) error // ErrInTrash для записи в корзине
+
+    // История паролей
+    GetPasswordHistory(id int) ([]model.HistoryItem, error)
+    DecryptHistoryPassword(id, hid int) (string, error)
+    RestorePassword(id, hid int) error
+
+    // Папки и теги (см. folders.go); ID 0 у папки — верхний уровень
+    GetFolders() ([]model.Folder, error)
+    CreateFolder(parentID int, name string) (model.Folder, error)
+    RenameFolder(id int, name string) error
+    MoveFolder(id, parentID int) error
+    MergeFolders(from, into int) error
+    DeleteFolder(id int) error // записи и подпапки переходят к родителю
+    GetTags() ([]model.Tag, error)
+    RenameTag(id int, name string) error
+    MergeTags(from, into int) error
+    DeleteTag(id int) error
+
+    // Корзина
+    GetTrash() ([]model.PasswordListItem, error)
+    RestoreFromTrash(id string) error
+    PurgePassword(id string) error
+    PurgeExpiredTrash() (int, error)
+    TrashRetentionDays() int
+    SetTrashRetentionDays(days int) error
+
+    // Зашифрованный экспорт/импорт (см. export.go)
+    Export(w io.Writer, passphrase string) error
+    Import(r io.Reader, passphrase string) (int, error)
+    ExportPlain(w io.Writer, format string) error // без шифрования, см. plain_export.go
     Close() error
 
     // Meta (единый источник истины)
     HasMeta() bool
     SetCrypto(*utils.CryptoService)
+
+    // Шифрование метаданных записей целиком
+    SealRecords() bool
+    SetSealRecords(enabled bool) error
+
+    // Политика автоблокировки (применяет приложение, см. app.AutoLock)
+    LockPolicy() model.LockPolicy
+    SetLockPolicy(p model.LockPolicy) error
+    ClipboardTimeout() time.Duration // очистка буфера после копирования секрета
+    SetClipboardTimeout(d time.Duration) error
 }
@@ -0,0 +1,62 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+
+	"password-manager/pkg/security"
+)
+
+// ErrNoTOTP: у записи не задан секрет 2FA
+var ErrNoTOTP = errors.New("entry has no TOTP secret")
+
+// sealTOTP проверяет секрет (otpauth:// URI или base32) и шифрует его
+// каноническую форму. Пустая строка — NULL, т.е. секрет удаляется.
+func (s *SQLStorage) sealTOTP(id int64, secret string) (any, error) {
+	if secret == "" {
+		return nil, nil
+	}
+	t, err := security.ParseTOTP(secret)
+	if err != nil {
+		return nil, err
+	}
+	return s.encryptField(id, fieldTOTP, t.URI())
+}
+
+// GetTOTP расшифровывает генератор кодов записи. Ключ каждой записи
+// расшифровывается и разбирается один раз: живые коды в GUI запрашиваются
+// каждую секунду. Кэш сбрасывается вместе с индексом (invalidateIndex) —
+// при изменении записей и при блокировке.
+func (s *SQLStorage) GetTOTP(id int) (*security.TOTP, error) {
+	s.indexMu.Lock()
+	defer s.indexMu.Unlock()
+	if t := s.totp[id]; t != nil {
+		return t, nil
+	}
+	t, err := s.loadTOTP(id)
+	if err != nil {
+		return nil, err
+	}
+	if s.totp == nil {
+		s.totp = map[int]*security.TOTP{}
+	}
+	s.totp[id] = t
+	return t, nil
+}
+
+func (s *SQLStorage) loadTOTP(id int) (*security.TOTP, error) {
+	var enc sql.NullString
+	var version int
+	err := s.DB.QueryRow("SELECT totp_secret, enc_version FROM passwords WHERE id = ?", id).Scan(&enc, &version)
+	if err != nil {
+		return nil, err
+	}
+	if !enc.Valid {
+		return nil, ErrNoTOTP
+	}
+	uri, err := s.decryptField(int64(id), fieldTOTP, enc.String, version)
+	if err != nil {
+		return nil, err
+	}
+	return security.ParseTOTP(uri)
+}
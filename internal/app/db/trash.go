@@ -0,0 +1,158 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"password-manager/internal/app/model"
+)
+
+// ErrNotInTrash: записи нет или она не в корзине
+var ErrNotInTrash = errors.New("entry is not in the trash")
+
+// ErrInTrash: запись в корзине — её можно только восстановить или удалить насовсем
+var ErrInTrash = errors.New("entry is in the trash")
+
+// RequireLive: sql.ErrNoRows, если записи нет, и ErrInTrash, если она в корзине
+func (s *SQLStorage) RequireLive(id int) error {
+	var deleted sql.NullString
+	if err := s.DB.QueryRow(`SELECT deleted_at FROM passwords WHERE id = ?`, id).Scan(&deleted); err != nil {
+		return err
+	}
+	if deleted.Valid {
+		return ErrInTrash
+	}
+	return nil
+}
+
+// DeletePassword переносит запись в корзину; заодно чистит корзину от просроченного
+func (s *SQLStorage) DeletePassword(id string) error {
+	defer s.invalidateIndex()
+	res, err := s.DB.Exec(
+		`UPDATE passwords SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`,
+		time.Now().UTC().Format(time.RFC3339), id,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	if _, err := s.PurgeExpiredTrash(); err != nil {
+		log.Printf("purge trash: %v", err)
+	}
+	return nil
+}
+
+// GetTrash: записи в корзине, недавно удалённые сверху
+func (s *SQLStorage) GetTrash() ([]model.PasswordListItem, error) {
+	list, err := s.GetFilteredPasswords(model.PasswordFilter{IncludeDeleted: true})
+	if err != nil {
+		return nil, err
+	}
+	trash := []model.PasswordListItem{}
+	for _, p := range list {
+		if p.DeletedAt != "" {
+			trash = append(trash, p)
+		}
+	}
+	// RFC3339 в UTC сравнивается как строка
+	sort.SliceStable(trash, func(i, j int) bool { return trash[i].DeletedAt > trash[j].DeletedAt })
+	return trash, nil
+}
+
+func (s *SQLStorage) RestoreFromTrash(id string) error {
+	defer s.invalidateIndex()
+	res, err := s.DB.Exec(`UPDATE passwords SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotInTrash
+	}
+	return nil
+}
+
+// PurgePassword удаляет запись из корзины насовсем, вместе с историей паролей
+func (s *SQLStorage) PurgePassword(id string) error {
+	defer s.invalidateIndex()
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("DELETE FROM passwords WHERE id = ? AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotInTrash
+	}
+	if _, err := tx.Exec("DELETE FROM password_history WHERE password_id = ?", id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM password_tags WHERE password_id = ?", id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// PurgeExpiredTrash удаляет записи, пролежавшие в корзине дольше срока хранения
+func (s *SQLStorage) PurgeExpiredTrash() (int, error) {
+	days := s.TrashRetentionDays()
+	if days <= 0 {
+		return 0, nil
+	}
+	cutoff := time.Now().UTC().AddDate(0, 0, -days).Format(time.RFC3339)
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"password_history", "password_tags"} {
+		if _, err := tx.Exec(
+			`DELETE FROM `+table+` WHERE password_id IN
+				(SELECT id FROM passwords WHERE deleted_at IS NOT NULL AND deleted_at < ?)`, cutoff,
+		); err != nil {
+			return 0, err
+		}
+	}
+	res, err := tx.Exec(`DELETE FROM passwords WHERE deleted_at IS NOT NULL AND deleted_at < ?`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	n, _ := res.RowsAffected()
+	if n > 0 {
+		s.invalidateIndex()
+	}
+	return int(n), nil
+}
+
+// TrashRetentionDays: сколько дней хранить удалённые записи (0 — бессрочно)
+func (s *SQLStorage) TrashRetentionDays() int {
+	var days int
+	if err := s.DB.QueryRow(`SELECT trash_retention_days FROM meta WHERE id = 1`).Scan(&days); err != nil {
+		return 0
+	}
+	return days
+}
+
+func (s *SQLStorage) SetTrashRetentionDays(days int) error {
+	if days < 0 {
+		return errors.New("retention must not be negative")
+	}
+	if _, err := s.DB.Exec(`UPDATE meta SET trash_retention_days = ? WHERE id = 1`, days); err != nil {
+		return err
+	}
+	_, err := s.PurgeExpiredTrash()
+	return err
+}
@@ -0,0 +1,101 @@
+package endpoint
+
+import (
+    "bytes"
+    "errors"
+    "net/http"
+    "time"
+
+    "password-manager/internal/app/db"
+    "password-manager/internal/app/model"
+    "password-manager/pkg/security"
+    "password-manager/pkg/utils"
+
+    "github.com/labstack/echo/v4"
+)
+
+// The export passphrase travels in a header so the import body can be the container itself
+const passphraseHeader = "X-Passphrase"
+
+// Encrypted export of the whole vault, trash and password history included
+func (h *Handler) Export(c echo.Context) error {
+    passphrase := c.Request().Header.Get(passphraseHeader)
+    if passphrase == "" {
+        return c.JSON(http.StatusBadRequest, utils.JSONError(passphraseHeader+" header is required"))
+    }
+
+    var buf bytes.Buffer
+    if err := h.App.DB.Export(&buf, passphrase); err != nil {
+        h.App.Logger.Error("Export error:", err)
+        return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to export vault"))
+    }
+    name := "vault-" + time.Now().Format("2006-01-02") + ".pmexport"
+    c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+name+`"`)
+    return c.Blob(http.StatusOK, echo.MIMEApplicationJSONCharsetUTF8, buf.Bytes())
+}
+
+// Import an export container; entries are appended with new IDs
+func (h *Handler) Import(c echo.Context) error {
+    passphrase := c.Request().Header.Get(passphraseHeader)
+    if passphrase == "" {
+        return c.JSON(http.StatusBadRequest, utils.JSONError(passphraseHeader+" header is required"))
+    }
+
+    n, err := h.App.DB.Import(c.Request().Body, passphrase)
+    switch {
+    case errors.Is(err, db.ErrBadPassphrase):
+        return c.JSON(http.StatusForbidden, utils.JSONError("Wrong passphrase or corrupted export"))
+    case errors.Is(err, db.ErrExportFormat),
+        errors.Is(err, security.ErrInvalidTOTP),
+        errors.Is(err, model.ErrInvalidField):
+        return c.JSON(http.StatusBadRequest, utils.JSONError(err.Error()))
+    case err != nil:
+        h.App.Logger.Error("Import error:", err)
+        return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to import vault"))
+    }
+    return c.JSON(http.StatusOK, map[string]int{"imported": n})
+}
+
+type plainExportRequest struct {
+    MasterPassword string `json:"master_password"`
+    Format         string `json:"format"`  // "csv" or "json"
+    Confirm        bool   `json:"confirm"` // the caller understands the file is NOT encrypted
+}
+
+// Unencrypted export in the Bitwarden layout, for audits; requires the master password again
+func (h *Handler) ExportPlain(c echo.Context) error {
+    var req plainExportRequest
+    if err := c.Bind(&req); err != nil || req.MasterPassword == "" {
+        return c.JSON(http.StatusBadRequest, utils.JSONError("master_password is required"))
+    }
+    if !req.Confirm {
+        return c.JSON(http.StatusBadRequest, utils.JSONError("confirm must be true: the export is not encrypted"))
+    }
+    if req.Format != db.PlainCSV && req.Format != db.PlainJSON {
+        return c.JSON(http.StatusBadRequest, utils.JSONError(db.ErrPlainFormat.Error()))
+    }
+
+    err := h.App.VerifyMasterPassword(req.MasterPassword)
+    switch {
+    case errors.Is(err, security.ErrInvalidMasterPassword):
+        return c.JSON(http.StatusForbidden, utils.JSONError("Invalid master password"))
+    case err != nil:
+        return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to verify master password"))
+    }
+
+    var buf bytes.Buffer
+    if err := h.App.DB.ExportPlain(&buf, req.Format); err != nil {
+        h.App.Logger.Error("Plain export error:", err)
+        return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to export vault"))
+    }
+    h.App.Logger.Warn("Unencrypted vault export requested from ", c.RealIP())
+
+    contentType := echo.MIMEApplicationJSONCharsetUTF8
+    if req.Format == db.PlainCSV {
+        contentType = "text/csv; charset=utf-8"
+    }
+    name := "vault-" + time.Now().Format("2006-01-02") + "-UNENCRYPTED." + req.Format
+    c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+name+`"`)
+    c.Response().Header().Set("Cache-Control", "no-store")
+    return c.Blob(http.StatusOK, contentType, buf.Bytes())
+}
@@ -0,0 +1,42 @@
+package endpoint
+
+import (
+    "fmt"
+    "net/http"
+    "strconv"
+
+    "password-manager/pkg/utils"
+
+    "github.com/labstack/echo/v4"
+)
+
+// Copy a custom field value (e.g. a hidden one) to the clipboard
+func (h *Handler) CopyField(c echo.Context) error {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        return c.JSON(http.StatusBadRequest, utils.JSONError("Invalid ID"))
+    }
+    index, err := strconv.Atoi(c.Param("index"))
+    if err != nil {
+        return c.JSON(http.StatusBadRequest, utils.JSONError("Invalid field index"))
+    }
+
+    if err := h.App.DB.RequireLive(id); err != nil {
+        return liveEntryError(c, err, "Failed to decrypt fields")
+    }
+    details, err := h.App.DB.GetDetails(id)
+    if err != nil {
+        return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to decrypt fields"))
+    }
+    if index < 0 || index >= len(details.Fields) {
+        return c.JSON(http.StatusNotFound, utils.JSONError("Field not found"))
+    }
+
+    seconds, err := h.copySecret(details.Fields[index].Value)
+    if err != nil {
+        return copyError(c, err, "Failed to copy field")
+    }
+    return c.JSON(http.StatusOK, map[string]string{
+        "status": fmt.Sprintf("Field copied to clipboard. It will be cleared in %d seconds.", seconds),
+    })
+}
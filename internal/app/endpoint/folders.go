@@ -0,0 +1,170 @@
+package endpoint
+
+import (
+    "errors"
+    "net/http"
+    "strconv"
+
+    "password-manager/internal/app/db"
+    "password-manager/pkg/utils"
+
+    "github.com/labstack/echo/v4"
+)
+
+// Folders with their paths and entry counts, sorted by path
+func (h *Handler) GetFolders(c echo.Context) error {
+    list, err := h.App.DB.GetFolders()
+    if err != nil {
+        return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to retrieve folders"))
+    }
+    return c.JSON(http.StatusOK, list)
+}
+
+// Create a folder; parent_id 0 or missing — top level
+func (h *Handler) CreateFolder(c echo.Context) error {
+    var req struct {
+        Name     string `json:"name"`
+        ParentID int    `json:"parent_id"`
+    }
+    if err := c.Bind(&req); err != nil {
+        return c.JSON(http.StatusBadRequest, utils.JSONError("Invalid request body"))
+    }
+    f, err := h.App.DB.CreateFolder(req.ParentID, req.Name)
+    if err != nil {
+        return folderError(c, err, "Failed to create folder")
+    }
+    return c.JSON(http.StatusCreated, f)
+}
+
+// Rename and/or move a folder: {"name": "..."}, {"parent_id": 0} moves it to the top level
+func (h *Handler) UpdateFolder(c echo.Context) error {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        return c.JSON(http.StatusBadRequest, utils.JSONError("Invalid ID"))
+    }
+    var req struct {
+        Name     string `json:"name"`
+        ParentID *int   `json:"parent_id"`
+    }
+    if err := c.Bind(&req); err != nil {
+        return c.JSON(http.StatusBadRequest, utils.JSONError("Invalid request body"))
+    }
+    if req.Name == "" && req.ParentID == nil {
+        return c.JSON(http.StatusBadRequest, utils.JSONError("Nothing to update"))
+    }
+
+    if req.ParentID != nil {
+        if err := h.App.DB.MoveFolder(id, *req.ParentID); err != nil {
+            return folderError(c, err, "Failed to move folder")
+        }
+    }
+    if req.Name != "" {
+        if err := h.App.DB.RenameFolder(id, req.Name); err != nil {
+            return folderError(c, err, "Failed to rename folder")
+        }
+    }
+    return c.JSON(http.StatusOK, map[string]string{"status": "Folder updated"})
+}
+
+// Move entries and subfolders into another folder and delete this one
+func (h *Handler) MergeFolder(c echo.Context) error {
+    from, into, ok := mergeIDs(c)
+    if !ok {
+        return c.JSON(http.StatusBadRequest, utils.JSONError("Invalid ID"))
+    }
+    if err := h.App.DB.MergeFolders(from, into); err != nil {
+        return folderError(c, err, "Failed to merge folders")
+    }
+    return c.JSON(http.StatusOK, map[string]string{"status": "Folders merged"})
+}
+
+// Delete a folder; its entries and subfolders move to the parent folder
+func (h *Handler) DeleteFolder(c echo.Context) error {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        return c.JSON(http.StatusBadRequest, utils.JSONError("Invalid ID"))
+    }
+    if err := h.App.DB.DeleteFolder(id); err != nil {
+        return folderError(c, err, "Failed to delete folder")
+    }
+    return c.NoContent(http.StatusNoContent)
+}
+
+// Tags with entry counts, sorted by name
+func (h *Handler) GetTags(c echo.Context) error {
+    list, err := h.App.DB.GetTags()
+    if err != nil {
+        return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to retrieve tags"))
+    }
+    return c.JSON(http.StatusOK, list)
+}
+
+// Rename a tag; renaming onto an existing tag is a merge (see MergeTag)
+func (h *Handler) UpdateTag(c echo.Context) error {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        return c.JSON(http.StatusBadRequest, utils.JSONError("Invalid ID"))
+    }
+    var req struct {
+        Name string `json:"name"`
+    }
+    if err := c.Bind(&req); err != nil {
+        return c.JSON(http.StatusBadRequest, utils.JSONError("Invalid request body"))
+    }
+    if err := h.App.DB.RenameTag(id, req.Name); err != nil {
+        return folderError(c, err, "Failed to rename tag")
+    }
+    return c.JSON(http.StatusOK, map[string]string{"status": "Tag renamed"})
+}
+
+// Replace a tag with another one on every entry and delete it
+func (h *Handler) MergeTag(c echo.Context) error {
+    from, into, ok := mergeIDs(c)
+    if !ok {
+        return c.JSON(http.StatusBadRequest, utils.JSONError("Invalid ID"))
+    }
+    if err := h.App.DB.MergeTags(from, into); err != nil {
+        return folderError(c, err, "Failed to merge tags")
+    }
+    return c.JSON(http.StatusOK, map[string]string{"status": "Tags merged"})
+}
+
+// Remove a tag from every entry
+func (h *Handler) DeleteTag(c echo.Context) error {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        return c.JSON(http.StatusBadRequest, utils.JSONError("Invalid ID"))
+    }
+    if err := h.App.DB.DeleteTag(id); err != nil {
+        return folderError(c, err, "Failed to delete tag")
+    }
+    return c.NoContent(http.StatusNoContent)
+}
+
+// mergeIDs: :id из пути и {"into": ID} из тела
+func mergeIDs(c echo.Context) (from, into int, ok bool) {
+    from, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        return 0, 0, false
+    }
+    var req struct {
+        Into int `json:"into"`
+    }
+    if err := c.Bind(&req); err != nil || req.Into <= 0 {
+        return 0, 0, false
+    }
+    return from, req.Into, true
+}
+
+// folderError: ошибки папок и тегов → HTTP-статус, остальные — 500 с fallback
+func folderError(c echo.Context, err error, fallback string) error {
+    switch {
+    case errors.Is(err, db.ErrFolderNotFound), errors.Is(err, db.ErrTagNotFound):
+        return c.JSON(http.StatusNotFound, utils.JSONError(err.Error()))
+    case errors.Is(err, db.ErrFolderExists), errors.Is(err, db.ErrTagExists):
+        return c.JSON(http.StatusConflict, utils.JSONError(err.Error()))
+    case errors.Is(err, db.ErrFolderName), errors.Is(err, db.ErrFolderCycle), errors.Is(err, db.ErrTagName):
+        return c.JSON(http.StatusBadRequest, utils.JSONError(err.Error()))
+    }
+    return c.JSON(http.StatusInternalServerError, utils.JSONError(fallback))
+}
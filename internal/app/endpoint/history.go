@@ -0,0 +1,48 @@
+package endpoint
+
+import (
+    "errors"
+    "net/http"
+    "strconv"
+
+    "password-manager/internal/app/db"
+    "password-manager/pkg/utils"
+
+    "github.com/labstack/echo/v4"
+)
+
+// Previous passwords of an entry (timestamps only, newest first)
+func (h *Handler) GetPasswordHistory(c echo.Context) error {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        return c.JSON(http.StatusBadRequest, utils.JSONError("Invalid ID"))
+    }
+    list, err := h.App.DB.GetPasswordHistory(id)
+    if err != nil {
+        return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to retrieve history"))
+    }
+    return c.JSON(http.StatusOK, list)
+}
+
+// Make a previous password current again; the current one goes to history
+func (h *Handler) RestorePassword(c echo.Context) error {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        return c.JSON(http.StatusBadRequest, utils.JSONError("Invalid ID"))
+    }
+    hid, err := strconv.Atoi(c.Param("hid"))
+    if err != nil {
+        return c.JSON(http.StatusBadRequest, utils.JSONError("Invalid history ID"))
+    }
+
+    err = h.App.DB.RestorePassword(id, hid)
+    switch {
+    case errors.Is(err, db.ErrHistoryNotFound):
+        return c.JSON(http.StatusNotFound, utils.JSONError("History entry not found"))
+    case errors.Is(err, db.ErrInTrash):
+        return c.JSON(http.StatusConflict, utils.JSONError("Entry is in the trash"))
+    case err != nil:
+        return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to restore password"))
+    }
+    return c.JSON(http.StatusOK, map[string]string{"status": "Password restored"})
+}
@@ -0,0 +1,94 @@
+package endpoint
+
+import (
+    "encoding/json"
+    "errors"
+    "net/http"
+
+    "password-manager/internal/app/importer"
+    "password-manager/pkg/utils"
+
+    "github.com/labstack/echo/v4"
+)
+
+// Multipart form for both preview and import:
+//
+//    file            — the export of the other manager
+//    password        — KeePass master password (only for keepass)
+//    mapping         — JSON object folder → category (optional)
+//    skip_duplicates — "true" to leave entries that already exist (import only)
+func (h *Handler) parseExternalImport(c echo.Context) ([]importer.PreviewItem, []string, error) {
+    fh, err := c.FormFile("file")
+    if err != nil {
+        return nil, nil, echo.NewHTTPError(http.StatusBadRequest, utils.JSONError("file is required"))
+    }
+    mapping := importer.Mapping{}
+    if raw := c.FormValue("mapping"); raw != "" {
+        if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+            return nil, nil, echo.NewHTTPError(http.StatusBadRequest, utils.JSONError("mapping must be a JSON object"))
+        }
+    }
+
+    f, err := fh.Open()
+    if err != nil {
+        return nil, nil, err
+    }
+    defer f.Close()
+
+    items, err := importer.Parse(c.Param("format"), f, c.FormValue("password"))
+    switch {
+    case errors.Is(err, importer.ErrKeePassCredentials):
+        return nil, nil, echo.NewHTTPError(http.StatusForbidden, utils.JSONError(err.Error()))
+    case err != nil:
+        return nil, nil, echo.NewHTTPError(http.StatusBadRequest, utils.JSONError(err.Error()))
+    }
+
+    existing, err := h.App.DB.GetAllPasswords()
+    if err != nil {
+        return nil, nil, err
+    }
+    return importer.Preview(items, existing, mapping), importer.Folders(items), nil
+}
+
+type importPreviewResponse struct {
+    Folders    []string               `json:"folders"`
+    Items      []importer.PreviewItem `json:"items"`
+    Duplicates int                    `json:"duplicates"`
+}
+
+// What an import would create: categories after mapping and duplicates of existing entries
+func (h *Handler) PreviewExternalImport(c echo.Context) error {
+    preview, folders, err := h.parseExternalImport(c)
+    if err != nil {
+        return importError(c, err)
+    }
+    resp := importPreviewResponse{Folders: folders, Items: preview}
+    for _, it := range preview {
+        if it.DuplicateOf != 0 {
+            resp.Duplicates++
+        }
+    }
+    return c.JSON(http.StatusOK, resp)
+}
+
+// Import entries from Bitwarden, KeePass or a browser CSV export
+func (h *Handler) ImportExternal(c echo.Context) error {
+    preview, _, err := h.parseExternalImport(c)
+    if err != nil {
+        return importError(c, err)
+    }
+    res, err := importer.Apply(h.App.DB, preview, c.FormValue("skip_duplicates") == "true")
+    if err != nil {
+        h.App.Logger.Error("External import error:", err)
+        return c.JSON(http.StatusInternalServerError, utils.JSONError("Import failed, nothing was imported: "+err.Error()))
+    }
+    return c.JSON(http.StatusOK, res)
+}
+
+func importError(c echo.Context, err error) error {
+    var he *echo.HTTPError
+    if errors.As(err, &he) {
+        return c.JSON(he.Code, he.Message)
+    }
+    return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to read import file"))
+}
@@ -0,0 +1,42 @@
+package endpoint
+
+import (
+    "errors"
+    "net/http"
+
+    "password-manager/internal/app/db"
+    "password-manager/pkg/security"
+    "password-manager/pkg/utils"
+
+    "github.com/labstack/echo/v4"
+)
+
+type changeMasterRequest struct {
+    OldPassword string `json:"old_password"`
+    NewPassword string `json:"new_password"`
+}
+
+// Change the master password; nothing is committed if any entry fails to decrypt
+func (h *Handler) ChangeMasterPassword(c echo.Context) error {
+    var req changeMasterRequest
+    if err := c.Bind(&req); err != nil || req.OldPassword == "" || req.NewPassword == "" {
+        return c.JSON(http.StatusBadRequest, utils.JSONError("Old and new master passwords are required"))
+    }
+
+    err := h.App.ChangeMasterPassword(req.OldPassword, req.NewPassword)
+    var decErr *db.DecryptError
+    switch {
+    case err == nil:
+        return c.JSON(http.StatusOK, map[string]string{"status": "Master password changed"})
+    case errors.Is(err, security.ErrInvalidMasterPassword):
+        return c.JSON(http.StatusForbidden, utils.JSONError("Invalid old master password"))
+    case errors.As(err, &decErr):
+        return c.JSON(http.StatusConflict, map[string]interface{}{
+            "error":      "Some entries failed to decrypt, master password not changed",
+            "failed_ids": decErr.IDs,
+        })
+    default:
+        h.App.Logger.Error("Change master password error:", err)
+        return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to change master password"))
+    }
+}
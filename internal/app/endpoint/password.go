@@ -1,12 +1,17 @@
 package endpoint
 
 import (
+    "database/sql"
+    "errors"
+    "fmt"
     "net/http"
     "strconv"
     "time"
 
     "password-manager/internal/app"
+    "password-manager/internal/app/db"
     "password-manager/internal/app/model"
+    "password-manager/pkg/security"
     "password-manager/pkg/utils"
 
     "github.com/labstack/echo/v4"
@@ -14,22 +19,64 @@ import (
 )
 
 type Handler struct {
-    App *app.App
+    App      *app.App
+    Sessions *sessionStore
+    Unlocks  *unlockLimiter
 }
 
-const minEntropy = 60 // Recommended minimum entropy
+// route: handlers are method expressions so the same table serves every vault
+type route struct {
+    method  string
+    path    string
+    handler func(*Handler, echo.Context) error
+    public  bool // no session required
+}
 
-// Register routes and inject dependencies
-func RegisterRoutes(e *echo.Echo, appInstance *app.App) {
-    h := &Handler{App: appInstance}
+var routes = []route{
+    {http.MethodPost, "/session", (*Handler).CreateSession, true},
+    {http.MethodPost, "/unlock", (*Handler).CreateSession, true},
+    {http.MethodGet, "/generate-password", (*Handler).GeneratePassword, true},
+    {http.MethodGet, "/generate-passphrase", (*Handler).GeneratePassphrase, true},
+
+    // Всё остальное — только при разблокированном хранилище и с токеном
+    {http.MethodDelete, "/session", (*Handler).DeleteSession, false},
+    {http.MethodPost, "/master/change", (*Handler).ChangeMasterPassword, false},
+    {http.MethodGet, "/vault/settings", (*Handler).GetVaultSettings, false},
+    {http.MethodPut, "/vault/settings", (*Handler).UpdateVaultSettings, false},
+    {http.MethodGet, "/passwords", (*Handler).GetFilteredPasswords, false},
+    {http.MethodGet, "/passwords/:id", (*Handler).GetPassword, false},
+    {http.MethodPost, "/passwords", (*Handler).CreatePassword, false},
+    {http.MethodPut, "/passwords/:id", (*Handler).UpdatePassword, false},
+    {http.MethodDelete, "/passwords/:id", (*Handler).DeletePassword, false},
+    {http.MethodPost, "/passwords/:id/copy", (*Handler).CopyPassword, false},
+    {http.MethodGet, "/passwords/:id/totp", (*Handler).GetTOTP, false},
+    {http.MethodGet, "/passwords/:id/generate", (*Handler).RegeneratePassword, false},
+    {http.MethodPost, "/passwords/:id/fields/:index/copy", (*Handler).CopyField, false},
+    {http.MethodGet, "/passwords/:id/history", (*Handler).GetPasswordHistory, false},
+    {http.MethodPost, "/passwords/:id/history/:hid/restore", (*Handler).RestorePassword, false},
+    {http.MethodGet, "/trash", (*Handler).GetTrash, false},
+    {http.MethodPost, "/trash/:id/restore", (*Handler).RestoreFromTrash, false},
+    {http.MethodDelete, "/trash/:id", (*Handler).PurgePassword, false},
+    {http.MethodGet, "/folders", (*Handler).GetFolders, false},
+    {http.MethodPost, "/folders", (*Handler).CreateFolder, false},
+    {http.MethodPut, "/folders/:id", (*Handler).UpdateFolder, false},
+    {http.MethodPost, "/folders/:id/merge", (*Handler).MergeFolder, false},
+    {http.MethodDelete, "/folders/:id", (*Handler).DeleteFolder, false},
+    {http.MethodGet, "/tags", (*Handler).GetTags, false},
+    {http.MethodPut, "/tags/:id", (*Handler).UpdateTag, false},
+    {http.MethodPost, "/tags/:id/merge", (*Handler).MergeTag, false},
+    {http.MethodDelete, "/tags/:id", (*Handler).DeleteTag, false},
+    {http.MethodGet, "/report", (*Handler).GetReport, false},
+    {http.MethodGet, "/export", (*Handler).Export, false},
+    {http.MethodPost, "/export/plain", (*Handler).ExportPlain, false},
+    {http.MethodPost, "/import", (*Handler).Import, false},
+    {http.MethodPost, "/import/:format/preview", (*Handler).PreviewExternalImport, false},
+    {http.MethodPost, "/import/:format", (*Handler).ImportExternal, false},
+}
 
-    e.GET("/passwords", h.GetFilteredPasswords)
-    e.GET("/passwords/:id", h.GetPassword)
-    e.GET("/generate-password", h.GeneratePassword)
-    e.POST("/passwords", h.CreatePassword)
-    e.PUT("/passwords/:id", h.UpdatePassword)
-    e.DELETE("/passwords/:id", h.DeletePassword)
-    e.POST("/passwords/:id/copy", h.CopyPassword)
+// Register routes for a single vault (named "default")
+func RegisterRoutes(e *echo.Echo, appInstance *app.App, sessionTTL time.Duration) {
+    RegisterVaults(e, []Vault{{Name: app.DefaultVaultName, App: appInstance}}, sessionTTL)
 }
 
 // Retrieve all entries without passwords
@@ -41,6 +88,12 @@ func (h *Handler) GetPasswords(c echo.Context) error {
     return c.JSON(http.StatusOK, list)
 }
 
+// Entry with notes and custom fields; hidden field values are blanked
+type passwordResponse struct {
+    model.PasswordListItem
+    model.EntryDetails
+}
+
 // Retrieve a single entry without the password
 func (h *Handler) GetPassword(c echo.Context) error {
     id := c.Param("id")
@@ -48,7 +101,11 @@ func (h *Handler) GetPassword(c echo.Context) error {
     if err != nil {
         return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to retrieve password"))
     }
-    return c.JSON(http.StatusOK, p)
+    details, err := h.App.DB.GetDetails(p.ID)
+    if err != nil {
+        return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to decrypt notes"))
+    }
+    return c.JSON(http.StatusOK, passwordResponse{PasswordListItem: p, EntryDetails: details.Masked()})
 }
 
 // Create a new password entry
@@ -58,32 +115,35 @@ func (h *Handler) CreatePassword(c echo.Context) error {
         return c.JSON(http.StatusBadRequest, utils.JSONError("Invalid request body"))
     }
 
-    if err := utils.ValidatePasswordStrength(p.Password, minEntropy); err != nil {
-        return c.JSON(http.StatusBadRequest, map[string]string{
-            "error":  "Password is too weak",
-            "reason": err.Error(),
-        })
-    }
-
-    encryptedPass, err := h.App.Crypto.Encrypt(p.Password)
-    if err != nil {
-        h.App.Logger.Error("Encrypt error:", err)
-        return c.JSON(http.StatusInternalServerError, utils.JSONError("Encryption failed"))
+    if st, weak := weakPassword(p); weak {
+        return weakPasswordResponse(c, st)
     }
-    p.Password = encryptedPass
 
     id, createdAt, err := h.App.DB.CreatePassword(p)
+    if errors.Is(err, security.ErrInvalidTOTP) || errors.Is(err, model.ErrInvalidField) ||
+        errors.Is(err, utils.ErrGeneratorPolicy) || errors.Is(err, db.ErrFolderName) || errors.Is(err, db.ErrTagName) {
+        return c.JSON(http.StatusBadRequest, utils.JSONError(err.Error()))
+    }
     if err != nil {
         return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to save password"))
     }
 
+    hasDetails := (p.Notes != nil && *p.Notes != "") || len(p.Fields) > 0 ||
+        (p.Generator != nil && p.Generator.Length > 0)
     resp := model.PasswordListItem{
-        ID:        int(id),
-        Service:   p.Service,
-        Username:  p.Username,
-        Link:      p.Link,
-        Category:  p.Category,
-        CreatedAt: createdAt,
+        ID:         int(id),
+        Service:    p.Service,
+        Username:   p.Username,
+        Link:       p.Link,
+        Category:   p.Category,
+        CreatedAt:  createdAt,
+        UpdatedAt:  createdAt,
+        HasTOTP:    p.TOTPSecret != nil && *p.TOTPSecret != "",
+        HasDetails: hasDetails,
+    }
+    // папка и теги — как их сохранило хранилище (регистр существующих имён)
+    if stored, err := h.App.DB.GetPasswordByID(strconv.FormatInt(id, 10)); err == nil {
+        resp.FolderID, resp.Category, resp.Tags = stored.FolderID, stored.Category, stored.Tags
     }
 
     return c.JSON(http.StatusCreated, resp)
@@ -97,19 +157,61 @@ func (h *Handler) UpdatePassword(c echo.Context) error {
         return c.JSON(http.StatusBadRequest, utils.JSONError("Invalid request body"))
     }
 
-    encryptedPass, err := h.App.Crypto.Encrypt(p.Password)
+    // Проверка как при создании, но только если пароль меняется:
+    // правка логина или заметок не должна упираться в старый пароль
+    rowID, err := strconv.Atoi(id)
     if err != nil {
-        return c.JSON(http.StatusInternalServerError, utils.JSONError("Encryption failed"))
+        return c.JSON(http.StatusBadRequest, utils.JSONError("Invalid ID"))
     }
-    p.Password = encryptedPass
-
-    if err := h.App.DB.UpdatePassword(id, p); err != nil {
+    if err := h.App.DB.RequireLive(rowID); err != nil {
+        return liveEntryError(c, err, "Failed to update password")
+    }
+    current, err := h.App.DB.DecryptPassword(rowID)
+    if err != nil {
         return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to update password"))
     }
+    if st, weak := weakPassword(p); weak && p.Password != current {
+        return weakPasswordResponse(c, st)
+    }
+
+    err = h.App.DB.UpdatePassword(id, p)
+    if errors.Is(err, security.ErrInvalidTOTP) || errors.Is(err, model.ErrInvalidField) ||
+        errors.Is(err, utils.ErrGeneratorPolicy) || errors.Is(err, db.ErrFolderName) || errors.Is(err, db.ErrTagName) {
+        return c.JSON(http.StatusBadRequest, utils.JSONError(err.Error()))
+    }
+    if err != nil {
+        return liveEntryError(c, err, "Failed to update password")
+    }
 
     return c.JSON(http.StatusOK, map[string]string{"status": "Updated successfully"})
 }
 
+// liveEntryError: 404 для несуществующей записи, 409 для записи в корзине
+// (её можно только восстановить), иначе 500 с сообщением failed
+func liveEntryError(c echo.Context, err error, failed string) error {
+    switch {
+    case errors.Is(err, sql.ErrNoRows):
+        return c.JSON(http.StatusNotFound, utils.JSONError("Password not found"))
+    case errors.Is(err, db.ErrInTrash):
+        return c.JSON(http.StatusConflict, utils.JSONError("Entry is in the trash"))
+    }
+    return c.JSON(http.StatusInternalServerError, utils.JSONError(failed))
+}
+
+// weakPassword: сервис, логин и ссылка атакующему известны — пароль из них слабый
+func weakPassword(p model.Password) (security.Strength, bool) {
+    st := security.EstimateStrength(p.Password, p.Service, p.Username, p.Link)
+    return st, st.Weak()
+}
+
+func weakPasswordResponse(c echo.Context, st security.Strength) error {
+    return c.JSON(http.StatusBadRequest, map[string]interface{}{
+        "error":    "Password is too weak",
+        "reason":   st.Reason(),
+        "strength": st,
+    })
+}
+
 // Copy password to clipboard
 func (h *Handler) CopyPassword(c echo.Context) error {
     idStr := c.Param("id")
@@ -118,46 +220,73 @@ func (h *Handler) CopyPassword(c echo.Context) error {
         return c.JSON(http.StatusBadRequest, utils.JSONError("Некорректный ID"))
     }
 
-    encB64, err := h.App.DB.GetEncryptedPasswordByID(id)
+    if err := h.App.DB.RequireLive(id); err != nil {
+        return liveEntryError(c, err, "Не удалось расшифровать пароль")
+    }
+    plain, err := h.App.DB.DecryptPassword(id)
     if err != nil {
-        return c.JSON(http.StatusInternalServerError, utils.JSONError("Не удалось получить зашифрованный пароль"))
+        return c.JSON(http.StatusInternalServerError, utils.JSONError("Не удалось расшифровать пароль"))
     }
 
-    if err := utils.CopyToClipboard(encB64, h.App.Crypto); err != nil {
-        h.App.Logger.Error("Ошибка копирования:", err)
-        return c.JSON(http.StatusInternalServerError, utils.JSONError("Ошибка при копировании"))
+    seconds, err := h.copySecret(plain)
+    if err != nil {
+        return copyError(c, err, "Ошибка при копировании")
     }
 
-    go func() {
-        time.Sleep(10 * time.Second)
-        _ = utils.CopyToClipboard("", h.App.Crypto)
-    }()
-
     return c.JSON(http.StatusOK, map[string]string{
-        "status": "Пароль скопирован в буфер обмена. Будет очищен через 10 секунд.",
+        "status": fmt.Sprintf("Пароль скопирован в буфер обмена. Будет очищен через %d с.", seconds),
     })
 }
 
-// Delete a password entry
+// copySecret кладёт секрет в буфер обмена с таймаутом очистки этого хранилища
+// и возвращает таймаут в секундах для ответа
+func (h *Handler) copySecret(secret string) (int, error) {
+    timeout := utils.ClipboardTimeoutOrDefault(h.App.DB.ClipboardTimeout())
+    if err := utils.Clipboard.CopySecret(secret, timeout); err != nil {
+        if !errors.Is(err, utils.ErrNoClipboard) {
+            h.App.Logger.Error("Ошибка копирования:", err)
+        }
+        return 0, err
+    }
+    return int(timeout.Seconds()), nil
+}
+
+// copyError: у сервера без GUI (cmd/server) буфера обмена нет вовсе — это
+// не сбой, а неподдерживаемое действие
+func copyError(c echo.Context, err error, failed string) error {
+    if errors.Is(err, utils.ErrNoClipboard) {
+        return c.JSON(http.StatusNotImplemented, utils.JSONError("Clipboard is not available on a headless server"))
+    }
+    return c.JSON(http.StatusInternalServerError, utils.JSONError(failed))
+}
+
+// Move a password entry to the trash
 func (h *Handler) DeletePassword(c echo.Context) error {
     id := c.Param("id")
-    if err := h.App.DB.DeletePassword(id); err != nil {
+    err := h.App.DB.DeletePassword(id)
+    if errors.Is(err, sql.ErrNoRows) {
+        return c.JSON(http.StatusNotFound, utils.JSONError("Password not found"))
+    }
+    if err != nil {
         return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to delete password"))
     }
     return c.NoContent(http.StatusNoContent)
 }
 
-// Filter password entries or return all if no filters
+// Filter password entries or return all if no filters.
+// folder matches the folder and its subfolders, category only the folder itself;
+// q is a typo-tolerant search, best matches first.
 func (h *Handler) GetFilteredPasswords(c echo.Context) error {
-    service := c.QueryParam("service")
-    username := c.QueryParam("username")
-    category := c.QueryParam("category")
-
-    if service == "" && username == "" && category == "" {
-        return h.GetPasswords(c)
-    }
-
-    list, err := h.App.DB.GetFilteredPasswords(service, username, category)
+    includeDeleted, _ := strconv.ParseBool(c.QueryParam("include_deleted"))
+    list, err := h.App.DB.GetFilteredPasswords(model.PasswordFilter{
+        Service:        c.QueryParam("service"),
+        Username:       c.QueryParam("username"),
+        Category:       c.QueryParam("category"),
+        Folder:         c.QueryParam("folder"),
+        Tag:            c.QueryParam("tag"),
+        Query:          c.QueryParam("q"),
+        IncludeDeleted: includeDeleted,
+    })
     if err != nil {
         return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to filter passwords"))
     }
@@ -171,13 +300,52 @@ func (h *Handler) GeneratePassword(c echo.Context) error {
         return c.JSON(http.StatusBadRequest, utils.JSONError("Invalid length"))
     }
 
-    exclude := c.QueryParam("exclude")
-    useUpper := c.QueryParam("upper") == "true"
-    useLower := c.QueryParam("lower") == "true"
-    useDigits := c.QueryParam("digits") == "true"
-    useSymbols := c.QueryParam("symbols") == "true"
+    policy := utils.GeneratorPolicy{
+        Length:           length,
+        Upper:            c.QueryParam("upper") == "true",
+        Lower:            c.QueryParam("lower") == "true",
+        Digits:           c.QueryParam("digits") == "true",
+        Symbols:          c.QueryParam("symbols") == "true",
+        SymbolSet:        c.QueryParam("symbol_set"),
+        Exclude:          c.QueryParam("exclude"),
+        ExcludeAmbiguous: c.QueryParam("exclude_ambiguous") == "true",
+        NoRepeat:         c.QueryParam("no_repeat") == "true",
+        Pronounceable:    c.QueryParam("pronounceable") == "true",
+    }
+    for param, dst := range map[string]*int{
+        "min_upper":   &policy.MinUpper,
+        "min_lower":   &policy.MinLower,
+        "min_digits":  &policy.MinDigits,
+        "min_symbols": &policy.MinSymbols,
+    } {
+        if v := c.QueryParam(param); v != "" {
+            if *dst, err = strconv.Atoi(v); err != nil {
+                return c.JSON(http.StatusBadRequest, utils.JSONError("Invalid "+param))
+            }
+        }
+    }
+
+    return generateResponse(c, policy)
+}
 
-    pass, err := utils.GeneratePassword(length, useUpper, useLower, useDigits, useSymbols, exclude)
+// Regenerate a password with the rules saved on the entry (nothing is stored)
+func (h *Handler) RegeneratePassword(c echo.Context) error {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        return c.JSON(http.StatusBadRequest, utils.JSONError("Invalid ID"))
+    }
+    details, err := h.App.DB.GetDetails(id)
+    if err != nil {
+        return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to decrypt notes"))
+    }
+    if details.Generator == nil {
+        return c.JSON(http.StatusNotFound, utils.JSONError("No generator policy saved for this entry"))
+    }
+    return generateResponse(c, *details.Generator)
+}
+
+func generateResponse(c echo.Context, policy utils.GeneratorPolicy) error {
+    pass, err := utils.GenerateWithPolicy(policy)
     if err != nil {
         return c.JSON(http.StatusBadRequest, utils.JSONError(err.Error()))
     }
@@ -187,5 +355,40 @@ func (h *Handler) GeneratePassword(c echo.Context) error {
     return c.JSON(http.StatusOK, map[string]interface{}{
         "password": pass,
         "entropy":  entropy,
+        "policy":   policy,
+    })
+}
+
+// Generate a diceware passphrase from the EFF large wordlist; entropy is computed
+// from the options, not estimated from the resulting string
+func (h *Handler) GeneratePassphrase(c echo.Context) error {
+    opts := utils.DefaultPassphraseOptions()
+    if v := c.QueryParam("words"); v != "" {
+        n, err := strconv.Atoi(v)
+        if err != nil {
+            return c.JSON(http.StatusBadRequest, utils.JSONError("Invalid words"))
+        }
+        opts.Words = n
+    }
+    if c.QueryParams().Has("separator") {
+        opts.Separator = c.QueryParam("separator")
+    }
+    opts.Capitalize = c.QueryParam("capitalize") == "true"
+    if v := c.QueryParam("digits"); v != "" {
+        n, err := strconv.Atoi(v)
+        if err != nil {
+            return c.JSON(http.StatusBadRequest, utils.JSONError("Invalid digits"))
+        }
+        opts.Digits = n
+    }
+
+    phrase, entropy, err := utils.GeneratePassphrase(opts)
+    if err != nil {
+        return c.JSON(http.StatusBadRequest, utils.JSONError(err.Error()))
+    }
+
+    return c.JSON(http.StatusOK, map[string]interface{}{
+        "passphrase": phrase,
+        "entropy":    entropy,
     })
 }
@@ -0,0 +1,38 @@
+package endpoint
+
+import (
+    "net/http"
+    "strconv"
+
+    "password-manager/internal/app/report"
+    "password-manager/pkg/utils"
+
+    "github.com/labstack/echo/v4"
+)
+
+// Vault health: reused, weak and old passwords (no password values in the response).
+// min_score and max_age_days override the defaults; 0 disables that check.
+func (h *Handler) GetReport(c echo.Context) error {
+    opts := report.DefaultOptions()
+    for param, dst := range map[string]*int{
+        "min_score":    &opts.MinScore,
+        "max_age_days": &opts.MaxAgeDays,
+    } {
+        if v := c.QueryParam(param); v != "" {
+            n, err := strconv.Atoi(v)
+            if err != nil {
+                return c.JSON(http.StatusBadRequest, utils.JSONError("Invalid "+param))
+            }
+            *dst = n
+        }
+    }
+    if err := opts.Validate(); err != nil {
+        return c.JSON(http.StatusBadRequest, utils.JSONError(err.Error()))
+    }
+
+    r, err := report.Generate(h.App.DB, opts)
+    if err != nil {
+        return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to build report"))
+    }
+    return c.JSON(http.StatusOK, r)
+}
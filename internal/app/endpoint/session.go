@@ -0,0 +1,191 @@
+package endpoint
+
+import (
+    "crypto/rand"
+    "encoding/hex"
+    "math"
+    "net/http"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+
+    "password-manager/pkg/utils"
+
+    "github.com/labstack/echo/v4"
+)
+
+const defaultSessionTTL = 15 * time.Minute
+
+// sessionStore хранит выданные bearer-токены и время их истечения
+type sessionStore struct {
+    mu     sync.Mutex
+    ttl    time.Duration
+    tokens map[string]time.Time
+}
+
+func newSessionStore(ttl time.Duration) *sessionStore {
+    if ttl <= 0 {
+        ttl = defaultSessionTTL
+    }
+    return &sessionStore{ttl: ttl, tokens: make(map[string]time.Time)}
+}
+
+func (s *sessionStore) issue() (string, time.Time, error) {
+    buf := make([]byte, 32)
+    if _, err := rand.Read(buf); err != nil {
+        return "", time.Time{}, err
+    }
+    token := hex.EncodeToString(buf)
+    expires := time.Now().Add(s.ttl)
+
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    now := time.Now()
+    for t, exp := range s.tokens {
+        if now.After(exp) {
+            delete(s.tokens, t)
+        }
+    }
+    s.tokens[token] = expires
+    return token, expires, nil
+}
+
+func (s *sessionStore) valid(token string) bool {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    exp, ok := s.tokens[token]
+    if !ok {
+        return false
+    }
+    if time.Now().After(exp) {
+        delete(s.tokens, token)
+        return false
+    }
+    return true
+}
+
+func (s *sessionStore) revokeAll() {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.tokens = make(map[string]time.Time)
+}
+
+// Перебор мастер-пароля: после unlockFreeAttempts неудач подряд следующая
+// попытка возможна не раньше, чем через паузу, удваивающуюся с каждой неудачей
+const (
+    unlockFreeAttempts = 5
+    unlockBaseDelay    = time.Second
+    unlockMaxDelay     = 15 * time.Minute
+)
+
+// unlockLimiter: попытки разблокировки одного хранилища, по одной за раз
+type unlockLimiter struct {
+    mu       sync.Mutex
+    busy     bool
+    failures int
+    until    time.Time
+}
+
+// begin: можно ли пробовать сейчас; если нет — через сколько
+func (l *unlockLimiter) begin() (time.Duration, bool) {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    if l.busy {
+        return unlockBaseDelay, false
+    }
+    if wait := time.Until(l.until); wait > 0 {
+        return wait, false
+    }
+    l.busy = true
+    return 0, true
+}
+
+// done фиксирует исход попытки, начатой begin
+func (l *unlockLimiter) done(ok bool) {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    l.busy = false
+    if ok {
+        l.failures, l.until = 0, time.Time{}
+        return
+    }
+    l.failures++
+    if l.failures < unlockFreeAttempts {
+        return
+    }
+    delay := unlockMaxDelay
+    if n := l.failures - unlockFreeAttempts; n < 20 {
+        delay = min(unlockBaseDelay<<n, unlockMaxDelay)
+    }
+    l.until = time.Now().Add(delay)
+}
+
+func bearerToken(c echo.Context) string {
+    auth := c.Request().Header.Get(echo.HeaderAuthorization)
+    const prefix = "Bearer "
+    if len(auth) <= len(prefix) || !strings.EqualFold(auth[:len(prefix)], prefix) {
+        return ""
+    }
+    return strings.TrimSpace(auth[len(prefix):])
+}
+
+type sessionRequest struct {
+    Password string `json:"password"`
+}
+
+// Unlock the vault with the master password and issue a bearer token.
+// A new vault is never created here: use --key-file or the desktop app.
+// Repeated failures are throttled (429 with Retry-After).
+func (h *Handler) CreateSession(c echo.Context) error {
+    var req sessionRequest
+    if err := c.Bind(&req); err != nil || req.Password == "" {
+        return c.JSON(http.StatusBadRequest, utils.JSONError("Master password is required"))
+    }
+    // Иначе первый, кто дойдёт до сервера, задаст мастер-пароль
+    if !h.App.HasMeta() {
+        return c.JSON(http.StatusConflict, utils.JSONError("Vault is not initialized: create it with --key-file or the desktop app"))
+    }
+
+    if wait, ok := h.Unlocks.begin(); !ok {
+        c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+        return c.JSON(http.StatusTooManyRequests, utils.JSONError("Too many unlock attempts, try again later"))
+    }
+    err := h.App.InitializeMasterWithPassword(req.Password)
+    h.Unlocks.done(err == nil)
+    if err != nil {
+        return c.JSON(http.StatusUnauthorized, utils.JSONError("Invalid master password"))
+    }
+
+    token, expires, err := h.Sessions.issue()
+    if err != nil {
+        return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to create session"))
+    }
+
+    return c.JSON(http.StatusCreated, map[string]string{
+        "token":      token,
+        "expires_at": expires.UTC().Format(time.RFC3339),
+    })
+}
+
+// Lock the vault: wipe the key and revoke every issued token
+func (h *Handler) DeleteSession(c echo.Context) error {
+    h.App.Lock()
+    h.Sessions.revokeAll()
+    return c.NoContent(http.StatusNoContent)
+}
+
+// RequireSession rejects requests when the bearer token is missing or
+// expired (401), then while the vault is locked (423). The token goes first
+// so that only session holders learn whether the vault is unlocked.
+func (h *Handler) RequireSession(next echo.HandlerFunc) echo.HandlerFunc {
+    return func(c echo.Context) error {
+        if !h.Sessions.valid(bearerToken(c)) {
+            return c.JSON(http.StatusUnauthorized, utils.JSONError("Invalid or expired session"))
+        }
+        if h.App.IsLocked() {
+            return c.JSON(http.StatusLocked, utils.JSONError("Vault is locked"))
+        }
+        return next(c)
+    }
+}
@@ -0,0 +1,50 @@
+package endpoint
+
+import (
+    "database/sql"
+    "errors"
+    "net/http"
+    "strconv"
+    "time"
+
+    "password-manager/internal/app/db"
+    "password-manager/pkg/utils"
+
+    "github.com/labstack/echo/v4"
+)
+
+type totpResponse struct {
+    Code      string `json:"code"`
+    Digits    int    `json:"digits"`
+    Period    int    `json:"period"`
+    ExpiresIn int    `json:"expires_in"` // секунд до смены кода
+}
+
+// Current TOTP code of an entry
+func (h *Handler) GetTOTP(c echo.Context) error {
+    id, err := strconv.Atoi(c.Param("id"))
+    if err != nil {
+        return c.JSON(http.StatusBadRequest, utils.JSONError("Invalid ID"))
+    }
+
+    if err := h.App.DB.RequireLive(id); err != nil {
+        return liveEntryError(c, err, "Failed to decrypt TOTP secret")
+    }
+    t, err := h.App.DB.GetTOTP(id)
+    switch {
+    case errors.Is(err, sql.ErrNoRows):
+        return c.JSON(http.StatusNotFound, utils.JSONError("Password not found"))
+    case errors.Is(err, db.ErrNoTOTP):
+        return c.JSON(http.StatusNotFound, utils.JSONError("Entry has no TOTP secret"))
+    case err != nil:
+        return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to decrypt TOTP secret"))
+    }
+
+    now := time.Now()
+    return c.JSON(http.StatusOK, totpResponse{
+        Code:      t.Code(now),
+        Digits:    t.Digits,
+        Period:    t.Period,
+        ExpiresIn: int(t.Remaining(now) / time.Second),
+    })
+}
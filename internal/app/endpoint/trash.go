@@ -0,0 +1,44 @@
+package endpoint
+
+import (
+    "errors"
+    "net/http"
+
+    "password-manager/internal/app/db"
+    "password-manager/pkg/utils"
+
+    "github.com/labstack/echo/v4"
+)
+
+// Entries in the trash, most recently deleted first
+func (h *Handler) GetTrash(c echo.Context) error {
+    list, err := h.App.DB.GetTrash()
+    if err != nil {
+        return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to retrieve trash"))
+    }
+    return c.JSON(http.StatusOK, list)
+}
+
+// Move an entry back from the trash
+func (h *Handler) RestoreFromTrash(c echo.Context) error {
+    err := h.App.DB.RestoreFromTrash(c.Param("id"))
+    switch {
+    case errors.Is(err, db.ErrNotInTrash):
+        return c.JSON(http.StatusNotFound, utils.JSONError("Entry is not in the trash"))
+    case err != nil:
+        return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to restore entry"))
+    }
+    return c.JSON(http.StatusOK, map[string]string{"status": "Restored from trash"})
+}
+
+// Delete an entry from the trash permanently
+func (h *Handler) PurgePassword(c echo.Context) error {
+    err := h.App.DB.PurgePassword(c.Param("id"))
+    switch {
+    case errors.Is(err, db.ErrNotInTrash):
+        return c.JSON(http.StatusNotFound, utils.JSONError("Entry is not in the trash"))
+    case err != nil:
+        return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to delete entry"))
+    }
+    return c.NoContent(http.StatusNoContent)
+}
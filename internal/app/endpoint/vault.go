@@ -0,0 +1,87 @@
+package endpoint
+
+import (
+    "fmt"
+    "net/http"
+    "time"
+
+    "password-manager/internal/app/model"
+    "password-manager/pkg/utils"
+
+    "github.com/labstack/echo/v4"
+)
+
+type vaultSettings struct {
+    SealRecords        bool             `json:"seal_records"`
+    TrashRetentionDays int              `json:"trash_retention_days"` // 0 — keep forever
+    AutoLock           model.LockPolicy `json:"auto_lock"`            // enforced by the desktop/mobile app
+    ClipboardSeconds   int              `json:"clipboard_clear_seconds"`
+}
+
+// Partial update: omitted fields stay as they are; auto_lock is replaced as a whole
+type vaultSettingsUpdate struct {
+    SealRecords        *bool             `json:"seal_records"`
+    TrashRetentionDays *int              `json:"trash_retention_days"`
+    AutoLock           *model.LockPolicy `json:"auto_lock"`
+    ClipboardSeconds   *int              `json:"clipboard_clear_seconds"`
+}
+
+func (h *Handler) currentVaultSettings() vaultSettings {
+    return vaultSettings{
+        SealRecords:        h.App.DB.SealRecords(),
+        TrashRetentionDays: h.App.DB.TrashRetentionDays(),
+        AutoLock:           h.App.DB.LockPolicy(),
+        ClipboardSeconds:   int(h.App.DB.ClipboardTimeout().Seconds()),
+    }
+}
+
+// Current per-vault settings
+func (h *Handler) GetVaultSettings(c echo.Context) error {
+    return c.JSON(http.StatusOK, h.currentVaultSettings())
+}
+
+// Update per-vault settings; toggling seal_records converts every entry
+func (h *Handler) UpdateVaultSettings(c echo.Context) error {
+    var req vaultSettingsUpdate
+    if err := c.Bind(&req); err != nil {
+        return c.JSON(http.StatusBadRequest, utils.JSONError("Invalid request body"))
+    }
+    if req.TrashRetentionDays != nil && *req.TrashRetentionDays < 0 {
+        return c.JSON(http.StatusBadRequest, utils.JSONError("trash_retention_days must not be negative"))
+    }
+    if req.AutoLock != nil {
+        if err := req.AutoLock.Validate(); err != nil {
+            return c.JSON(http.StatusBadRequest, utils.JSONError(err.Error()))
+        }
+    }
+    if req.ClipboardSeconds != nil {
+        d := time.Duration(*req.ClipboardSeconds) * time.Second
+        if d < utils.MinClipboardTimeout || d > utils.MaxClipboardTimeout {
+            return c.JSON(http.StatusBadRequest, utils.JSONError(fmt.Sprintf("clipboard_clear_seconds must be between %d and %d",
+                int(utils.MinClipboardTimeout.Seconds()), int(utils.MaxClipboardTimeout.Seconds()))))
+        }
+    }
+
+    if req.SealRecords != nil {
+        if err := h.App.DB.SetSealRecords(*req.SealRecords); err != nil {
+            h.App.Logger.Error("Seal records error:", err)
+            return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to update vault settings"))
+        }
+    }
+    if req.TrashRetentionDays != nil {
+        if err := h.App.DB.SetTrashRetentionDays(*req.TrashRetentionDays); err != nil {
+            return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to update vault settings"))
+        }
+    }
+    if req.AutoLock != nil {
+        if err := h.App.DB.SetLockPolicy(*req.AutoLock); err != nil {
+            return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to update vault settings"))
+        }
+    }
+    if req.ClipboardSeconds != nil {
+        if err := h.App.DB.SetClipboardTimeout(time.Duration(*req.ClipboardSeconds) * time.Second); err != nil {
+            return c.JSON(http.StatusInternalServerError, utils.JSONError("Failed to update vault settings"))
+        }
+    }
+    return c.JSON(http.StatusOK, h.currentVaultSettings())
+}
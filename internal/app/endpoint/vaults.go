@@ -0,0 +1,88 @@
+package endpoint
+
+import (
+    "net/http"
+    "net/url"
+    "time"
+
+    "password-manager/internal/app"
+    "password-manager/pkg/utils"
+
+    "github.com/labstack/echo/v4"
+)
+
+// Clients pick a vault with the /vaults/:vault path prefix or this header;
+// without either the first registered vault is used
+const vaultHeader = "X-Vault"
+
+// Vault served by the REST API; every vault has its own master password and sessions
+type Vault struct {
+    Name string
+    App  *app.App
+}
+
+type vaultRouter struct {
+    names    []string
+    handlers map[string]*Handler
+}
+
+// RegisterVaults registers every route twice: at the root (vault from X-Vault
+// or the default one) and under /vaults/:vault
+func RegisterVaults(e *echo.Echo, vaults []Vault, sessionTTL time.Duration) {
+    vr := &vaultRouter{handlers: make(map[string]*Handler, len(vaults))}
+    for _, v := range vaults {
+        vr.names = append(vr.names, v.Name)
+        vr.handlers[v.Name] = &Handler{App: v.App, Sessions: newSessionStore(sessionTTL), Unlocks: &unlockLimiter{}}
+    }
+
+    e.GET("/vaults", vr.list)
+    for _, r := range routes {
+        h := vr.wrap(r)
+        e.Add(r.method, r.path, h)
+        e.Add(r.method, "/vaults/:vault"+r.path, h)
+    }
+}
+
+// Names of the served vaults, the default one first
+func (vr *vaultRouter) list(c echo.Context) error {
+    return c.JSON(http.StatusOK, map[string][]string{"vaults": vr.names})
+}
+
+// handler resolves the vault of the request; the path prefix wins,
+// a conflicting X-Vault header is an error
+func (vr *vaultRouter) handler(c echo.Context) (*Handler, int, string) {
+    header := c.Request().Header.Get(vaultHeader)
+    name := header
+    if raw := c.Param("vault"); raw != "" {
+        unescaped, err := url.PathUnescape(raw)
+        if err != nil {
+            return nil, http.StatusBadRequest, "Invalid vault name"
+        }
+        if header != "" && header != unescaped {
+            return nil, http.StatusBadRequest, vaultHeader + " header does not match the vault in the path"
+        }
+        name = unescaped
+    }
+    if name == "" {
+        name = vr.names[0]
+    }
+    h, ok := vr.handlers[name]
+    if !ok {
+        return nil, http.StatusNotFound, "Unknown vault"
+    }
+    return h, 0, ""
+}
+
+func (vr *vaultRouter) wrap(r route) echo.HandlerFunc {
+    return func(c echo.Context) error {
+        h, status, msg := vr.handler(c)
+        if h == nil {
+            return c.JSON(status, utils.JSONError(msg))
+        }
+        next := func(c echo.Context) error { return r.handler(h, c) }
+        if !r.public {
+            next = h.RequireSession(next)
+        }
+        return next(c)
+    }
+}
@@ -0,0 +1,110 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"password-manager/internal/app/model"
+)
+
+// Незашифрованный JSON-экспорт Bitwarden (Tools → Export vault → .json)
+
+const (
+	bitwardenLogin = 1
+
+	bitwardenFieldText    = 0
+	bitwardenFieldHidden  = 1
+	bitwardenFieldBoolean = 2
+)
+
+type bitwardenExport struct {
+	Encrypted bool `json:"encrypted"`
+	Folders   []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"folders"`
+	Items []bitwardenItem `json:"items"`
+}
+
+type bitwardenItem struct {
+	Type     int     `json:"type"`
+	Name     string  `json:"name"`
+	Notes    *string `json:"notes"`
+	FolderID *string `json:"folderId"`
+	Fields   []struct {
+		Name  string  `json:"name"`
+		Value *string `json:"value"`
+		Type  int     `json:"type"`
+	} `json:"fields"`
+	Login *struct {
+		URIs []struct {
+			URI *string `json:"uri"`
+		} `json:"uris"`
+		Username *string `json:"username"`
+		Password *string `json:"password"`
+		TOTP     *string `json:"totp"`
+	} `json:"login"`
+}
+
+func str(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// parseBitwarden: переносятся только логины; заметки, карты и личности пропускаются
+func parseBitwarden(data []byte) ([]Item, error) {
+	var exp bitwardenExport
+	if err := json.Unmarshal(data, &exp); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	if exp.Encrypted {
+		return nil, errors.New("encrypted Bitwarden exports are not supported, export as plain JSON")
+	}
+
+	folders := map[string]string{}
+	for _, f := range exp.Folders {
+		folders[f.ID] = f.Name
+	}
+
+	var items []Item
+	for _, bw := range exp.Items {
+		if bw.Type != bitwardenLogin || bw.Login == nil {
+			continue
+		}
+		p := model.Password{
+			Service:  bw.Name,
+			Username: str(bw.Login.Username),
+			Password: str(bw.Login.Password),
+		}
+		// первая ссылка — основная, остальные в поля
+		for i, u := range bw.Login.URIs {
+			if i == 0 {
+				p.Link = str(u.URI)
+				continue
+			}
+			p.Fields = append(p.Fields, model.CustomField{Name: "url", Type: model.FieldURL, Value: str(u.URI)})
+		}
+		if totp := str(bw.Login.TOTP); totp != "" {
+			p.TOTPSecret = &totp
+		}
+		if notes := str(bw.Notes); notes != "" {
+			p.Notes = &notes
+		}
+		for _, f := range bw.Fields {
+			kind := model.FieldText
+			switch f.Type {
+			case bitwardenFieldHidden:
+				kind = model.FieldHidden
+			case bitwardenFieldText, bitwardenFieldBoolean:
+			default:
+				continue // linked-поля ссылаются на другие, своего значения нет
+			}
+			p.Fields = append(p.Fields, model.CustomField{Name: f.Name, Type: kind, Value: str(f.Value)})
+		}
+		items = append(items, Item{Entry: p, Folder: folders[str(bw.FolderID)]})
+	}
+	return items, nil
+}
@@ -0,0 +1,63 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	"password-manager/internal/app/model"
+)
+
+// CSV-экспорт паролей браузеров. Колонки ищутся по заголовку:
+//
+//	Chrome:  name,url,username,password[,note]
+//	Firefox: "url","username","password","httpRealm","formActionOrigin","guid",...
+func parseBrowserCSV(data []byte) ([]Item, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // BOM
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: empty file", ErrInvalidFile)
+	}
+
+	col := map[string]int{}
+	for i, name := range rows[0] {
+		col[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"url", "username", "password"} {
+		if _, ok := col[required]; !ok {
+			return nil, fmt.Errorf("%w: no %q column (expected a Chrome or Firefox export)", ErrInvalidFile, required)
+		}
+	}
+	get := func(row []string, name string) string {
+		i, ok := col[name]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return row[i]
+	}
+
+	var items []Item
+	for _, row := range rows[1:] {
+		p := model.Password{
+			Service:  strings.TrimSpace(get(row, "name")), // у Firefox нет — возьмётся хост из ссылки
+			Link:     strings.TrimSpace(get(row, "url")),
+			Username: strings.TrimSpace(get(row, "username")),
+			Password: get(row, "password"),
+		}
+		if p.Link == "" && p.Username == "" && p.Password == "" {
+			continue
+		}
+		if note := get(row, "note"); note != "" {
+			p.Notes = &note
+		}
+		items = append(items, Item{Entry: p})
+	}
+	return items, nil
+}
@@ -0,0 +1,229 @@
+// Package importer переносит записи из других менеджеров паролей:
+// Bitwarden (незашифрованный JSON), KeePass (KDBX 4) и CSV-экспорт
+// Chrome/Firefox. Файл сначала разбирается в []Item, затем Preview
+// показывает дубликаты и итоговые категории, и только Apply пишет записи
+// в хранилище через Storage.CreatePasswords (одной транзакцией, с шифрованием).
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"password-manager/internal/app/db"
+	"password-manager/internal/app/model"
+	"password-manager/pkg/security"
+)
+
+// Поддерживаемые форматы
+const (
+	FormatBitwarden = "bitwarden"
+	FormatKeePass   = "keepass"
+	FormatCSV       = "csv" // Chrome и Firefox, различаются по заголовку
+)
+
+var Formats = []string{FormatBitwarden, FormatKeePass, FormatCSV}
+
+// больше этого файлы не читаем
+const maxImportSize = 64 << 20
+
+var (
+	ErrUnknownFormat = errors.New("unknown import format")
+	ErrInvalidFile   = errors.New("invalid import file")
+)
+
+// Item: запись из чужого менеджера, ещё не записанная в хранилище
+type Item struct {
+	Entry  model.Password
+	Folder string // папка Bitwarden / группа KeePass; пусто — без папки
+}
+
+// DetectFormat угадывает формат по расширению файла ("" — не удалось)
+func DetectFormat(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return FormatBitwarden
+	case ".kdbx":
+		return FormatKeePass
+	case ".csv":
+		return FormatCSV
+	}
+	return ""
+}
+
+// Parse разбирает файл; password нужен только для KeePass
+func Parse(format string, r io.Reader, password string) ([]Item, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxImportSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxImportSize {
+		return nil, fmt.Errorf("%w: file too large", ErrInvalidFile)
+	}
+
+	var items []Item
+	switch format {
+	case FormatBitwarden:
+		items, err = parseBitwarden(data)
+	case FormatKeePass:
+		items, err = parseKeePass(data, password)
+	case FormatCSV:
+		items, err = parseBrowserCSV(data)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		normalize(&items[i].Entry)
+	}
+	return items, nil
+}
+
+// normalize приводит запись к тому, что примет CreatePassword:
+// непонятный TOTP и некорректные поля не должны ронять весь импорт
+func normalize(p *model.Password) {
+	if p.Service == "" {
+		p.Service = hostOf(p.Link)
+	}
+	for i, f := range p.Fields {
+		if f.Name == "" {
+			p.Fields[i].Name = "field"
+		}
+		if p.Fields[i].Validate() != nil {
+			p.Fields[i].Type = model.FieldText
+		}
+	}
+	if p.TOTPSecret != nil {
+		if *p.TOTPSecret == "" {
+			p.TOTPSecret = nil
+		} else if _, err := security.ParseTOTP(*p.TOTPSecret); err != nil {
+			// сохраняем как есть, чтобы не потерять
+			p.Fields = append(p.Fields, model.CustomField{Name: "otp", Type: model.FieldHidden, Value: *p.TOTPSecret})
+			p.TOTPSecret = nil
+		}
+	}
+}
+
+// Folders: все папки из файла, для сопоставления с папками хранилища
+func Folders(items []Item) []string {
+	seen := map[string]bool{}
+	var folders []string
+	for _, it := range items {
+		if it.Folder != "" && !seen[it.Folder] {
+			seen[it.Folder] = true
+			folders = append(folders, it.Folder)
+		}
+	}
+	sort.Strings(folders)
+	return folders
+}
+
+// Mapping: папка файла → путь папки хранилища. Папки без сопоставления
+// переносятся с тем же путём (вложенность сохраняется); пустое значение —
+// без папки.
+type Mapping map[string]string
+
+func (m Mapping) category(it Item) string {
+	if it.Folder == "" {
+		return it.Entry.Category
+	}
+	if c, ok := m[it.Folder]; ok {
+		return c
+	}
+	return it.Folder
+}
+
+// PreviewItem: как запись ляжет в хранилище. Пароль в JSON не попадает.
+type PreviewItem struct {
+	Entry       model.Password `json:"-"` // категория уже подставлена
+	Service     string         `json:"service"`
+	Username    string         `json:"username"`
+	Link        string         `json:"link"`
+	Folder      string         `json:"folder,omitempty"`
+	Category    string         `json:"category"`
+	DuplicateOf int            `json:"duplicate_of,omitempty"` // ID существующей записи
+}
+
+// Preview применяет сопоставление папок и ищет дубликаты среди existing:
+// тот же логин и тот же сайт (по хосту ссылки) или то же имя сервиса
+func Preview(items []Item, existing []model.PasswordListItem, mapping Mapping) []PreviewItem {
+	out := make([]PreviewItem, 0, len(items))
+	for _, it := range items {
+		p := it.Entry
+		p.Category = mapping.category(it)
+		out = append(out, PreviewItem{
+			Entry:       p,
+			Service:     p.Service,
+			Username:    p.Username,
+			Link:        p.Link,
+			Folder:      it.Folder,
+			Category:    p.Category,
+			DuplicateOf: findDuplicate(p, existing),
+		})
+	}
+	return out
+}
+
+func findDuplicate(p model.Password, existing []model.PasswordListItem) int {
+	host := hostOf(p.Link)
+	for _, e := range existing {
+		if !strings.EqualFold(strings.TrimSpace(e.Username), strings.TrimSpace(p.Username)) {
+			continue
+		}
+		if host != "" && host == hostOf(e.Link) {
+			return e.ID
+		}
+		if strings.EqualFold(strings.TrimSpace(e.Service), strings.TrimSpace(p.Service)) {
+			return e.ID
+		}
+	}
+	return 0
+}
+
+// hostOf: хост ссылки без www. ("" — не ссылка)
+func hostOf(link string) string {
+	link = strings.TrimSpace(link)
+	if link == "" {
+		return ""
+	}
+	if !strings.Contains(link, "://") {
+		link = "https://" + link
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+type Result struct {
+	Imported int `json:"imported"`
+	Skipped  int `json:"skipped"` // дубликаты при skipDuplicates
+}
+
+// Apply пишет записи одной транзакцией (storage.CreatePasswords): ошибка
+// на любой записи откатывает импорт целиком, и его можно просто повторить.
+// Номер записи в ошибке считается без пропущенных дубликатов.
+func Apply(storage db.Storage, items []PreviewItem, skipDuplicates bool) (Result, error) {
+	var res Result
+	list := make([]model.Password, 0, len(items))
+	for _, it := range items {
+		if skipDuplicates && it.DuplicateOf != 0 {
+			res.Skipped++
+			continue
+		}
+		list = append(list, it.Entry)
+	}
+	n, err := storage.CreatePasswords(list)
+	if err != nil {
+		return Result{}, err
+	}
+	res.Imported = n
+	return res, nil
+}
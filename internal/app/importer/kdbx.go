@@ -0,0 +1,443 @@
+package importer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20"
+	"golang.org/x/crypto/salsa20/salsa"
+
+	"password-manager/pkg/security"
+)
+
+// Чтение контейнера KeePass KDBX 4 (только мастер-пароль, без файла-ключа).
+// Формат: https://keepass.info/help/kb/kdbx_4.html
+// Расшифровка целиком локальная; на выходе — XML базы с уже открытыми
+// защищёнными значениями (см. keepass.go).
+
+var ErrKeePassCredentials = errors.New("wrong KeePass password or damaged file")
+
+// ErrKeePassKDFLimits: вывод ключа по параметрам файла дороже, чем мы
+// позволяем при импорте (security.MaxImport*); файл при этом может быть цел
+var ErrKeePassKDFLimits = errors.New("KeePass key derivation settings exceed the import limits")
+
+const (
+	kdbxSignature1 = 0x9AA2D903
+	kdbxSignature2 = 0xB54BFB67
+
+	// поля внешнего заголовка
+	kdbxEndOfHeader  = 0
+	kdbxCipherID     = 2
+	kdbxCompression  = 3
+	kdbxMasterSeed   = 4
+	kdbxEncryptionIV = 7
+	kdbxKdfParams    = 11
+
+	// поля внутреннего заголовка
+	kdbxInnerEnd       = 0
+	kdbxInnerStreamID  = 1
+	kdbxInnerStreamKey = 2
+
+	kdbxStreamSalsa20  = 2
+	kdbxStreamChaCha20 = 3
+)
+
+var (
+	kdbxCipherAES256   = mustUUID("31c1f2e6bf714350be5805216afc5aff")
+	kdbxCipherChaCha20 = mustUUID("d6038a2b8b6f4cb5a524339a31dbb59a")
+
+	kdbxKdfAES      = mustUUID("c9d9f39a628a4460bf740d08c18a4fea")
+	kdbxKdfAESKDBX4 = mustUUID("7c02bb8279a74ac0927d114a00648238") // так пишет KeePassXC
+	kdbxKdfArgon2d  = mustUUID("ef636ddf8c29444b91f7a9a403e30a0c")
+	kdbxKdfArgon2id = mustUUID("9e298b1956db4773b23dfc3ec6f0a1e6")
+)
+
+func mustUUID(s string) string {
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != 16 {
+		panic("bad uuid " + s)
+	}
+	return string(b)
+}
+
+type kdbxHeader struct {
+	cipherID   string
+	compressed bool
+	masterSeed []byte
+	iv         []byte
+	kdf        map[string][]byte // VariantDictionary, значения как есть
+}
+
+// openKDBX возвращает XML базы и генератор потока для защищённых значений
+func openKDBX(data []byte, password string) ([]byte, cipher.Stream, error) {
+	r := bytes.NewReader(data)
+	var sig [3]uint32
+	if err := binary.Read(r, binary.LittleEndian, &sig); err != nil {
+		return nil, nil, fmt.Errorf("%w: not a KeePass database", ErrInvalidFile)
+	}
+	if sig[0] != kdbxSignature1 || sig[1] != kdbxSignature2 {
+		return nil, nil, fmt.Errorf("%w: not a KeePass database", ErrInvalidFile)
+	}
+	if major := sig[2] >> 16; major != 4 {
+		return nil, nil, fmt.Errorf("%w: KDBX %d is not supported, save the database as KDBX 4", ErrInvalidFile, major)
+	}
+
+	h, err := readKDBXHeader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	headerLen := len(data) - r.Len()
+	headerBytes := data[:headerLen]
+
+	var headerHash, headerMAC [32]byte
+	if _, err := io.ReadFull(r, headerHash[:]); err != nil {
+		return nil, nil, fmt.Errorf("%w: truncated header", ErrInvalidFile)
+	}
+	if _, err := io.ReadFull(r, headerMAC[:]); err != nil {
+		return nil, nil, fmt.Errorf("%w: truncated header", ErrInvalidFile)
+	}
+	if sum := sha256.Sum256(headerBytes); !hmac.Equal(sum[:], headerHash[:]) {
+		return nil, nil, fmt.Errorf("%w: header checksum mismatch", ErrInvalidFile)
+	}
+
+	// Ключи: составной ключ → KDF → ключ шифрования и база для HMAC
+	pw := sha256.Sum256([]byte(password))
+	composite := sha256.Sum256(pw[:])
+	transformed, err := h.transformKey(composite[:])
+	if err != nil {
+		return nil, nil, err
+	}
+	encKey := sha256.Sum256(concat(h.masterSeed, transformed))
+	hmacBase := sha512.Sum512(concat(h.masterSeed, transformed, []byte{1}))
+
+	if !hmac.Equal(kdbxMAC(hmacBase[:], ^uint64(0), headerBytes), headerMAC[:]) {
+		return nil, nil, ErrKeePassCredentials
+	}
+
+	payload, err := readKDBXBlocks(r, hmacBase[:])
+	if err != nil {
+		return nil, nil, err
+	}
+	plain, err := h.decrypt(encKey[:], payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	if h.compressed {
+		zr, err := gzip.NewReader(bytes.NewReader(plain))
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		if plain, err = io.ReadAll(io.LimitReader(zr, maxImportSize*4)); err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+	}
+	return readKDBXInner(plain)
+}
+
+func readKDBXHeader(r *bytes.Reader) (*kdbxHeader, error) {
+	h := &kdbxHeader{}
+	for {
+		id, value, err := readKDBXField(r)
+		if err != nil {
+			return nil, err
+		}
+		switch id {
+		case kdbxEndOfHeader:
+			if h.cipherID == "" || len(h.masterSeed) != 32 || h.kdf == nil {
+				return nil, fmt.Errorf("%w: incomplete header", ErrInvalidFile)
+			}
+			return h, nil
+		case kdbxCipherID:
+			h.cipherID = string(value)
+		case kdbxCompression:
+			h.compressed = len(value) == 4 && binary.LittleEndian.Uint32(value) == 1
+		case kdbxMasterSeed:
+			h.masterSeed = value
+		case kdbxEncryptionIV:
+			h.iv = value
+		case kdbxKdfParams:
+			if h.kdf, err = readVariantDictionary(value); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// readKDBXField: [id:1][длина:4][данные] — так устроены оба заголовка
+func readKDBXField(r *bytes.Reader) (byte, []byte, error) {
+	id, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w: truncated header", ErrInvalidFile)
+	}
+	var size uint32
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil || int64(size) > int64(r.Len()) {
+		return 0, nil, fmt.Errorf("%w: truncated header", ErrInvalidFile)
+	}
+	value := make([]byte, size)
+	if _, err := io.ReadFull(r, value); err != nil {
+		return 0, nil, fmt.Errorf("%w: truncated header", ErrInvalidFile)
+	}
+	return id, value, nil
+}
+
+// readVariantDictionary: параметры KDF; типы значений здесь не важны —
+// каждый параметр читается тем размером, которого ждёт KDF
+func readVariantDictionary(data []byte) (map[string][]byte, error) {
+	bad := fmt.Errorf("%w: invalid KDF parameters", ErrInvalidFile)
+	if len(data) < 2 || data[1] != 0x01 {
+		return nil, bad
+	}
+	data = data[2:]
+	dict := map[string][]byte{}
+	for {
+		if len(data) < 1 {
+			return nil, bad
+		}
+		if data[0] == 0 {
+			return dict, nil
+		}
+		data = data[1:]
+		var key, value []byte
+		for _, dst := range []*[]byte{&key, &value} {
+			if len(data) < 4 {
+				return nil, bad
+			}
+			n := binary.LittleEndian.Uint32(data)
+			if uint64(n) > uint64(len(data)-4) {
+				return nil, bad
+			}
+			*dst, data = data[4:4+n], data[4+n:]
+		}
+		dict[string(key)] = value
+	}
+}
+
+func (h *kdbxHeader) kdfUint(key string, size int) (uint64, error) {
+	v := h.kdf[key]
+	switch {
+	case size == 4 && len(v) == 4:
+		return uint64(binary.LittleEndian.Uint32(v)), nil
+	case size == 8 && len(v) == 8:
+		return binary.LittleEndian.Uint64(v), nil
+	}
+	return 0, fmt.Errorf("%w: KDF parameter %s", ErrInvalidFile, key)
+}
+
+func (h *kdbxHeader) transformKey(composite []byte) ([]byte, error) {
+	salt := h.kdf["S"]
+	switch string(h.kdf["$UUID"]) {
+	case kdbxKdfAES, kdbxKdfAESKDBX4:
+		rounds, err := h.kdfUint("R", 8)
+		if err != nil {
+			return nil, err
+		}
+		if rounds > security.MaxAESKDFRounds {
+			return nil, fmt.Errorf("%w (AES-KDF: rounds≤%d)", ErrKeePassKDFLimits, security.MaxAESKDFRounds)
+		}
+		block, err := aes.NewCipher(salt)
+		if err != nil {
+			return nil, fmt.Errorf("%w: AES-KDF seed", ErrInvalidFile)
+		}
+		key := append([]byte(nil), composite...)
+		for i := uint64(0); i < rounds; i++ {
+			block.Encrypt(key[:16], key[:16])
+			block.Encrypt(key[16:], key[16:])
+		}
+		sum := sha256.Sum256(key)
+		return sum[:], nil
+
+	case kdbxKdfArgon2d, kdbxKdfArgon2id:
+		iterations, err := h.kdfUint("I", 8)
+		if err != nil {
+			return nil, err
+		}
+		memory, err := h.kdfUint("M", 8)
+		if err != nil {
+			return nil, err
+		}
+		parallelism, err := h.kdfUint("P", 4)
+		if err != nil {
+			return nil, err
+		}
+		version, err := h.kdfUint("V", 4)
+		if err != nil {
+			return nil, err
+		}
+		if version != 0x13 {
+			return nil, fmt.Errorf("%w: Argon2 version %#x is not supported", ErrInvalidFile, version)
+		}
+		if iterations == 0 || parallelism == 0 || memory < 8*1024 {
+			return nil, fmt.Errorf("%w: Argon2 parameters out of range", ErrInvalidFile)
+		}
+		if iterations > security.MaxImportArgon2Iterations || memory/1024 > security.MaxImportArgon2Memory ||
+			parallelism > security.MaxImportArgon2Parallelism {
+			return nil, fmt.Errorf("%w (Argon2: t≤%d, m≤%d MiB, p≤%d)", ErrKeePassKDFLimits,
+				security.MaxImportArgon2Iterations, security.MaxImportArgon2Memory/1024, security.MaxImportArgon2Parallelism)
+		}
+		// в KDBX память в байтах, у argon2 — в KiB
+		if string(h.kdf["$UUID"]) == kdbxKdfArgon2d {
+			return security.Argon2dKey(composite, salt, uint32(iterations), uint32(memory/1024), uint8(parallelism), 32), nil
+		}
+		return argon2.IDKey(composite, salt, uint32(iterations), uint32(memory/1024), uint8(parallelism), 32), nil
+	}
+	return nil, fmt.Errorf("%w: unsupported KDF", ErrInvalidFile)
+}
+
+// kdbxMAC: HMAC-SHA256 с ключом блока index (заголовок — index = 2^64-1)
+func kdbxMAC(hmacBase []byte, index uint64, parts ...[]byte) []byte {
+	var idx [8]byte
+	binary.LittleEndian.PutUint64(idx[:], index)
+	key := sha512.Sum512(concat(idx[:], hmacBase))
+	mac := hmac.New(sha256.New, key[:])
+	for _, p := range parts {
+		mac.Write(p)
+	}
+	return mac.Sum(nil)
+}
+
+// readKDBXBlocks: поток блоков [HMAC:32][длина:4][данные], пустой блок — конец
+func readKDBXBlocks(r *bytes.Reader, hmacBase []byte) ([]byte, error) {
+	var out []byte
+	for index := uint64(0); ; index++ {
+		var mac [32]byte
+		var size [4]byte
+		if _, err := io.ReadFull(r, mac[:]); err != nil {
+			return nil, fmt.Errorf("%w: truncated data", ErrInvalidFile)
+		}
+		if _, err := io.ReadFull(r, size[:]); err != nil {
+			return nil, fmt.Errorf("%w: truncated data", ErrInvalidFile)
+		}
+		n := binary.LittleEndian.Uint32(size[:])
+		if int64(n) > int64(r.Len()) {
+			return nil, fmt.Errorf("%w: truncated data", ErrInvalidFile)
+		}
+		block := make([]byte, n)
+		if _, err := io.ReadFull(r, block); err != nil {
+			return nil, fmt.Errorf("%w: truncated data", ErrInvalidFile)
+		}
+		var idx [8]byte
+		binary.LittleEndian.PutUint64(idx[:], index)
+		if !hmac.Equal(kdbxMAC(hmacBase, index, idx[:], size[:], block), mac[:]) {
+			return nil, fmt.Errorf("%w: block %d is damaged", ErrInvalidFile, index)
+		}
+		if n == 0 {
+			return out, nil
+		}
+		out = append(out, block...)
+	}
+}
+
+func (h *kdbxHeader) decrypt(key, data []byte) ([]byte, error) {
+	switch h.cipherID {
+	case kdbxCipherAES256:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		if len(h.iv) != aes.BlockSize || len(data) == 0 || len(data)%aes.BlockSize != 0 {
+			return nil, fmt.Errorf("%w: bad AES payload", ErrInvalidFile)
+		}
+		plain := make([]byte, len(data))
+		cipher.NewCBCDecrypter(block, h.iv).CryptBlocks(plain, data)
+		pad := int(plain[len(plain)-1])
+		if pad == 0 || pad > aes.BlockSize {
+			return nil, fmt.Errorf("%w: bad padding", ErrInvalidFile)
+		}
+		return plain[:len(plain)-pad], nil
+	case kdbxCipherChaCha20:
+		c, err := chacha20.NewUnauthenticatedCipher(key, h.iv)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		plain := make([]byte, len(data))
+		c.XORKeyStream(plain, data)
+		return plain, nil
+	}
+	return nil, fmt.Errorf("%w: unsupported cipher (only AES-256 and ChaCha20)", ErrInvalidFile)
+}
+
+// readKDBXInner: внутренний заголовок (поток для защищённых значений,
+// вложения) и XML после него
+func readKDBXInner(data []byte) ([]byte, cipher.Stream, error) {
+	r := bytes.NewReader(data)
+	var streamID uint32
+	var streamKey []byte
+	for {
+		id, value, err := readKDBXField(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		if id == kdbxInnerEnd {
+			break
+		}
+		switch id {
+		case kdbxInnerStreamID:
+			if len(value) == 4 {
+				streamID = binary.LittleEndian.Uint32(value)
+			}
+		case kdbxInnerStreamKey:
+			streamKey = value
+		}
+	}
+	xmlData := data[len(data)-r.Len():]
+
+	switch streamID {
+	case kdbxStreamChaCha20:
+		sum := sha512.Sum512(streamKey)
+		c, err := chacha20.NewUnauthenticatedCipher(sum[:32], sum[32:44])
+		if err != nil {
+			return nil, nil, err
+		}
+		return xmlData, c, nil
+	case kdbxStreamSalsa20:
+		key := sha256.Sum256(streamKey)
+		return xmlData, newSalsa20Stream(key, [8]byte{0xE8, 0x30, 0x09, 0x4B, 0x97, 0x20, 0x5D, 0x2A}), nil
+	}
+	return nil, nil, fmt.Errorf("%w: unsupported inner stream %d", ErrInvalidFile, streamID)
+}
+
+// salsa20Stream: потоковый Salsa20 (в x/crypto есть только «одним куском»)
+type salsa20Stream struct {
+	key     [32]byte
+	counter [16]byte // nonce:8 || номер блока:8
+	buf     [64]byte
+	pos     int
+}
+
+func newSalsa20Stream(key [32]byte, nonce [8]byte) *salsa20Stream {
+	s := &salsa20Stream{key: key, pos: 64}
+	copy(s.counter[:8], nonce[:])
+	return s
+}
+
+func (s *salsa20Stream) XORKeyStream(dst, src []byte) {
+	for i := range src {
+		if s.pos == 64 {
+			var zero [64]byte
+			salsa.XORKeyStream(s.buf[:], zero[:], &s.counter, &s.key)
+			binary.LittleEndian.PutUint64(s.counter[8:], binary.LittleEndian.Uint64(s.counter[8:])+1)
+			s.pos = 0
+		}
+		dst[i] = src[i] ^ s.buf[s.pos]
+		s.pos++
+	}
+}
+
+func concat(parts ...[]byte) []byte {
+	var out []byte
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
@@ -0,0 +1,207 @@
+package importer
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"password-manager/internal/app/model"
+)
+
+// xmlNode: XML базы KeePass деревом. Защищённые значения XOR-ятся с
+// внутренним потоком строго в порядке документа, поэтому сначала дерево,
+// потом обход.
+type xmlNode struct {
+	name     string
+	attrs    []xml.Attr
+	text     string
+	children []*xmlNode
+}
+
+func (n *xmlNode) child(name string) *xmlNode {
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func (n *xmlNode) childText(name string) string {
+	if c := n.child(name); c != nil {
+		return c.text
+	}
+	return ""
+}
+
+func (n *xmlNode) attr(name string) string {
+	for _, a := range n.attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func parseXMLTree(data []byte) (*xmlNode, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	root := &xmlNode{}
+	stack := []*xmlNode{root}
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			if len(stack) == 1 && len(root.children) > 0 {
+				return root.children[0], nil
+			}
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		top := stack[len(stack)-1]
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &xmlNode{name: t.Name.Local, attrs: t.Attr}
+			top.children = append(top.children, n)
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			top.text += string(t)
+		}
+	}
+}
+
+// unprotect расшифровывает все <Value Protected="True"> в порядке документа
+func unprotect(n *xmlNode, stream cipher.Stream) error {
+	if n.name == "Value" && strings.EqualFold(n.attr("Protected"), "True") {
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(n.text))
+		if err != nil {
+			return fmt.Errorf("%w: protected value", ErrInvalidFile)
+		}
+		stream.XORKeyStream(raw, raw)
+		n.text = string(raw)
+	}
+	for _, c := range n.children {
+		if err := unprotect(c, stream); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseKeePass(data []byte, password string) ([]Item, error) {
+	xmlData, stream, err := openKDBX(data, password)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := parseXMLTree(xmlData)
+	if err != nil {
+		return nil, err
+	}
+	if err := unprotect(doc, stream); err != nil {
+		return nil, err
+	}
+
+	root := doc.child("Root")
+	if root == nil || root.child("Group") == nil {
+		return nil, fmt.Errorf("%w: no root group", ErrInvalidFile)
+	}
+	// корзину KeePass не переносим
+	var recycleBin string
+	if meta := doc.child("Meta"); meta != nil && strings.EqualFold(meta.childText("RecycleBinEnabled"), "True") {
+		recycleBin = strings.TrimSpace(meta.childText("RecycleBinUUID"))
+	}
+
+	var items []Item
+	var walk func(g *xmlNode, path string)
+	walk = func(g *xmlNode, path string) {
+		for _, c := range g.children {
+			switch c.name {
+			case "Entry":
+				items = append(items, Item{Entry: keePassEntry(c), Folder: path})
+			case "Group":
+				if recycleBin != "" && strings.TrimSpace(c.childText("UUID")) == recycleBin {
+					continue
+				}
+				name := c.childText("Name")
+				if path != "" {
+					name = path + "/" + name
+				}
+				walk(c, name)
+			}
+		}
+	}
+	// корневая группа — это сама база, в путь не входит
+	walk(root.child("Group"), "")
+	return items, nil
+}
+
+// keePassEntry: стандартные поля — в поля записи, прочие строки — в
+// пользовательские поля (защищённые как скрытые). <History> пропускается.
+func keePassEntry(e *xmlNode) model.Password {
+	var p model.Password
+	strs := map[string]string{}
+	var custom []model.CustomField
+	for _, s := range e.children {
+		if s.name != "String" {
+			continue
+		}
+		key, value := s.childText("Key"), ""
+		protected := false
+		if v := s.child("Value"); v != nil {
+			value = v.text
+			protected = strings.EqualFold(v.attr("Protected"), "True")
+		}
+		switch key {
+		case "Title", "UserName", "Password", "URL", "Notes", "otp",
+			"TimeOTP-Secret-Base32", "TimeOTP-Length", "TimeOTP-Period", "TimeOTP-Algorithm":
+			strs[key] = value
+		default:
+			if value == "" {
+				continue
+			}
+			kind := model.FieldText
+			if protected {
+				kind = model.FieldHidden
+			}
+			custom = append(custom, model.CustomField{Name: key, Type: kind, Value: value})
+		}
+	}
+
+	p.Service = strs["Title"]
+	p.Username = strs["UserName"]
+	p.Password = strs["Password"]
+	p.Link = strs["URL"]
+	p.Fields = custom
+	if notes := strs["Notes"]; notes != "" {
+		p.Notes = &notes
+	}
+	// KeePassXC хранит otpauth:// в "otp", KeePass 2 — секрет в TimeOTP-*
+	totp := strs["otp"]
+	if totp == "" && strs["TimeOTP-Secret-Base32"] != "" {
+		totp = keePassTOTP(strs, p)
+	}
+	if totp != "" {
+		p.TOTPSecret = &totp
+	}
+	return p
+}
+
+func keePassTOTP(strs map[string]string, p model.Password) string {
+	q := url.Values{}
+	q.Set("secret", strings.ReplaceAll(strs["TimeOTP-Secret-Base32"], " ", ""))
+	if v := strs["TimeOTP-Length"]; v != "" {
+		q.Set("digits", v)
+	}
+	if v := strs["TimeOTP-Period"]; v != "" {
+		q.Set("period", v)
+	}
+	// HMAC-SHA-256 → SHA256
+	if v := strs["TimeOTP-Algorithm"]; v != "" {
+		q.Set("algorithm", strings.ReplaceAll(strings.TrimPrefix(strings.ToUpper(v), "HMAC-"), "-", ""))
+	}
+	u := url.URL{Scheme: "otpauth", Host: "totp", Path: "/" + p.Service + ":" + p.Username, RawQuery: q.Encode()}
+	return u.String()
+}
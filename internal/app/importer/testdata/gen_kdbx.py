@@ -0,0 +1,168 @@
+# Генератор фикстур KDBX 4 для kdbx_test.go:
+#   python3 gen_kdbx.py aes keepass_aes.kdbx
+#   python3 gen_kdbx.py argon2d keepass_argon2d.kdbx
+# Пароль базы — "kpass". Argon2d здесь своя, на чистом Python по RFC 9106,
+# независимая от pkg/security/argon2d.go. Нужен openssl (AES-256-ECB, ChaCha20).
+import hashlib, hmac, struct, gzip, base64, subprocess, sys, random
+kdf = sys.argv[1]
+_rng = random.Random("kdbx-fixture-" + kdf)
+def urandom(n): return bytes(_rng.getrandbits(8) for _ in range(n))
+pw = b"kpass"
+def ossl(args, data):
+    return subprocess.run(["openssl","enc"]+args, input=data, capture_output=True, check=True).stdout
+def sha256(b): return hashlib.sha256(b).digest()
+def sha512(b): return hashlib.sha512(b).digest()
+composite = sha256(sha256(pw))
+# Независимая (чистый Python) Argon2d по RFC 9106 — только для генерации фикстур
+import hashlib, struct
+M64 = (1 << 64) - 1
+def le32(x): return struct.pack("<I", x)
+def blake(data, n): return hashlib.blake2b(data, digest_size=n).digest()
+def hprime(x, T):
+    if T <= 64: return blake(le32(T) + x, T)
+    r = (T + 31) // 32 - 2
+    v = blake(le32(T) + x, 64); out = v[:32]
+    for _ in range(r - 1):
+        v = blake(v, 64); out += v[:32]
+    return out + blake(v, T - 32 * r)
+def rotr(x, n): return ((x >> n) | (x << (64 - n))) & M64
+def gb(v, a, b, c, d):
+    v[a] = (v[a] + v[b] + 2 * (v[a] & 0xffffffff) * (v[b] & 0xffffffff)) & M64; v[d] = rotr(v[d] ^ v[a], 32)
+    v[c] = (v[c] + v[d] + 2 * (v[c] & 0xffffffff) * (v[d] & 0xffffffff)) & M64; v[b] = rotr(v[b] ^ v[c], 24)
+    v[a] = (v[a] + v[b] + 2 * (v[a] & 0xffffffff) * (v[b] & 0xffffffff)) & M64; v[d] = rotr(v[d] ^ v[a], 16)
+    v[c] = (v[c] + v[d] + 2 * (v[c] & 0xffffffff) * (v[d] & 0xffffffff)) & M64; v[b] = rotr(v[b] ^ v[c], 63)
+def perm(w, idx):
+    v = [w[i] for i in idx]
+    gb(v,0,4,8,12); gb(v,1,5,9,13); gb(v,2,6,10,14); gb(v,3,7,11,15)
+    gb(v,0,5,10,15); gb(v,1,6,11,12); gb(v,2,7,8,13); gb(v,3,4,9,14)
+    for i, k in enumerate(idx): w[k] = v[i]
+def G(x, y):
+    r = [a ^ b for a, b in zip(x, y)]; z = r[:]
+    for i in range(8): perm(z, list(range(16 * i, 16 * i + 16)))
+    for i in range(8): perm(z, [2 * i + 16 * j + k for j in range(8) for k in (0, 1)])
+    return [a ^ b for a, b in zip(z, r)]
+def words(b): return list(struct.unpack("<128Q", b))
+def argon2d(P, S, t, m, p, T, K=b"", X=b""):
+    h0 = blake(le32(p) + le32(T) + le32(m) + le32(t) + le32(0x13) + le32(0) +
+               le32(len(P)) + P + le32(len(S)) + S + le32(len(K)) + K + le32(len(X)) + X, 64)
+    mp = 4 * p * (m // (4 * p)); q = mp // p; sl = q // 4
+    B = [[None] * q for _ in range(p)]
+    for i in range(p):
+        B[i][0] = words(hprime(h0 + le32(0) + le32(i), 1024))
+        B[i][1] = words(hprime(h0 + le32(1) + le32(i), 1024))
+    for r in range(t):
+        for s in range(4):
+            for lane in range(p):
+                for idx in range(sl):
+                    j = s * sl + idx
+                    if r == 0 and j < 2: continue
+                    prev = B[lane][j - 1 if j > 0 else q - 1]
+                    j1, j2 = prev[0] & 0xffffffff, prev[0] >> 32
+                    l = lane if (r == 0 and s == 0) else j2 % p
+                    if l == lane:
+                        W = (s * sl + idx - 1) if r == 0 else (q - sl + idx - 1)
+                    else:
+                        W = (s * sl if r == 0 else q - sl) - (1 if idx == 0 else 0)
+                    x = (j1 * j1) >> 32; y = (W * x) >> 32; zz = W - 1 - y
+                    start = 0 if r == 0 or s == 3 else (s + 1) * sl
+                    ref = B[l][(start + zz) % q]
+                    new = G(prev, ref)
+                    if r > 0: new = [a ^ b for a, b in zip(new, B[lane][j])]
+                    B[lane][j] = new
+    c = B[0][q - 1]
+    for i in range(1, p): c = [a ^ b for a, b in zip(c, B[i][q - 1])]
+    return hprime(struct.pack("<128Q", *c), T)
+
+seed_kdf = urandom(32)
+rounds, a2_time, a2_mem, a2_par = 16, 2, 64 * 1024, 2
+if kdf == "aes":
+    k = composite
+    for _ in range(rounds):
+        k = ossl(["-aes-256-ecb","-K",seed_kdf.hex(),"-nopad"], k)
+    transformed = sha256(k)
+else:
+    transformed = argon2d(composite, seed_kdf, a2_time, a2_mem // 1024, a2_par, 32)
+master_seed = urandom(32); iv = urandom(12)
+def vd():
+    out = struct.pack("<H", 0x0100)
+    def item(t, key, val):
+        return bytes([t]) + struct.pack("<i", len(key)) + key + struct.pack("<i", len(val)) + val
+    if kdf == "aes":
+        out += item(0x42, b"$UUID", bytes.fromhex("c9d9f39a628a4460bf740d08c18a4fea"))
+        out += item(0x05, b"R", struct.pack("<Q", rounds))
+    else:
+        out += item(0x42, b"$UUID", bytes.fromhex("ef636ddf8c29444b91f7a9a403e30a0c"))
+        out += item(0x05, b"I", struct.pack("<Q", a2_time))
+        out += item(0x05, b"M", struct.pack("<Q", a2_mem))
+        out += item(0x04, b"P", struct.pack("<I", a2_par))
+        out += item(0x04, b"V", struct.pack("<I", 0x13))
+    out += item(0x42, b"S", seed_kdf)
+    return out + b"\x00"
+def field(i, d): return bytes([i]) + struct.pack("<I", len(d)) + d
+header = struct.pack("<III", 0x9AA2D903, 0xB54BFB67, 0x00040000)
+header += field(2, bytes.fromhex("d6038a2b8b6f4cb5a524339a31dbb59a"))
+header += field(3, struct.pack("<I", 1))
+header += field(4, master_seed)
+header += field(7, iv)
+header += field(11, vd())
+header += field(0, b"\r\n\r\n")
+enc_key = sha256(master_seed + transformed)
+hmac_base = sha512(master_seed + transformed + b"\x01")
+def block_key(i): return sha512(struct.pack("<Q", i) + hmac_base)
+header_hmac = hmac.new(block_key(0xFFFFFFFFFFFFFFFF), header, hashlib.sha256).digest()
+
+def rotl(v,c): return ((v<<c)&0xffffffff)|(v>>(32-c))
+def salsa_block(key, nonce, ctr):
+    c=[0x61707865,0x3320646e,0x79622d32,0x6b206574]
+    k=list(struct.unpack("<8I",key)); n=list(struct.unpack("<2I",nonce)); b=[ctr&0xffffffff, ctr>>32]
+    st=[c[0],k[0],k[1],k[2],k[3],c[1],n[0],n[1],b[0],b[1],c[2],k[4],k[5],k[6],k[7],c[3]]
+    x=st[:]
+    def qr(a,b_,c_,d):
+        x[b_]^=rotl((x[a]+x[d])&0xffffffff,7); x[c_]^=rotl((x[b_]+x[a])&0xffffffff,9)
+        x[d]^=rotl((x[c_]+x[b_])&0xffffffff,13); x[a]^=rotl((x[d]+x[c_])&0xffffffff,18)
+    for _ in range(10):
+        qr(0,4,8,12);qr(5,9,13,1);qr(10,14,2,6);qr(15,3,7,11)
+        qr(0,1,2,3);qr(5,6,7,4);qr(10,11,8,9);qr(15,12,13,14)
+    return struct.pack("<16I",*[(x[i]+st[i])&0xffffffff for i in range(16)])
+def salsa_ks(key,nonce,n):
+    out=b"";i=0
+    while len(out)<n: out+=salsa_block(key,nonce,i); i+=1
+    return out[:n]
+# inner stream
+stream_key = urandom(64)
+h = sha512(stream_key)
+protected_plain = ["S3cret-pass!", "1234", "old-pass", "binpass"]
+ks_len = sum(len(p.encode()) for p in protected_plain)
+ks = salsa_ks(sha256(stream_key), bytes.fromhex("E830094B97205D2A"), ks_len)
+pos = 0
+enc = []
+for p in protected_plain:
+    b = p.encode(); enc.append(base64.b64encode(bytes(x^y for x,y in zip(b, ks[pos:pos+len(b)]))).decode()); pos += len(b)
+bin_uuid = base64.b64encode(b"R"*16).decode()
+xml = f'''<?xml version="1.0" encoding="utf-8" standalone="yes"?>
+<KeePassFile><Meta><Generator>test</Generator><RecycleBinEnabled>True</RecycleBinEnabled><RecycleBinUUID>{bin_uuid}</RecycleBinUUID></Meta>
+<Root><Group><UUID>{base64.b64encode(b"A"*16).decode()}</UUID><Name>Database</Name>
+<Entry><UUID>x</UUID><String><Key>Title</Key><Value>RootSite</Value></String><String><Key>UserName</Key><Value>root@x.org</Value></String><String><Key>Password</Key><Value>plainpw</Value></String><String><Key>URL</Key><Value>https://root.example</Value></String></Entry>
+<Group><UUID>{base64.b64encode(b"B"*16).decode()}</UUID><Name>Internet</Name>
+<Entry><UUID>y</UUID>
+<String><Key>Title</Key><Value>GitHub</Value></String>
+<String><Key>UserName</Key><Value>octo</Value></String>
+<String><Key>Password</Key><Value Protected="True">{enc[0]}</Value></String>
+<String><Key>URL</Key><Value>https://github.com/login</Value></String>
+<String><Key>Notes</Key><Value>line1
+line2</Value></String>
+<String><Key>PIN</Key><Value Protected="True">{enc[1]}</Value></String>
+<String><Key>otp</Key><Value>otpauth://totp/GitHub:octo?secret=JBSWY3DPEHPK3PXP&amp;issuer=GitHub</Value></String>
+<History><Entry><String><Key>Password</Key><Value Protected="True">{enc[2]}</Value></String></Entry></History>
+</Entry></Group>
+<Group><UUID>{bin_uuid}</UUID><Name>Recycle Bin</Name>
+<Entry><String><Key>Title</Key><Value>Deleted</Value></String><String><Key>Password</Key><Value Protected="True">{enc[3]}</Value></String></Entry>
+</Group></Group></Root></KeePassFile>'''.encode()
+inner = field(1, struct.pack("<I", 2)) + field(2, stream_key) + field(0, b"")
+payload = gzip.compress(inner + xml, mtime=0)
+ct = ossl(["-chacha20","-K",enc_key.hex(),"-iv","00000000"+iv.hex()], payload)
+def blk(i, data):
+    mac = hmac.new(block_key(i), struct.pack("<Q", i) + struct.pack("<I", len(data)) + data, hashlib.sha256).digest()
+    return mac + struct.pack("<I", len(data)) + data
+out = header + sha256(header) + header_hmac + blk(0, ct) + blk(1, b"")
+open(sys.argv[2], "wb").write(out)
@@ -0,0 +1,27 @@
+package model
+
+import "password-manager/pkg/utils"
+
+// Entry as it travels inside an export container: everything in plaintext,
+// including the trash state and previous passwords
+type ExportEntry struct {
+    Service    string                 `json:"service"`
+    Username   string                 `json:"username"`
+    Link       string                 `json:"link"`
+    Password   string                 `json:"password"`
+    Category   string                 `json:"category"` // folder path
+    Tags       []string               `json:"tags,omitempty"`
+    CreatedAt  string                 `json:"created_at"`
+    UpdatedAt  string                 `json:"updated_at,omitempty"`  // last password change
+    TOTPSecret string                 `json:"totp_secret,omitempty"` // otpauth:// URI
+    Notes      string                 `json:"notes,omitempty"`
+    Fields     []CustomField          `json:"fields,omitempty"`
+    Generator  *utils.GeneratorPolicy `json:"generator,omitempty"`
+    History    []ExportHistory        `json:"history,omitempty"` // newest first
+    DeletedAt  string                 `json:"deleted_at,omitempty"`
+}
+
+type ExportHistory struct {
+    Password  string `json:"password"`
+    ChangedAt string `json:"changed_at"`
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+    "errors"
+    "fmt"
+    "net/mail"
+    "net/url"
+    "time"
+
+    "password-manager/pkg/utils"
+)
+
+// Типы пользовательских полей записи
+const (
+    FieldText   = "text"
+    FieldHidden = "hidden" // не показывается и не отдаётся API, только копируется
+    FieldURL    = "url"
+    FieldEmail  = "email"
+    FieldDate   = "date" // YYYY-MM-DD
+)
+
+var FieldTypes = []string{FieldText, FieldHidden, FieldURL, FieldEmail, FieldDate}
+
+var ErrInvalidField = errors.New("invalid custom field")
+
+type CustomField struct {
+    Name  string `json:"name"`
+    Type  string `json:"type"`
+    Value string `json:"value"`
+}
+
+// Notes, custom fields and generator rules of an entry (stored as one encrypted blob)
+type EntryDetails struct {
+    Notes     string                 `json:"notes"`
+    Fields    []CustomField          `json:"fields"`
+    Generator *utils.GeneratorPolicy `json:"generator,omitempty"` // rules to regenerate the password with
+}
+
+// Validate checks the name, the type and that the value matches the type
+func (f CustomField) Validate() error {
+    if f.Name == "" {
+        return fmt.Errorf("%w: name is empty", ErrInvalidField)
+    }
+    switch f.Type {
+    case FieldText, FieldHidden:
+    case FieldURL:
+        if u, err := url.Parse(f.Value); f.Value != "" && (err != nil || u.Scheme == "" || u.Host == "") {
+            return fmt.Errorf("%w: %q: invalid URL", ErrInvalidField, f.Name)
+        }
+    case FieldEmail:
+        if _, err := mail.ParseAddress(f.Value); f.Value != "" && err != nil {
+            return fmt.Errorf("%w: %q: invalid email", ErrInvalidField, f.Name)
+        }
+    case FieldDate:
+        if _, err := time.Parse("2006-01-02", f.Value); f.Value != "" && err != nil {
+            return fmt.Errorf("%w: %q: date must be YYYY-MM-DD", ErrInvalidField, f.Name)
+        }
+    default:
+        return fmt.Errorf("%w: %q: unknown type %q", ErrInvalidField, f.Name, f.Type)
+    }
+    return nil
+}
+
+// Masked returns a copy with hidden values blanked out (for API output)
+func (d EntryDetails) Masked() EntryDetails {
+    out := EntryDetails{Notes: d.Notes, Fields: make([]CustomField, len(d.Fields)), Generator: d.Generator}
+    for i, f := range d.Fields {
+        if f.Type == FieldHidden {
+            f.Value = ""
+        }
+        out.Fields[i] = f
+    }
+    return out
+}
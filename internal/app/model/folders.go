@@ -0,0 +1,20 @@
+package model
+
+// Folder of entries; folders nest, an entry is in at most one folder
+type Folder struct {
+    ID       int    `json:"id"`
+    ParentID int    `json:"parent_id,omitempty"` // 0 — top level
+    Name     string `json:"name"`
+    Path     string `json:"path"`  // names from the top level joined with "/"
+    Count    int    `json:"count"` // entries directly in this folder, trash excluded
+}
+
+// Tag: an entry can have any number of tags
+type Tag struct {
+    ID    int    `json:"id"`
+    Name  string `json:"name"`
+    Count int    `json:"count"` // entries with this tag, trash excluded
+}
+
+// FolderSeparator joins folder names into a path ("Work/Clients")
+const FolderSeparator = "/"
@@ -0,0 +1,37 @@
+package model
+
+import (
+    "errors"
+    "time"
+)
+
+// Auto-lock policy of a vault; applied by the desktop/mobile app
+type LockPolicy struct {
+    IdleMinutes       int  `json:"idle_minutes"`        // 0 — never lock on inactivity
+    OnFocusLoss       bool `json:"lock_on_focus_loss"`  // no app window has focus: minimized, in background or another window activated
+    OnSuspend         bool `json:"lock_on_suspend"`     // system sleep/hibernate detected
+    MaxSessionMinutes int  `json:"max_session_minutes"` // 0 — unlimited
+}
+
+// Prior behaviour: 2 minutes idle; locking on sleep is the safe default
+var DefaultLockPolicy = LockPolicy{IdleMinutes: 2, OnSuspend: true}
+
+const maxLockMinutes = 7 * 24 * 60
+
+var ErrInvalidLockPolicy = errors.New("lock policy minutes must be between 0 and 10080")
+
+func (p LockPolicy) Validate() error {
+    if p.IdleMinutes < 0 || p.IdleMinutes > maxLockMinutes ||
+        p.MaxSessionMinutes < 0 || p.MaxSessionMinutes > maxLockMinutes {
+        return ErrInvalidLockPolicy
+    }
+    return nil
+}
+
+func (p LockPolicy) IdleTimeout() time.Duration {
+    return time.Duration(p.IdleMinutes) * time.Minute
+}
+
+func (p LockPolicy) MaxSession() time.Duration {
+    return time.Duration(p.MaxSessionMinutes) * time.Minute
+}
@@ -1,5 +1,7 @@
 package model
 
+import "password-manager/pkg/utils"
+
 // Full password structure (used for creation/update)
 type Password struct {
     ID        int    `json:"id"`
@@ -7,17 +9,54 @@ type Password struct {
     Username  string `json:"username"`
     Link      string `json:"link"`
     Password  string `json:"password"`
-    Category  string `json:"category"`
+    Category  string `json:"category"` // folder path, missing folders are created; "" — no folder
     CreatedAt string `json:"created_at"`
+    // otpauth:// URI or bare base32 seed; nil on update keeps the stored one, "" removes it
+    TOTPSecret *string `json:"totp_secret,omitempty"`
+    // nil on update keeps the stored notes/fields; "" and [] clear them
+    Notes  *string       `json:"notes,omitempty"`
+    Fields []CustomField `json:"fields,omitempty"`
+    // nil on update keeps the stored rules; length 0 removes them
+    Generator *utils.GeneratorPolicy `json:"generator,omitempty"`
+    // nil on update keeps the stored tags; [] removes them. Unknown tags are created.
+    Tags []string `json:"tags,omitempty"`
 }
 
 // Structure without the Password field (used for public output)
 type PasswordListItem struct {
-    ID        int    `json:"id"`
-    Service   string `json:"service"`
-    Username  string `json:"username"`
-    Link      string `json:"link"`
-    Category  string `json:"category"`
-    CreatedAt string `json:"created_at"`
-    Password  string `json:"password"`
+    ID         int      `json:"id"`
+    Service    string   `json:"service"`
+    Username   string   `json:"username"`
+    Link       string   `json:"link"`
+    FolderID   int      `json:"folder_id,omitempty"`
+    Category   string   `json:"category"` // folder path
+    Tags       []string `json:"tags,omitempty"`
+    CreatedAt  string   `json:"created_at"`
+    UpdatedAt  string   `json:"updated_at"` // last password change
+    Password   string   `json:"password"`
+    HasTOTP    bool     `json:"has_totp"`
+    HasDetails bool     `json:"has_details"`
+    DeletedAt  string   `json:"deleted_at,omitempty"` // set while the entry is in the trash
+}
+
+// Filter for listing entries; empty fields match everything
+type PasswordFilter struct {
+    Service  string
+    Username string
+    // Folder paths and tag names ignore case. Category matches one folder,
+    // Folder also matches its subfolders.
+    Category string
+    Folder   string
+    Tag      string
+    // Fuzzy full-text query over service, username, link, notes and tags;
+    // results are ordered by relevance
+    Query          string
+    IncludeDeleted bool
+}
+
+// Previous password of an entry (the value itself is only copied or restored)
+type HistoryItem struct {
+    ID         int    `json:"id"`
+    PasswordID int    `json:"password_id"`
+    ChangedAt  string `json:"changed_at"`
 }
@@ -0,0 +1,227 @@
+package app
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+    "unicode"
+    "unicode/utf8"
+
+    "password-manager/pkg/utils"
+)
+
+// Реестр хранилищ: имя → путь к файлу SQLite.
+// Хранится в <UserConfigDir>/password-manager/vaults.json; у каждого хранилища
+// свой мастер-пароль, реестр сам по себе секретов не содержит.
+
+const (
+    DefaultVaultName = "default"
+    registryFileName = "vaults.json"
+    legacyDBPath     = "passwords.db" // раньше база всегда лежала в рабочем каталоге
+    maxVaultName     = 64
+)
+
+var (
+    ErrVaultNotFound = errors.New("vault not found")
+    ErrVaultExists   = errors.New("vault with this name already exists")
+    ErrVaultName     = fmt.Errorf("vault name must be 1-%d letters, digits, spaces, '-', '_' or '.'", maxVaultName)
+)
+
+type VaultEntry struct {
+    Name string `json:"name"`
+    Path string `json:"path"`
+}
+
+type Registry struct {
+    Default string       `json:"default"` // последнее открытое хранилище
+    Vaults  []VaultEntry `json:"vaults"`
+
+    file string
+}
+
+// RegistryDir: каталог настроек приложения
+func RegistryDir() (string, error) {
+    dir, err := os.UserConfigDir()
+    if err != nil {
+        return "", err
+    }
+    return filepath.Join(dir, "password-manager"), nil
+}
+
+// LoadRegistry читает реестр; отсутствующий файл — пустой реестр
+func LoadRegistry(file string) (*Registry, error) {
+    r := &Registry{file: file}
+    data, err := os.ReadFile(file)
+    if errors.Is(err, os.ErrNotExist) {
+        return r, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    if err := json.Unmarshal(data, r); err != nil {
+        return nil, fmt.Errorf("%s: %w", file, err)
+    }
+    return r, nil
+}
+
+// LoadDefaultRegistry: реестр пользователя. Пустой реестр получает хранилище
+// "default" — старый ./passwords.db, если он есть, иначе файл рядом с реестром.
+func LoadDefaultRegistry() (*Registry, error) {
+    dir, err := RegistryDir()
+    if err != nil {
+        return nil, err
+    }
+    r, err := LoadRegistry(filepath.Join(dir, registryFileName))
+    if err != nil {
+        return nil, err
+    }
+    if len(r.Vaults) > 0 {
+        return r, nil
+    }
+
+    path := filepath.Join(dir, legacyDBPath)
+    if _, err := os.Stat(legacyDBPath); err == nil {
+        if path, err = filepath.Abs(legacyDBPath); err != nil {
+            return nil, err
+        }
+    }
+    if err := r.Add(DefaultVaultName, path); err != nil {
+        return nil, err
+    }
+    return r, r.Save()
+}
+
+// Save пишет реестр атомарно (временный файл + rename), права 0600
+func (r *Registry) Save() error {
+    if r.file == "" {
+        return errors.New("registry has no file")
+    }
+    if err := os.MkdirAll(filepath.Dir(r.file), 0o700); err != nil {
+        return err
+    }
+    data, err := json.MarshalIndent(r, "", "  ")
+    if err != nil {
+        return err
+    }
+
+    tmp := r.file + ".tmp"
+    f, err := utils.CreatePrivateFile(tmp)
+    if err != nil {
+        return err
+    }
+    _, err = f.Write(data)
+    if cerr := f.Close(); err == nil {
+        err = cerr
+    }
+    if err != nil {
+        os.Remove(tmp)
+        return err
+    }
+    return os.Rename(tmp, r.file)
+}
+
+func (r *Registry) Names() []string {
+    names := make([]string, len(r.Vaults))
+    for i, v := range r.Vaults {
+        names[i] = v.Name
+    }
+    return names
+}
+
+func (r *Registry) Get(name string) (VaultEntry, error) {
+    for _, v := range r.Vaults {
+        if v.Name == name {
+            return v, nil
+        }
+    }
+    return VaultEntry{}, ErrVaultNotFound
+}
+
+// DefaultVault: последнее открытое хранилище, иначе первое
+func (r *Registry) DefaultVault() (VaultEntry, error) {
+    if v, err := r.Get(r.Default); err == nil {
+        return v, nil
+    }
+    if len(r.Vaults) == 0 {
+        return VaultEntry{}, ErrVaultNotFound
+    }
+    return r.Vaults[0], nil
+}
+
+// Add регистрирует хранилище; путь сохраняется абсолютным, один файл — одно имя.
+// Сам файл не создаётся — это делает InitDB при первом открытии.
+func (r *Registry) Add(name, path string) error {
+    name = strings.TrimSpace(name)
+    if err := ValidateVaultName(name); err != nil {
+        return err
+    }
+    if _, err := r.Get(name); err == nil {
+        return ErrVaultExists
+    }
+    if path == "" {
+        return errors.New("vault path is required")
+    }
+    abs, err := filepath.Abs(path)
+    if err != nil {
+        return err
+    }
+    for _, v := range r.Vaults {
+        if v.Path == abs {
+            return fmt.Errorf("%s is already registered as vault %q", abs, v.Name)
+        }
+    }
+    r.Vaults = append(r.Vaults, VaultEntry{Name: name, Path: abs})
+    if r.Default == "" {
+        r.Default = name
+    }
+    return nil
+}
+
+// Remove убирает хранилище из реестра, файл базы остаётся на диске
+func (r *Registry) Remove(name string) error {
+    for i, v := range r.Vaults {
+        if v.Name == name {
+            r.Vaults = append(r.Vaults[:i], r.Vaults[i+1:]...)
+            if r.Default == name {
+                r.Default = ""
+            }
+            return nil
+        }
+    }
+    return ErrVaultNotFound
+}
+
+func (r *Registry) SetDefault(name string) error {
+    if _, err := r.Get(name); err != nil {
+        return err
+    }
+    r.Default = name
+    return nil
+}
+
+// PathFor: путь по умолчанию для нового хранилища — рядом с реестром
+func (r *Registry) PathFor(name string) string {
+    file := strings.Map(func(c rune) rune {
+        if unicode.IsLetter(c) || unicode.IsDigit(c) || c == '-' || c == '_' {
+            return c
+        }
+        return '_'
+    }, strings.TrimSpace(name))
+    return filepath.Join(filepath.Dir(r.file), file+".db")
+}
+
+// ValidateVaultName: имя идёт в путь REST (/vaults/:vault), поэтому без '/', '%' и не "." / ".."
+func ValidateVaultName(name string) error {
+    if strings.Trim(name, ".") == "" || utf8.RuneCountInString(name) > maxVaultName || strings.TrimSpace(name) != name {
+        return ErrVaultName
+    }
+    for _, c := range name {
+        if !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != ' ' && c != '-' && c != '_' && c != '.' {
+            return ErrVaultName
+        }
+    }
+    return nil
+}
@@ -0,0 +1,189 @@
+// Package report — проверка здоровья хранилища: повторно используемые,
+// слабые, давно не менявшиеся и утёкшие (по локальной базе HIBP, см.
+// security.Breaches) пароли. Пароли расшифровываются только в памяти на
+// время проверки; одинаковые группируются по HMAC со случайным ключом,
+// который живёт до конца проверки, так что ни значения, ни их хэши в отчёт
+// не попадают.
+package report
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"password-manager/internal/app/db"
+	"password-manager/pkg/security"
+)
+
+const (
+	DefaultMaxAgeDays = 365
+	MaxAgeDaysLimit   = 100 * 365
+)
+
+var ErrInvalidOptions = errors.New("invalid report options")
+
+// Options: пороги отчёта. MinScore 0 и MaxAgeDays 0 отключают соответствующую проверку.
+type Options struct {
+	MinScore   int `json:"min_score"`    // слабый — оценка ниже (0–4, см. security.EstimateStrength)
+	MaxAgeDays int `json:"max_age_days"` // старый — пароль не менялся дольше
+}
+
+func DefaultOptions() Options {
+	return Options{MinScore: security.MinStrengthScore, MaxAgeDays: DefaultMaxAgeDays}
+}
+
+func (o Options) Validate() error {
+	if o.MinScore < 0 || o.MinScore > 4 {
+		return fmt.Errorf("%w: min_score must be between 0 and 4", ErrInvalidOptions)
+	}
+	if o.MaxAgeDays < 0 || o.MaxAgeDays > MaxAgeDaysLimit {
+		return fmt.Errorf("%w: max_age_days must be between 0 and %d", ErrInvalidOptions, MaxAgeDaysLimit)
+	}
+	return nil
+}
+
+// Entry: запись в отчёте (без пароля)
+type Entry struct {
+	ID        int           `json:"id"`
+	Service   string        `json:"service"`
+	Username  string        `json:"username"`
+	Category  string        `json:"category"`
+	Score     int           `json:"score"`
+	Warning   security.Hint `json:"warning,omitempty"`
+	Breached  int           `json:"breached,omitempty"` // сколько раз встречался в утечках
+	UpdatedAt string        `json:"updated_at"`
+	AgeDays   int           `json:"age_days"`
+}
+
+// Report: в корзине записи не проверяются, пустые пароли — тоже (только для
+// возраста). Failed — записи, которые не удалось расшифровать.
+type Report struct {
+	GeneratedAt string    `json:"generated_at"`
+	Options     Options   `json:"options"`
+	Total       int       `json:"total"`
+	Reused      [][]Entry `json:"reused"`       // группы записей с одинаковым паролем, крупные сверху
+	Weak        []Entry   `json:"weak"`         // самые слабые сверху
+	Old         []Entry   `json:"old"`          // самые старые сверху
+	BreachCheck bool      `json:"breach_check"` // false — локальная база утечек не подключена
+	Breached    []Entry   `json:"breached"`     // самые частые в утечках сверху
+	Failed      []int     `json:"failed,omitempty"`
+}
+
+// Issues: сколько записей попало хотя бы в один список
+func (r *Report) Issues() int {
+	ids := map[int]bool{}
+	for _, g := range r.Reused {
+		for _, e := range g {
+			ids[e.ID] = true
+		}
+	}
+	for _, list := range [][]Entry{r.Weak, r.Old, r.Breached} {
+		for _, e := range list {
+			ids[e.ID] = true
+		}
+	}
+	return len(ids) + len(r.Failed)
+}
+
+// Generate проверяет все записи хранилища (оно должно быть разблокировано)
+func Generate(store db.Storage, opts Options) (*Report, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	list, err := store.GetAllPasswords()
+	if err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	r := &Report{
+		GeneratedAt: now.Format(time.RFC3339),
+		Options:     opts,
+		Total:       len(list),
+		Reused:      [][]Entry{},
+		Weak:        []Entry{},
+		Old:         []Entry{},
+		BreachCheck: security.Breaches.Enabled(),
+		Breached:    []Entry{},
+	}
+
+	groups := map[[sha256.Size]byte][]Entry{}
+	for _, item := range list {
+		e := Entry{
+			ID:        item.ID,
+			Service:   item.Service,
+			Username:  item.Username,
+			Category:  item.Category,
+			UpdatedAt: item.UpdatedAt,
+			AgeDays:   ageDays(now, item.UpdatedAt, item.CreatedAt),
+		}
+		plain, err := store.DecryptPassword(item.ID)
+		if err != nil {
+			r.Failed = append(r.Failed, item.ID)
+			continue
+		}
+
+		if plain != "" {
+			st := security.EstimateStrength(plain, item.Service, item.Username, item.Link)
+			e.Score, e.Warning = st.Score, st.MainHint()
+			if r.BreachCheck {
+				if e.Breached, err = security.Breaches.Count(plain); err != nil {
+					return nil, fmt.Errorf("breach check: %w", err)
+				}
+			}
+
+			mac := hmac.New(sha256.New, key)
+			mac.Write([]byte(plain))
+			var sum [sha256.Size]byte
+			copy(sum[:], mac.Sum(nil))
+			groups[sum] = append(groups[sum], e)
+
+			if st.Score < opts.MinScore {
+				r.Weak = append(r.Weak, e)
+			}
+			if e.Breached > 0 {
+				r.Breached = append(r.Breached, e)
+			}
+		}
+		if opts.MaxAgeDays > 0 && e.AgeDays > opts.MaxAgeDays {
+			r.Old = append(r.Old, e)
+		}
+	}
+
+	for _, g := range groups {
+		if len(g) > 1 {
+			r.Reused = append(r.Reused, g)
+		}
+	}
+	sort.Slice(r.Reused, func(i, j int) bool {
+		if len(r.Reused[i]) != len(r.Reused[j]) {
+			return len(r.Reused[i]) > len(r.Reused[j])
+		}
+		return r.Reused[i][0].ID < r.Reused[j][0].ID
+	})
+	sort.SliceStable(r.Weak, func(i, j int) bool { return r.Weak[i].Score < r.Weak[j].Score })
+	sort.SliceStable(r.Old, func(i, j int) bool { return r.Old[i].AgeDays > r.Old[j].AgeDays })
+	sort.SliceStable(r.Breached, func(i, j int) bool { return r.Breached[i].Breached > r.Breached[j].Breached })
+	return r, nil
+}
+
+// ageDays: полных дней с последней смены пароля (если её дата неизвестна — с создания)
+func ageDays(now time.Time, updatedAt, createdAt string) int {
+	for _, s := range []string{updatedAt, createdAt} {
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			if d := int(now.Sub(t).Hours() / 24); d > 0 {
+				return d
+			}
+			return 0
+		}
+	}
+	return 0
+}
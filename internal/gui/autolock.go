@@ -0,0 +1,203 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+
+	"password-manager/internal/app"
+	"password-manager/internal/app/model"
+	"password-manager/internal/i18n"
+)
+
+// Автоблокировка в GUI: политика хранилища применяется app.AutoLock,
+// здесь — активность во всех окнах, уход приложения в фон и смена окон.
+
+// autoLock: наблюдение за текущим разблокированным хранилищем; nil — заблокировано
+var autoLock *app.AutoLock
+
+// Как часто проверять, не печатает ли пользователь в поле ввода
+const typingPollInterval = 2 * time.Second
+
+// startAutoLock вызывается при показе главного окна. Блокировка затирает ключ,
+// закрывает все окна и показывает разблокировку того же хранилища (без переоткрытия БД).
+func startAutoLock(a fyne.App, appInstance *app.App) {
+	stopAutoLock()
+	var l *app.AutoLock
+	l = appInstance.StartAutoLock(func(reason app.LockReason) {
+		fyne.Do(func() {
+			if autoLock != nil && autoLock != l {
+				return
+			}
+			autoLock = nil
+			replaceWindows(a, func() { showUnlockWindow(a, appInstance) })
+		})
+	})
+	autoLock = l
+
+	// Fyne не сообщает о сворачивании окна, только о потере фокуса: на десктопе
+	// «в фоне» — ни одно окно приложения не в фокусе, будь оно свёрнуто или
+	// просто перекрыто другим окном. Поэтому опция — «при потере фокуса».
+	a.Lifecycle().SetOnExitedForeground(func() {
+		if l.Policy().OnFocusLoss {
+			l.LockNow(app.LockFocusLoss)
+		}
+	})
+	go watchTyping(a, l)
+}
+
+// stopAutoLock: хранилище закрывается иначе (смена хранилища)
+func stopAutoLock() {
+	if autoLock != nil {
+		autoLock.Stop()
+		autoLock = nil
+	}
+}
+
+func touchActivity() {
+	if autoLock != nil {
+		autoLock.Touch()
+	}
+}
+
+// replaceWindows показывает следующее окно и закрывает все прежние, включая
+// дочерние. OnClosed снимается, чтобы главное окно не завершило приложение.
+func replaceWindows(a fyne.App, show func()) {
+	windows := a.Driver().AllWindows()
+	show()
+	for _, w := range windows {
+		w.SetOnClosed(nil)
+		w.Close()
+	}
+}
+
+// trackActivity: мышь и клавиатура в окне сбрасывают таймер бездействия.
+// Вызывать после SetContent.
+func trackActivity(w fyne.Window) {
+	w.SetContent(newActivityLayer(w.Content()))
+	w.Canvas().SetOnTypedKey(func(*fyne.KeyEvent) { touchActivity() })
+	w.Canvas().SetOnTypedRune(func(rune) { touchActivity() })
+}
+
+// activityLayer оборачивает содержимое окна. Общего хука на мышь в Fyne нет,
+// но десктопный драйвер при каждом движении указателя спрашивает Cursor()
+// у всех объектов под ним, начиная с корня, — этим и пользуемся.
+type activityLayer struct {
+	widget.BaseWidget
+	content fyne.CanvasObject
+}
+
+func newActivityLayer(content fyne.CanvasObject) *activityLayer {
+	l := &activityLayer{content: content}
+	l.ExtendBaseWidget(l)
+	return l
+}
+
+func (l *activityLayer) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(l.content)
+}
+
+func (l *activityLayer) Cursor() desktop.Cursor {
+	touchActivity()
+	return desktop.DefaultCursor
+}
+
+// watchTyping: ввод в поле с фокусом не доходит до обработчиков канвы,
+// поэтому состояние такого поля сверяется по таймеру
+func watchTyping(a fyne.App, l *app.AutoLock) {
+	ticker := time.NewTicker(typingPollInterval)
+	defer ticker.Stop()
+
+	last := ""
+	for range ticker.C {
+		active := true
+		fyne.DoAndWait(func() {
+			if autoLock != l {
+				active = false
+				return
+			}
+			state := ""
+			for _, w := range a.Driver().AllWindows() {
+				var e *widget.Entry
+				switch f := w.Canvas().Focused().(type) {
+				case *widget.Entry:
+					e = f
+				case *widget.SelectEntry:
+					e = &f.Entry
+				default:
+					continue
+				}
+				state = fmt.Sprintf("%p:%d:%d:%d", e, len(e.Text), e.CursorRow, e.CursorColumn)
+			}
+			if state != "" && state != last {
+				l.Touch()
+			}
+			last = state
+		})
+		if !active {
+			return
+		}
+	}
+}
+
+func lockNow() {
+	if autoLock != nil {
+		autoLock.LockNow(app.LockManual)
+	}
+}
+
+// showLockPolicyDialog: политика автоблокировки текущего хранилища; применяется сразу
+func showLockPolicyDialog(w fyne.Window) {
+	l := autoLock
+	if l == nil {
+		return
+	}
+	p := l.Policy()
+
+	idleEntry := widget.NewEntry()
+	idleEntry.SetText(strconv.Itoa(p.IdleMinutes))
+	maxEntry := widget.NewEntry()
+	maxEntry.SetText(strconv.Itoa(p.MaxSessionMinutes))
+	focusCheck := widget.NewCheck(i18n.T("Lock_on_focus_loss"), nil)
+	focusCheck.SetChecked(p.OnFocusLoss)
+	suspendCheck := widget.NewCheck(i18n.T("Lock_on_suspend"), nil)
+	suspendCheck.SetChecked(p.OnSuspend)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(i18n.T("Lock_idle_minutes"), idleEntry),
+		widget.NewFormItem(i18n.T("Lock_max_session_minutes"), maxEntry),
+		widget.NewFormItem("", widget.NewLabel(i18n.T("Lock_minutes_hint"))),
+		widget.NewFormItem("", focusCheck),
+		widget.NewFormItem("", widget.NewLabel(i18n.T("Lock_on_focus_loss_hint"))),
+		widget.NewFormItem("", suspendCheck),
+	}
+	form := dialog.NewForm(i18n.T("Auto_lock"), i18n.T("Save"), i18n.T("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		idle, err1 := strconv.Atoi(strings.TrimSpace(idleEntry.Text))
+		maxSession, err2 := strconv.Atoi(strings.TrimSpace(maxEntry.Text))
+		policy := model.LockPolicy{
+			IdleMinutes:       idle,
+			OnFocusLoss:       focusCheck.Checked,
+			OnSuspend:         suspendCheck.Checked,
+			MaxSessionMinutes: maxSession,
+		}
+		if err1 != nil || err2 != nil || policy.Validate() != nil {
+			dialog.ShowError(errors.New(i18n.T("Lock_minutes_invalid")), w)
+			return
+		}
+		if err := l.SetPolicy(policy); err != nil {
+			dialog.ShowError(err, w)
+		}
+	}, w)
+	form.Resize(fyne.NewSize(460, form.MinSize().Height))
+	form.Show()
+}
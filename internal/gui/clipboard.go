@@ -0,0 +1,41 @@
+package gui
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"password-manager/internal/app"
+	"password-manager/internal/i18n"
+	"password-manager/pkg/utils"
+)
+
+// showClipboardDialog: через сколько секунд стирать скопированные секреты
+// (настройка хранилища, применяется сразу)
+func showClipboardDialog(w fyne.Window, appInstance *app.App) {
+	secondsEntry := widget.NewEntry()
+	secondsEntry.SetText(strconv.Itoa(int(appInstance.DB.ClipboardTimeout().Seconds())))
+
+	items := []*widget.FormItem{widget.NewFormItem(i18n.T("Clipboard_clear_seconds"), secondsEntry)}
+	form := dialog.NewForm(i18n.T("Clipboard_settings"), i18n.T("Save"), i18n.T("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		seconds, err := strconv.Atoi(strings.TrimSpace(secondsEntry.Text))
+		d := time.Duration(seconds) * time.Second
+		if err != nil || d < utils.MinClipboardTimeout || d > utils.MaxClipboardTimeout {
+			dialog.ShowError(errors.New(i18n.T("Clipboard_seconds_invalid")), w)
+			return
+		}
+		if err := appInstance.DB.SetClipboardTimeout(d); err != nil {
+			dialog.ShowError(err, w)
+		}
+	}, w)
+	form.Resize(fyne.NewSize(420, form.MinSize().Height))
+	form.Show()
+}
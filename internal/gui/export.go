@@ -0,0 +1,210 @@
+package gui
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"password-manager/internal/app"
+	"password-manager/internal/app/db"
+	"password-manager/internal/i18n"
+	"password-manager/pkg/security"
+	"password-manager/pkg/utils"
+)
+
+// buildVaultMenu: меню «Хранилище» с экспортом и импортом (в том числе из
+// других менеджеров), блокировкой и сменой хранилища.
+// onImport вызывается после успешного импорта, чтобы обновить списки;
+// onSwitch == nil — пункта «Сменить хранилище» нет.
+func buildVaultMenu(w fyne.Window, appInstance *app.App, onImport, onSwitch func()) *fyne.MainMenu {
+	items := []*fyne.MenuItem{
+		fyne.NewMenuItem(i18n.T("Export_vault"), func() { showExportDialog(w, appInstance) }),
+		fyne.NewMenuItem(i18n.T("Import_vault"), func() { showImportDialog(w, appInstance, onImport) }),
+		fyne.NewMenuItem(i18n.T("Export_plain"), func() { showPlainExportDialog(w, appInstance) }),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(i18n.T("Import_other"), func() {
+			ShowExternalImportWindow(fyne.CurrentApp(), appInstance, onImport)
+		}),
+	}
+	items = append(items,
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(i18n.T("Auto_lock"), func() { showLockPolicyDialog(w) }),
+		fyne.NewMenuItem(i18n.T("Clipboard_settings"), func() { showClipboardDialog(w, appInstance) }),
+		fyne.NewMenuItem(i18n.T("Lock_now"), lockNow),
+	)
+	if onSwitch != nil {
+		items = append(items, fyne.NewMenuItem(i18n.T("Switch_vault"), onSwitch))
+	}
+	return fyne.NewMainMenu(fyne.NewMenu(i18n.T("Vault_menu"), items...))
+}
+
+// Сначала фраза-пароль (дважды), потом куда сохранить
+func showExportDialog(w fyne.Window, appInstance *app.App) {
+	passEntry := widget.NewPasswordEntry()
+	confirmEntry := widget.NewPasswordEntry()
+	hint := widget.NewLabel(i18n.T("Export_passphrase_hint"))
+	hint.Wrapping = fyne.TextWrapWord
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("", hint),
+		widget.NewFormItem(i18n.T("Export_passphrase"), passEntry),
+		widget.NewFormItem(i18n.T("Confirm_passphrase"), confirmEntry),
+	}
+	form := dialog.NewForm(i18n.T("Export_vault"), i18n.T("Confirm"), i18n.T("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		if passEntry.Text == "" {
+			dialog.ShowError(errors.New(i18n.T("Passphrase_required")), w)
+			return
+		}
+		if passEntry.Text != confirmEntry.Text {
+			dialog.ShowError(errors.New(i18n.T("Passphrases_mismatch")), w)
+			return
+		}
+		passphrase := passEntry.Text
+
+		save := dialog.NewFileSave(func(out fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if out == nil {
+				return
+			}
+			err = appInstance.DB.Export(out, passphrase)
+			if cerr := out.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			dialog.ShowInformation(i18n.T("Export_vault"), i18n.T("Vault_exported"), w)
+		}, w)
+		save.SetFileName("vault-" + time.Now().Format("2006-01-02") + ".pmexport")
+		save.Show()
+	}, w)
+	form.Resize(fyne.NewSize(420, form.MinSize().Height))
+	form.Show()
+}
+
+// Сначала файл, потом фраза-пароль; записи добавляются к текущим
+func showImportDialog(w fyne.Window, appInstance *app.App, onImport func()) {
+	dialog.ShowFileOpen(func(in fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if in == nil {
+			return
+		}
+
+		passEntry := widget.NewPasswordEntry()
+		items := []*widget.FormItem{widget.NewFormItem(i18n.T("Export_passphrase"), passEntry)}
+		form := dialog.NewForm(i18n.T("Import_vault"), i18n.T("Confirm"), i18n.T("Cancel"), items, func(ok bool) {
+			defer in.Close()
+			if !ok {
+				return
+			}
+			n, err := appInstance.DB.Import(in, passEntry.Text)
+			if errors.Is(err, db.ErrBadPassphrase) {
+				dialog.ShowError(errors.New(i18n.T("Wrong_passphrase")), w)
+				return
+			}
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			dialog.ShowInformation(i18n.T("Import_vault"), i18n.T("Entries_imported")+" "+strconv.Itoa(n), w)
+			if onImport != nil {
+				onImport()
+			}
+		}, w)
+		form.Resize(fyne.NewSize(380, form.MinSize().Height))
+		form.Show()
+	}, w)
+}
+
+// Экспорт без шифрования: предупреждение, галочка «понимаю», мастер-пароль ещё раз.
+// Локальный файл создаётся с правами 0600.
+func showPlainExportDialog(w fyne.Window, appInstance *app.App) {
+	warning := widget.NewLabel("⚠ " + i18n.T("Export_plain_warning"))
+	warning.Wrapping = fyne.TextWrapWord
+	warning.Importance = widget.DangerImportance
+	formatSelect := widget.NewSelect([]string{db.PlainCSV, db.PlainJSON}, nil)
+	formatSelect.SetSelected(db.PlainCSV)
+	masterEntry := widget.NewPasswordEntry()
+	confirmCheck := widget.NewCheck(i18n.T("Export_plain_confirm"), nil)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("", warning),
+		widget.NewFormItem(i18n.T("Import_format"), formatSelect),
+		widget.NewFormItem(i18n.T("Master_Password"), masterEntry),
+		widget.NewFormItem("", confirmCheck),
+	}
+	form := dialog.NewForm(i18n.T("Export_plain"), i18n.T("Confirm"), i18n.T("Cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		if !confirmCheck.Checked {
+			dialog.ShowError(errors.New(i18n.T("Confirm_risk_required")), w)
+			return
+		}
+		err := appInstance.VerifyMasterPassword(masterEntry.Text)
+		if errors.Is(err, security.ErrInvalidMasterPassword) {
+			dialog.ShowError(errors.New(i18n.T("Invalid_master_password")), w)
+			return
+		}
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		format := formatSelect.Selected
+
+		save := dialog.NewFileSave(func(out fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if out == nil {
+				return
+			}
+			if err := writePlainExport(out, appInstance, format); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			dialog.ShowInformation(i18n.T("Export_plain"), i18n.T("Vault_exported"), w)
+		}, w)
+		save.SetFileName("vault-" + time.Now().Format("2006-01-02") + "-UNENCRYPTED." + format)
+		save.Show()
+	}, w)
+	form.Resize(fyne.NewSize(480, form.MinSize().Height))
+	form.Show()
+}
+
+// writePlainExport: обычный файл пересоздаётся с 0600 (диалог мог создать его
+// с правами по умолчанию); прочие URI (мобильные хранилища) — как есть
+func writePlainExport(out fyne.URIWriteCloser, appInstance *app.App, format string) error {
+	var dst io.WriteCloser = out
+	if out.URI().Scheme() == "file" {
+		if err := out.Close(); err != nil {
+			return err
+		}
+		f, err := utils.CreatePrivateFile(out.URI().Path())
+		if err != nil {
+			return err
+		}
+		dst = f
+	}
+	err := appInstance.DB.ExportPlain(dst, format)
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
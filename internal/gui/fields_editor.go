@@ -0,0 +1,173 @@
+package gui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"password-manager/internal/app/db"
+	"password-manager/internal/app/model"
+	"password-manager/internal/i18n"
+	"password-manager/pkg/utils"
+)
+
+// fieldsEditor — список пользовательских полей записи в формах создания/изменения
+type fieldsEditor struct {
+	list *fyne.Container
+	rows []*fieldRow
+}
+
+type fieldRow struct {
+	name    *widget.Entry
+	kind    *widget.Select
+	value   *widget.Entry
+	content fyne.CanvasObject
+}
+
+func newFieldsEditor() *fieldsEditor {
+	return &fieldsEditor{list: container.NewVBox()}
+}
+
+func (e *fieldsEditor) addRow(f model.CustomField) {
+	row := &fieldRow{name: widget.NewEntry(), value: widget.NewEntry()}
+	row.name.SetPlaceHolder(i18n.T("Field_name"))
+	row.name.SetText(f.Name)
+	row.value.SetText(f.Value)
+
+	row.kind = widget.NewSelect(model.FieldTypes, func(kind string) {
+		// скрытые значения вводятся как пароль
+		row.value.Password = kind == model.FieldHidden
+		row.value.Refresh()
+	})
+	if f.Type == "" {
+		f.Type = model.FieldText
+	}
+	row.kind.SetSelected(f.Type)
+
+	removeBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		e.removeRow(row)
+	})
+	row.content = container.NewBorder(nil, nil,
+		container.NewGridWrap(fyne.NewSize(120, row.kind.MinSize().Height), row.kind),
+		removeBtn,
+		container.NewGridWithColumns(2, row.name, row.value),
+	)
+
+	e.rows = append(e.rows, row)
+	e.list.Add(row.content)
+}
+
+func (e *fieldsEditor) removeRow(row *fieldRow) {
+	for i, r := range e.rows {
+		if r == row {
+			e.rows = append(e.rows[:i], e.rows[i+1:]...)
+			break
+		}
+	}
+	e.list.Remove(row.content)
+}
+
+// SetFields заменяет все строки редактора
+func (e *fieldsEditor) SetFields(fields []model.CustomField) {
+	e.rows = nil
+	e.list.RemoveAll()
+	for _, f := range fields {
+		e.addRow(f)
+	}
+}
+
+// Fields: поля без имени пропускаются
+func (e *fieldsEditor) Fields() []model.CustomField {
+	fields := []model.CustomField{}
+	for _, r := range e.rows {
+		if r.name.Text == "" {
+			continue
+		}
+		fields = append(fields, model.CustomField{Name: r.name.Text, Type: r.kind.Selected, Value: r.value.Text})
+	}
+	return fields
+}
+
+func (e *fieldsEditor) Content() fyne.CanvasObject {
+	addBtn := widget.NewButtonWithIcon(i18n.T("Add_field"), theme.ContentAddIcon(), func() {
+		e.addRow(model.CustomField{})
+	})
+	return container.NewVBox(e.list, addBtn)
+}
+
+// copySecret кладёт значение в буфер обмена — общий путь для паролей и скрытых
+// полей; очистку по таймауту хранилища storage делает utils.Clipboard
+func copySecret(storage db.Storage, statusLabel *widget.Label, secret, statusKey string) {
+	if err := utils.Clipboard.CopySecret(secret, storage.ClipboardTimeout()); err != nil {
+		statusLabel.SetText(err.Error())
+		return
+	}
+	statusLabel.SetText(i18n.T(statusKey))
+	clearStatusLater(statusLabel)
+}
+
+// ShowDetailsWindow: заметки и пользовательские поля записи.
+// Скрытые значения не показываются, только копируются.
+func ShowDetailsWindow(storage db.Storage, entry model.PasswordListItem) {
+	a := fyne.CurrentApp()
+	factory := CurrentFactory()
+	a.Settings().SetTheme(factory.Theme())
+
+	w := a.NewWindow(i18n.T("Details") + ": " + entry.Service)
+	w.Resize(factory.SmallWindowSize())
+	w.CenterOnScreen()
+
+	details, err := storage.GetDetails(entry.ID)
+	if err != nil {
+		dialog.ShowError(err, w)
+		w.Show()
+		return
+	}
+
+	statusLabel := widget.NewLabel("")
+
+	notes := widget.NewLabel(details.Notes)
+	notes.Wrapping = fyne.TextWrapWord
+	if details.Notes == "" {
+		notes.SetText(i18n.T("No_notes"))
+	}
+
+	fields := container.NewVBox()
+	for _, f := range details.Fields {
+		value := f.Value
+		if f.Type == model.FieldHidden {
+			value = "••••••••"
+		}
+		valueLabel := widget.NewLabel(value)
+		valueLabel.Truncation = fyne.TextTruncateEllipsis
+
+		statusKey := "Value_copied"
+		if f.Type == model.FieldHidden {
+			statusKey = "Hidden_value_copied"
+		}
+		copyBtn := widget.NewButtonWithIcon("", theme.ContentCopyIcon(), func() {
+			copySecret(storage, statusLabel, f.Value, statusKey)
+		})
+		fields.Add(container.NewBorder(nil, nil,
+			widget.NewLabelWithStyle(f.Name, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			copyBtn,
+			valueLabel,
+		))
+	}
+
+	content := container.NewVBox(
+		widget.NewLabelWithStyle("📝 "+i18n.T("Notes"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), notes,
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle("🧩 "+i18n.T("Custom_fields"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), fields,
+		widget.NewSeparator(),
+		statusLabel,
+	)
+
+	scroll := container.NewVScroll(content)
+	scroll.SetMinSize(factory.SmallWindowSize())
+	w.SetContent(container.NewPadded(scroll))
+	trackActivity(w)
+	w.Show()
+}
@@ -0,0 +1,464 @@
+package gui
+
+import (
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"password-manager/internal/app"
+	"password-manager/internal/app/model"
+	"password-manager/internal/i18n"
+)
+
+// UID узлов дерева: "all" — все записи, "f:ID" — папка
+const allFoldersUID = "all"
+
+func folderUID(id int) string { return "f:" + strconv.Itoa(id) }
+
+func folderIDOf(uid string) int {
+	id, _ := strconv.Atoi(strings.TrimPrefix(uid, "f:"))
+	return id
+}
+
+// folderSidebar: дерево папок и выбор тега в боковой панели; выбор меняет
+// фильтр основного списка (папка — вместе с подпапками)
+type folderSidebar struct {
+	w           fyne.Window
+	appInstance *app.App
+	onFilter    func(model.PasswordFilter)
+
+	folders  map[int]model.Folder
+	children map[int][]int // 0 — верхний уровень
+	tags     []model.Tag
+	selected int // 0 — все записи
+	tag      string
+
+	title     *widget.Label
+	tree      *widget.Tree
+	tagSelect *widget.Select
+	buttons   []*widget.Button
+	content   fyne.CanvasObject
+}
+
+func newFolderSidebar(w fyne.Window, appInstance *app.App, onFilter func(model.PasswordFilter)) *folderSidebar {
+	s := &folderSidebar{w: w, appInstance: appInstance, onFilter: onFilter}
+
+	s.tree = widget.NewTree(
+		func(uid widget.TreeNodeID) []widget.TreeNodeID {
+			if uid == "" {
+				ids := []widget.TreeNodeID{allFoldersUID}
+				for _, id := range s.children[0] {
+					ids = append(ids, folderUID(id))
+				}
+				return ids
+			}
+			var ids []widget.TreeNodeID
+			if uid != allFoldersUID {
+				for _, id := range s.children[folderIDOf(uid)] {
+					ids = append(ids, folderUID(id))
+				}
+			}
+			return ids
+		},
+		func(uid widget.TreeNodeID) bool {
+			return uid == "" || (uid != allFoldersUID && len(s.children[folderIDOf(uid)]) > 0)
+		},
+		func(bool) fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(uid widget.TreeNodeID, _ bool, o fyne.CanvasObject) {
+			label := o.(*widget.Label)
+			if uid == allFoldersUID {
+				label.SetText("🗂 " + i18n.T("All_entries"))
+				return
+			}
+			f := s.folders[folderIDOf(uid)]
+			label.SetText("📂 " + f.Name + " (" + strconv.Itoa(f.Count) + ")")
+		},
+	)
+	s.tree.OnSelected = func(uid widget.TreeNodeID) {
+		s.selected = 0
+		if uid != allFoldersUID {
+			s.selected = folderIDOf(uid)
+		}
+		s.apply()
+	}
+
+	s.tagSelect = widget.NewSelect(nil, func(name string) {
+		if name == i18n.T("Any_tag") {
+			name = ""
+		}
+		s.tag = name
+		s.apply()
+	})
+
+	s.buttons = []*widget.Button{
+		widget.NewButtonWithIcon(i18n.T("New_folder"), theme.FolderNewIcon(), s.showCreate),
+		widget.NewButtonWithIcon(i18n.T("Rename"), theme.DocumentCreateIcon(), s.showRename),
+		widget.NewButtonWithIcon(i18n.T("Merge"), theme.ContentPasteIcon(), s.showMerge),
+		widget.NewButtonWithIcon(i18n.T("Delete"), theme.DeleteIcon(), s.showDelete),
+		widget.NewButtonWithIcon(i18n.T("Manage_tags"), theme.ListIcon(), func() { showTagManager(s.w, s.appInstance, s.changed) }),
+	}
+
+	s.title = widget.NewLabelWithStyle(i18n.T("Folders"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	s.content = container.NewBorder(
+		s.title,
+		container.NewVBox(
+			s.tagSelect,
+			container.NewGridWithColumns(2, s.buttons[0], s.buttons[1], s.buttons[2], s.buttons[3]),
+			s.buttons[4],
+		),
+		nil, nil,
+		s.tree,
+	)
+	s.Reload()
+	s.tree.Select(allFoldersUID)
+	return s
+}
+
+func (s *folderSidebar) Content() fyne.CanvasObject { return s.content }
+
+// Reload перечитывает папки и теги (после изменения записей — счётчики)
+func (s *folderSidebar) Reload() {
+	folders, err := s.appInstance.DB.GetFolders()
+	if err != nil {
+		dialog.ShowError(err, s.w)
+		return
+	}
+	tags, err := s.appInstance.DB.GetTags()
+	if err != nil {
+		dialog.ShowError(err, s.w)
+		return
+	}
+
+	s.folders = map[int]model.Folder{}
+	s.children = map[int][]int{}
+	for _, f := range folders { // уже по пути, поэтому и дети по алфавиту
+		s.folders[f.ID] = f
+		s.children[f.ParentID] = append(s.children[f.ParentID], f.ID)
+	}
+	s.tags = tags
+
+	options := []string{i18n.T("Any_tag")}
+	found := false
+	for _, t := range tags {
+		options = append(options, t.Name)
+		found = found || strings.EqualFold(t.Name, s.tag)
+	}
+	s.tagSelect.Options = options
+	if !found {
+		s.tag = ""
+	}
+	if s.tag == "" {
+		s.tagSelect.Selected = i18n.T("Any_tag")
+	} else {
+		s.tagSelect.Selected = s.tag
+	}
+	s.tagSelect.Refresh()
+
+	if _, ok := s.folders[s.selected]; !ok && s.selected != 0 {
+		s.selected = 0
+		s.tree.Select(allFoldersUID)
+	}
+	s.tree.Refresh()
+}
+
+// RefreshTexts: подписи после смены языка
+func (s *folderSidebar) RefreshTexts() {
+	s.title.SetText(i18n.T("Folders"))
+	for i, key := range []string{"New_folder", "Rename", "Merge", "Delete", "Manage_tags"} {
+		s.buttons[i].SetText(i18n.T(key))
+	}
+	s.Reload()
+}
+
+// Filter: текущий фильтр списка
+func (s *folderSidebar) Filter() model.PasswordFilter {
+	f := model.PasswordFilter{Tag: s.tag}
+	if folder, ok := s.folders[s.selected]; ok {
+		f.Folder = folder.Path
+	}
+	return f
+}
+
+func (s *folderSidebar) apply() {
+	if s.onFilter != nil {
+		s.onFilter(s.Filter())
+	}
+}
+
+// changed: папки или теги изменились — перечитать дерево и список
+func (s *folderSidebar) changed() {
+	s.Reload()
+	s.apply()
+}
+
+// selectedFolder: для операций, требующих выбранной папки
+func (s *folderSidebar) selectedFolder() (model.Folder, bool) {
+	f, ok := s.folders[s.selected]
+	if !ok {
+		dialog.ShowInformation(i18n.T("Info"), i18n.T("Select_folder"), s.w)
+	}
+	return f, ok
+}
+
+// showCreate: новая папка внутри выбранной (или на верхнем уровне)
+func (s *folderSidebar) showCreate() {
+	name := widget.NewEntry()
+	parent := s.selected
+	hint := i18n.T("Top_level")
+	if f, ok := s.folders[parent]; ok {
+		hint = f.Path
+	}
+	dialog.ShowForm(i18n.T("New_folder"), i18n.T("Save"), i18n.T("Cancel"), []*widget.FormItem{
+		widget.NewFormItem(i18n.T("Folder"), widget.NewLabel(hint)),
+		widget.NewFormItem(i18n.T("Folder_name"), name),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		if _, err := s.appInstance.DB.CreateFolder(parent, name.Text); err != nil {
+			dialog.ShowError(err, s.w)
+			return
+		}
+		s.changed()
+	}, s.w)
+}
+
+func (s *folderSidebar) showRename() {
+	f, ok := s.selectedFolder()
+	if !ok {
+		return
+	}
+	name := widget.NewEntry()
+	name.SetText(f.Name)
+	dialog.ShowForm(i18n.T("Rename"), i18n.T("Save"), i18n.T("Cancel"), []*widget.FormItem{
+		widget.NewFormItem(i18n.T("Folder_name"), name),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		if err := s.appInstance.DB.RenameFolder(f.ID, name.Text); err != nil {
+			dialog.ShowError(err, s.w)
+			return
+		}
+		s.changed()
+	}, s.w)
+}
+
+// showMerge: записи и подпапки выбранной папки переходят в другую
+func (s *folderSidebar) showMerge() {
+	f, ok := s.selectedFolder()
+	if !ok {
+		return
+	}
+	targets := map[string]int{}
+	var paths []string
+	for _, id := range s.sortedIDs() {
+		other := s.folders[id]
+		if other.ID != f.ID && !strings.HasPrefix(other.Path+"/", f.Path+"/") {
+			targets[other.Path] = other.ID
+			paths = append(paths, other.Path)
+		}
+	}
+	into := widget.NewSelect(paths, nil)
+	dialog.ShowForm(i18n.T("Merge"), i18n.T("Merge"), i18n.T("Cancel"), []*widget.FormItem{
+		widget.NewFormItem(i18n.T("Folder"), widget.NewLabel(f.Path)),
+		widget.NewFormItem(i18n.T("Merge_into"), into),
+	}, func(ok bool) {
+		if !ok || into.Selected == "" {
+			return
+		}
+		if err := s.appInstance.DB.MergeFolders(f.ID, targets[into.Selected]); err != nil {
+			dialog.ShowError(err, s.w)
+			return
+		}
+		s.changed()
+	}, s.w)
+}
+
+func (s *folderSidebar) showDelete() {
+	f, ok := s.selectedFolder()
+	if !ok {
+		return
+	}
+	dialog.ShowConfirm(i18n.T("Delete"), i18n.T("Delete_folder_confirm")+"\n"+f.Path, func(ok bool) {
+		if !ok {
+			return
+		}
+		if err := s.appInstance.DB.DeleteFolder(f.ID); err != nil {
+			dialog.ShowError(err, s.w)
+			return
+		}
+		s.changed()
+	}, s.w)
+}
+
+// sortedIDs: папки в порядке дерева
+func (s *folderSidebar) sortedIDs() []int {
+	var ids []int
+	var walk func(parent int)
+	walk = func(parent int) {
+		for _, id := range s.children[parent] {
+			ids = append(ids, id)
+			walk(id)
+		}
+	}
+	walk(0)
+	return ids
+}
+
+// showTagManager: переименование, слияние и удаление тегов
+func showTagManager(w fyne.Window, appInstance *app.App, onChange func()) {
+	var tags []model.Tag
+	selected := -1
+
+	list := widget.NewList(
+		func() int { return len(tags) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			o.(*widget.Label).SetText("#" + tags[i].Name + " (" + strconv.Itoa(tags[i].Count) + ")")
+		},
+	)
+	list.OnSelected = func(i widget.ListItemID) { selected = i }
+
+	reload := func() {
+		var err error
+		if tags, err = appInstance.DB.GetTags(); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		selected = -1
+		list.UnselectAll()
+		list.Refresh()
+	}
+	changed := func() {
+		reload()
+		if onChange != nil {
+			onChange()
+		}
+	}
+	current := func() (model.Tag, bool) {
+		if selected < 0 || selected >= len(tags) {
+			dialog.ShowInformation(i18n.T("Info"), i18n.T("Select_tag"), w)
+			return model.Tag{}, false
+		}
+		return tags[selected], true
+	}
+
+	renameBtn := widget.NewButtonWithIcon(i18n.T("Rename"), theme.DocumentCreateIcon(), func() {
+		t, ok := current()
+		if !ok {
+			return
+		}
+		name := widget.NewEntry()
+		name.SetText(t.Name)
+		dialog.ShowForm(i18n.T("Rename"), i18n.T("Save"), i18n.T("Cancel"), []*widget.FormItem{
+			widget.NewFormItem(i18n.T("Tag_name"), name),
+		}, func(ok bool) {
+			if !ok {
+				return
+			}
+			if err := appInstance.DB.RenameTag(t.ID, name.Text); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			changed()
+		}, w)
+	})
+	mergeBtn := widget.NewButtonWithIcon(i18n.T("Merge"), theme.ContentPasteIcon(), func() {
+		t, ok := current()
+		if !ok {
+			return
+		}
+		targets := map[string]int{}
+		var names []string
+		for _, other := range tags {
+			if other.ID != t.ID {
+				targets[other.Name] = other.ID
+				names = append(names, other.Name)
+			}
+		}
+		into := widget.NewSelect(names, nil)
+		dialog.ShowForm(i18n.T("Merge"), i18n.T("Merge"), i18n.T("Cancel"), []*widget.FormItem{
+			widget.NewFormItem(i18n.T("Tag"), widget.NewLabel("#"+t.Name)),
+			widget.NewFormItem(i18n.T("Merge_into"), into),
+		}, func(ok bool) {
+			if !ok || into.Selected == "" {
+				return
+			}
+			if err := appInstance.DB.MergeTags(t.ID, targets[into.Selected]); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			changed()
+		}, w)
+	})
+	deleteBtn := widget.NewButtonWithIcon(i18n.T("Delete"), theme.DeleteIcon(), func() {
+		t, ok := current()
+		if !ok {
+			return
+		}
+		dialog.ShowConfirm(i18n.T("Delete"), i18n.T("Delete_tag_confirm")+"\n#"+t.Name, func(ok bool) {
+			if !ok {
+				return
+			}
+			if err := appInstance.DB.DeleteTag(t.ID); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			changed()
+		}, w)
+	})
+
+	reload()
+	content := container.NewBorder(
+		nil,
+		container.NewGridWithColumns(3, renameBtn, mergeBtn, deleteBtn),
+		nil, nil,
+		list,
+	)
+	d := dialog.NewCustom(i18n.T("Manage_tags"), i18n.T("Close"), content, w)
+	d.Resize(fyne.NewSize(420, 420))
+	d.Show()
+}
+
+// folderPaths: пути всех папок для подсказок в формах
+func folderPaths(appInstance *app.App) []string {
+	folders, err := appInstance.DB.GetFolders()
+	if err != nil {
+		return nil
+	}
+	paths := make([]string, len(folders))
+	for i, f := range folders {
+		paths[i] = f.Path
+	}
+	return paths
+}
+
+// parseTags: "work, #urgent" → ["work", "urgent"]
+func parseTags(text string) []string {
+	tags := []string{}
+	for _, t := range strings.Split(text, ",") {
+		if t = strings.TrimPrefix(strings.TrimSpace(t), "#"); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// tagChips: теги для таблицы — "#work #urgent"
+func tagChips(tags []string) string {
+	chips := make([]string, len(tags))
+	for i, t := range tags {
+		chips[i] = "#" + t
+	}
+	return strings.Join(chips, " ")
+}
@@ -0,0 +1,243 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"password-manager/internal/i18n"
+	"password-manager/pkg/utils"
+)
+
+// generatorMode — генератор в формах создания и изменения: переключатель
+// «символы / парольная фраза» и опции выбранного режима (другие скрыты).
+type generatorMode struct {
+	radio  *widget.RadioGroup
+	chars  *charOptions
+	phrase *passphraseOptions
+}
+
+func newGeneratorMode() *generatorMode {
+	g := &generatorMode{chars: newCharOptions(), phrase: newPassphraseOptions()}
+	g.radio = widget.NewRadioGroup([]string{i18n.T("Characters"), i18n.T("Passphrase")}, func(string) {
+		if g.Passphrase() {
+			g.chars.content.Hide()
+			g.phrase.content.Show()
+		} else {
+			g.phrase.content.Hide()
+			g.chars.content.Show()
+		}
+	})
+	g.radio.Horizontal = true
+	g.radio.Required = true
+	g.radio.SetSelected(i18n.T("Characters"))
+	return g
+}
+
+func (g *generatorMode) Passphrase() bool {
+	return g.radio.Selected == i18n.T("Passphrase")
+}
+
+func (g *generatorMode) Content() fyne.CanvasObject {
+	return container.NewVBox(g.radio, g.chars.content, g.phrase.content)
+}
+
+// Generate вставляет новый пароль или фразу в поле и пишет итог в status
+func (g *generatorMode) Generate(passwordEntry *widget.Entry, status *widget.Label) {
+	defer clearStatusLater(status)
+	if g.Passphrase() {
+		phrase, entropy, err := g.phrase.Generate()
+		if err != nil {
+			status.SetText(i18n.T("Generation_error") + ": " + err.Error())
+			return
+		}
+		passwordEntry.SetText(phrase)
+		status.SetText(i18n.T("Passphrase_inserted") + fmt.Sprintf(" (%.0f ", entropy) + i18n.T("bits") + ")")
+		return
+	}
+
+	policy, err := g.chars.Policy()
+	if err == nil {
+		var password string
+		if password, err = utils.GenerateWithPolicy(policy); err == nil {
+			passwordEntry.SetText(password)
+			status.SetText(i18n.T("Generated_inserted"))
+			return
+		}
+	}
+	status.SetText(i18n.T("Generation_error") + ": " + err.Error())
+}
+
+// Rules — что сохранить в записи: правила генератора, если отмечено «сохранить»,
+// иначе пустая политика (удаляет сохранённую)
+func (g *generatorMode) Rules() (*utils.GeneratorPolicy, error) {
+	if g.Passphrase() || !g.chars.save.Checked {
+		return &utils.GeneratorPolicy{}, nil
+	}
+	policy, err := g.chars.Policy()
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// LoadRules показывает сохранённые правила записи (nil — правил нет)
+func (g *generatorMode) LoadRules(p *utils.GeneratorPolicy) {
+	g.chars.save.SetChecked(p != nil)
+	if p != nil {
+		g.radio.SetSelected(i18n.T("Characters"))
+		g.chars.SetPolicy(*p)
+	}
+}
+
+// charOptions — правила посимвольного генератора (utils.GeneratorPolicy)
+type charOptions struct {
+	length, exclude, symbolSet                *widget.Entry
+	upper, lower, digits, symbols             *widget.Check
+	minUpper, minLower, minDigits, minSymbols *widget.Entry
+	ambiguous, noRepeat, pronounceable, save  *widget.Check
+	content                                   *fyne.Container
+}
+
+func newCharOptions() *charOptions {
+	o := &charOptions{
+		length:        widget.NewEntry(),
+		exclude:       widget.NewEntry(),
+		symbolSet:     widget.NewEntry(),
+		upper:         widget.NewCheck("A-Z", nil),
+		lower:         widget.NewCheck("a-z", nil),
+		digits:        widget.NewCheck("0-9", nil),
+		symbols:       widget.NewCheck("!@#", nil),
+		minUpper:      widget.NewEntry(),
+		minLower:      widget.NewEntry(),
+		minDigits:     widget.NewEntry(),
+		minSymbols:    widget.NewEntry(),
+		ambiguous:     widget.NewCheck(i18n.T("Exclude_ambiguous")+" ("+utils.AmbiguousChars+")", nil),
+		noRepeat:      widget.NewCheck(i18n.T("No_repeat"), nil),
+		pronounceable: widget.NewCheck(i18n.T("Pronounceable"), nil),
+		save:          widget.NewCheck(i18n.T("Save_generator_rules"), nil),
+	}
+	o.symbolSet.SetPlaceHolder(utils.DefaultSymbols)
+	for _, e := range []*widget.Entry{o.minUpper, o.minLower, o.minDigits, o.minSymbols} {
+		e.SetPlaceHolder(i18n.T("Min_count"))
+	}
+	o.SetPolicy(utils.DefaultGeneratorPolicy())
+
+	o.content = container.NewVBox(
+		container.NewGridWithColumns(2,
+			container.NewVBox(widget.NewLabel(i18n.T("Length")), o.length),
+			container.NewVBox(widget.NewLabel(i18n.T("Exclude")), o.exclude),
+		),
+		container.NewGridWithColumns(4, o.upper, o.lower, o.digits, o.symbols),
+		container.NewGridWithColumns(4, o.minUpper, o.minLower, o.minDigits, o.minSymbols),
+		container.NewVBox(widget.NewLabel(i18n.T("Symbol_set")), o.symbolSet),
+		container.NewGridWithColumns(3, o.ambiguous, o.noRepeat, o.pronounceable),
+		o.save,
+	)
+	return o
+}
+
+func (o *charOptions) SetPolicy(p utils.GeneratorPolicy) {
+	o.length.SetText(strconv.Itoa(p.Length))
+	o.exclude.SetText(p.Exclude)
+	o.symbolSet.SetText(p.SymbolSet)
+	o.upper.SetChecked(p.Upper)
+	o.lower.SetChecked(p.Lower)
+	o.digits.SetChecked(p.Digits)
+	o.symbols.SetChecked(p.Symbols)
+	for e, n := range map[*widget.Entry]int{o.minUpper: p.MinUpper, o.minLower: p.MinLower, o.minDigits: p.MinDigits, o.minSymbols: p.MinSymbols} {
+		e.SetText("")
+		if n > 0 {
+			e.SetText(strconv.Itoa(n))
+		}
+	}
+	o.ambiguous.SetChecked(p.ExcludeAmbiguous)
+	o.noRepeat.SetChecked(p.NoRepeat)
+	o.pronounceable.SetChecked(p.Pronounceable)
+}
+
+// Policy собирает правила из формы; пустой минимум — 0 (для включённого класса это «хотя бы один»)
+func (o *charOptions) Policy() (utils.GeneratorPolicy, error) {
+	length, err := strconv.Atoi(strings.TrimSpace(o.length.Text))
+	if err != nil || length <= 0 {
+		return utils.GeneratorPolicy{}, errors.New(i18n.T("Invalid_length"))
+	}
+	p := utils.GeneratorPolicy{
+		Length:           length,
+		Upper:            o.upper.Checked,
+		Lower:            o.lower.Checked,
+		Digits:           o.digits.Checked,
+		Symbols:          o.symbols.Checked,
+		SymbolSet:        o.symbolSet.Text,
+		Exclude:          o.exclude.Text,
+		ExcludeAmbiguous: o.ambiguous.Checked,
+		NoRepeat:         o.noRepeat.Checked,
+		Pronounceable:    o.pronounceable.Checked,
+	}
+	for e, dst := range map[*widget.Entry]*int{o.minUpper: &p.MinUpper, o.minLower: &p.MinLower, o.minDigits: &p.MinDigits, o.minSymbols: &p.MinSymbols} {
+		text := strings.TrimSpace(e.Text)
+		if text == "" {
+			continue
+		}
+		if *dst, err = strconv.Atoi(text); err != nil {
+			return utils.GeneratorPolicy{}, errors.New(i18n.T("Min_count_invalid"))
+		}
+	}
+	return p, nil
+}
+
+// passphraseOptions — опции diceware-генератора
+type passphraseOptions struct {
+	words      *widget.Entry
+	separator  *widget.Entry
+	digits     *widget.Entry
+	capitalize *widget.Check
+	content    *fyne.Container
+}
+
+func newPassphraseOptions() *passphraseOptions {
+	def := utils.DefaultPassphraseOptions()
+	o := &passphraseOptions{
+		words:      widget.NewEntry(),
+		separator:  widget.NewEntry(),
+		digits:     widget.NewEntry(),
+		capitalize: widget.NewCheck(i18n.T("Capitalize_words"), nil),
+	}
+	o.words.SetText(strconv.Itoa(def.Words))
+	o.separator.SetText(def.Separator)
+	o.digits.SetText(strconv.Itoa(def.Digits))
+
+	o.content = container.NewVBox(
+		container.NewGridWithColumns(3,
+			container.NewVBox(widget.NewLabel(i18n.T("Word_count")), o.words),
+			container.NewVBox(widget.NewLabel(i18n.T("Separator")), o.separator),
+			container.NewVBox(widget.NewLabel(i18n.T("Digits_to_insert")), o.digits),
+		),
+		o.capitalize,
+	)
+	o.content.Hide()
+	return o
+}
+
+// Generate возвращает фразу и её энтропию в битах
+func (o *passphraseOptions) Generate() (string, float64, error) {
+	words, err1 := strconv.Atoi(strings.TrimSpace(o.words.Text))
+	digits, err2 := strconv.Atoi(strings.TrimSpace(o.digits.Text))
+	if err1 != nil || err2 != nil {
+		return "", 0, errors.New(i18n.T("Passphrase_options_invalid"))
+	}
+	return utils.GeneratePassphrase(utils.PassphraseOptions{
+		Words:      words,
+		Separator:  o.separator.Text,
+		Capitalize: o.capitalize.Checked,
+		Digits:     digits,
+	})
+}
@@ -0,0 +1,194 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"password-manager/internal/app"
+	"password-manager/internal/app/report"
+	"password-manager/internal/i18n"
+	"password-manager/pkg/security"
+)
+
+// Путь к локальной базе Pwned Passwords — настройка приложения, а не хранилища
+const breachPathPref = "breach_index_path"
+
+// loadBreachIndex подключает сохранённую базу утечек (один раз за запуск)
+func loadBreachIndex() {
+	path := fyne.CurrentApp().Preferences().String(breachPathPref)
+	if path == "" || security.Breaches.Enabled() {
+		return
+	}
+	if err := security.Breaches.Open(path); err != nil {
+		log.Printf("breach index %s: %v", path, err)
+	}
+}
+
+// buildHealthTab: вкладка «Здоровье» — повторы, слабые, старые и утёкшие пароли.
+// Проверка расшифровывает все записи, поэтому запускается только кнопкой.
+func buildHealthTab(w fyne.Window, appInstance *app.App) fyne.CanvasObject {
+	def := report.DefaultOptions()
+	scoreSelect := widget.NewSelect([]string{"0", "1", "2", "3", "4"}, nil)
+	scoreSelect.SetSelected(strconv.Itoa(def.MinScore))
+	ageEntry := widget.NewEntry()
+	ageEntry.SetText(strconv.Itoa(def.MaxAgeDays))
+
+	summary := widget.NewLabel(i18n.T("Health_hint"))
+	summary.Wrapping = fyne.TextWrapWord
+	sections := widget.NewAccordion()
+	sections.MultiOpen = true
+
+	var checkBtn *widget.Button
+	checkBtn = widget.NewButtonWithIcon(i18n.T("Check_health"), theme.ViewRefreshIcon(), func() {
+		age, err := strconv.Atoi(strings.TrimSpace(ageEntry.Text))
+		if err != nil {
+			dialog.ShowError(errors.New(i18n.T("Invalid_max_age")), w)
+			return
+		}
+		score, _ := strconv.Atoi(scoreSelect.Selected)
+		opts := report.Options{MinScore: score, MaxAgeDays: age}
+		if err := opts.Validate(); err != nil {
+			dialog.ShowError(errors.New(i18n.T("Invalid_max_age")), w)
+			return
+		}
+
+		checkBtn.Disable()
+		summary.SetText(i18n.T("Checking"))
+		go func() {
+			r, err := report.Generate(appInstance.DB, opts)
+			fyne.Do(func() {
+				checkBtn.Enable()
+				if err != nil {
+					summary.SetText("")
+					dialog.ShowError(err, w)
+					return
+				}
+				showReport(r, summary, sections)
+			})
+		}()
+	})
+
+	controls := container.NewGridWithColumns(2,
+		container.NewVBox(widget.NewLabel(i18n.T("Min_score")), scoreSelect),
+		container.NewVBox(widget.NewLabel(i18n.T("Max_age_days")), ageEntry),
+	)
+	return container.NewBorder(
+		container.NewVBox(controls, breachIndexRow(w), checkBtn, summary, widget.NewSeparator()),
+		nil, nil, nil,
+		container.NewVScroll(sections),
+	)
+}
+
+// showReport: итог и три раскрывающихся списка (пустые не показываются)
+func showReport(r *report.Report, summary *widget.Label, sections *widget.Accordion) {
+	text := fmt.Sprintf("%s: %d · %s: %d", i18n.T("Entries_checked"), r.Total, i18n.T("Issues_found"), r.Issues())
+	if len(r.Failed) > 0 {
+		ids := make([]string, len(r.Failed))
+		for i, id := range r.Failed {
+			ids[i] = "#" + strconv.Itoa(id)
+		}
+		text += "\n⚠ " + i18n.T("Undecryptable_entries") + ": " + strings.Join(ids, ", ")
+	}
+	if !r.BreachCheck {
+		text += "\nℹ " + i18n.T("Breach_check_off")
+	}
+	if r.Issues() == 0 {
+		text += "\n✅ " + i18n.T("No_issues")
+	}
+	summary.SetText(text)
+
+	sections.Items = nil
+	if len(r.Reused) > 0 {
+		lines := make([]string, len(r.Reused))
+		for i, g := range r.Reused {
+			names := make([]string, len(g))
+			for j, e := range g {
+				names[j] = reportEntryText(e)
+			}
+			lines[i] = "• " + strings.Join(names, "\n   ")
+		}
+		sections.Append(reportSection(i18n.T("Reused_passwords"), len(r.Reused), lines))
+	}
+	if len(r.Weak) > 0 {
+		lines := make([]string, len(r.Weak))
+		for i, e := range r.Weak {
+			lines[i] = fmt.Sprintf("• %s (%d/4)", reportEntryText(e), e.Score)
+			if e.Warning != "" {
+				lines[i] += ": " + hintText(e.Warning)
+			}
+		}
+		sections.Append(reportSection(i18n.T("Weak_passwords"), len(r.Weak), lines))
+	}
+	if len(r.Old) > 0 {
+		lines := make([]string, len(r.Old))
+		for i, e := range r.Old {
+			lines[i] = fmt.Sprintf("• %s (%d %s)", reportEntryText(e), e.AgeDays, i18n.T("days"))
+		}
+		sections.Append(reportSection(i18n.T("Old_passwords"), len(r.Old), lines))
+	}
+	if len(r.Breached) > 0 {
+		lines := make([]string, len(r.Breached))
+		for i, e := range r.Breached {
+			lines[i] = fmt.Sprintf("• %s (%d)", reportEntryText(e), e.Breached)
+		}
+		sections.Append(reportSection(i18n.T("Breached_passwords"), len(r.Breached), lines))
+	}
+	sections.OpenAll()
+	sections.Refresh()
+}
+
+func reportEntryText(e report.Entry) string {
+	return "#" + strconv.Itoa(e.ID) + "  " + e.Service + " — " + e.Username
+}
+
+func reportSection(title string, count int, lines []string) *widget.AccordionItem {
+	label := widget.NewLabel(strings.Join(lines, "\n"))
+	label.Wrapping = fyne.TextWrapWord
+	return widget.NewAccordionItem(fmt.Sprintf("%s (%d)", title, count), label)
+}
+
+// breachIndexRow: выбор файла (или каталога диапазонов) Pwned Passwords; пусто — проверка выключена
+func breachIndexRow(w fyne.Window) fyne.CanvasObject {
+	pathEntry := widget.NewEntry()
+	pathEntry.SetPlaceHolder(i18n.T("Breach_index_placeholder"))
+	pathEntry.SetText(security.Breaches.Path())
+
+	apply := func() {
+		path := strings.TrimSpace(pathEntry.Text)
+		if err := security.Breaches.Open(path); err != nil {
+			dialog.ShowError(errors.New(i18n.T("Breach_index_invalid")+": "+err.Error()), w)
+			return
+		}
+		fyne.CurrentApp().Preferences().SetString(breachPathPref, path)
+		dialog.ShowInformation(i18n.T("Success"), i18n.T("Settings_saved"), w)
+	}
+	browseBtn := widget.NewButtonWithIcon("", theme.FolderOpenIcon(), func() {
+		dialog.ShowFileOpen(func(in fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if in == nil {
+				return
+			}
+			pathEntry.SetText(in.URI().Path())
+			in.Close()
+			apply()
+		}, w)
+	})
+	saveBtn := widget.NewButtonWithIcon("", theme.ConfirmIcon(), apply)
+
+	return container.NewVBox(
+		widget.NewLabel(i18n.T("Breach_index")),
+		container.NewBorder(nil, nil, nil, container.NewHBox(browseBtn, saveBtn), pathEntry),
+	)
+}
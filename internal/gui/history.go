@@ -0,0 +1,106 @@
+package gui
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"password-manager/internal/app"
+	"password-manager/internal/i18n"
+)
+
+// ShowHistoryWindow: прежние пароли записи с копированием и восстановлением
+func ShowHistoryWindow(a fyne.App, appInstance *app.App, onSuccess func()) {
+	factory := CurrentFactory()
+	a.Settings().SetTheme(factory.Theme())
+
+	w := a.NewWindow(i18n.T("Password_history"))
+	w.Resize(factory.SmallWindowSize())
+	w.CenterOnScreen()
+
+	idEntry := widget.NewEntry()
+	idEntry.SetPlaceHolder(i18n.T("Enter_ID"))
+	statusLabel := widget.NewLabel("")
+	list := container.NewVBox()
+
+	var load func()
+	load = func() {
+		id, err := strconv.Atoi(strings.TrimSpace(idEntry.Text))
+		if err != nil {
+			dialog.ShowInformation(i18n.T("Info"), i18n.T("Please_enter_ID"), w)
+			return
+		}
+		history, err := appInstance.DB.GetPasswordHistory(id)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		list.RemoveAll()
+		if len(history) == 0 {
+			list.Add(widget.NewLabel(i18n.T("No_history")))
+			return
+		}
+		for _, h := range history {
+			changed := h.ChangedAt
+			if t, err := time.Parse(time.RFC3339, h.ChangedAt); err == nil {
+				changed = t.Local().Format("02 Jan 2006, 15:04")
+			}
+
+			copyBtn := widget.NewButtonWithIcon(i18n.T("Copy"), theme.ContentCopyIcon(), func() {
+				plain, err := appInstance.DB.DecryptHistoryPassword(id, h.ID)
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				copySecret(appInstance.DB, statusLabel, plain, "Password_copied")
+			})
+			restoreBtn := widget.NewButtonWithIcon(i18n.T("Restore"), theme.HistoryIcon(), func() {
+				dialog.ShowConfirm(i18n.T("Restore"), i18n.T("Restore_password_confirm"), func(ok bool) {
+					if !ok {
+						return
+					}
+					if err := appInstance.DB.RestorePassword(id, h.ID); err != nil {
+						dialog.ShowError(err, w)
+						return
+					}
+					statusLabel.SetText(i18n.T("Password_restored"))
+					clearStatusLater(statusLabel)
+					if onSuccess != nil {
+						onSuccess()
+					}
+					load()
+				}, w)
+			})
+
+			list.Add(container.NewBorder(nil, nil, nil,
+				container.NewHBox(copyBtn, restoreBtn),
+				widget.NewLabel("🕓 "+changed),
+			))
+		}
+	}
+
+	showBtn := widget.NewButtonWithIcon(i18n.T("Show"), theme.SearchIcon(), load)
+	idEntry.OnSubmitted = func(string) { load() }
+
+	content := container.NewVBox(
+		widget.NewLabelWithStyle("🕓 "+i18n.T("Password_history"), fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+		container.NewBorder(nil, nil, nil, showBtn, idEntry),
+		widget.NewSeparator(),
+		list,
+		widget.NewSeparator(),
+		statusLabel,
+	)
+
+	scroll := container.NewVScroll(content)
+	scroll.SetMinSize(factory.SmallWindowSize())
+	w.SetContent(container.NewPadded(scroll))
+	trackActivity(w)
+	w.Show()
+}
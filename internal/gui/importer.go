@@ -0,0 +1,184 @@
+package gui
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"password-manager/internal/app"
+	"password-manager/internal/app/importer"
+	"password-manager/internal/i18n"
+)
+
+// ShowExternalImportWindow: импорт из Bitwarden, KeePass или CSV браузера.
+// Файл → предпросмотр (дубликаты, папки → категории) → импорт.
+func ShowExternalImportWindow(a fyne.App, appInstance *app.App, onSuccess func()) {
+	factory := CurrentFactory()
+	a.Settings().SetTheme(factory.Theme())
+
+	w := a.NewWindow(i18n.T("Import_other"))
+	w.Resize(factory.WindowSize())
+	w.CenterOnScreen()
+
+	var (
+		data    []byte
+		items   []importer.Item
+		preview []importer.PreviewItem
+		mapping = importer.Mapping{}
+	)
+
+	formatSelect := widget.NewSelect(importer.Formats, nil)
+	fileLabel := widget.NewLabel(i18n.T("No_file_selected"))
+	fileLabel.Truncation = fyne.TextTruncateEllipsis
+	passEntry := widget.NewPasswordEntry()
+	passEntry.SetPlaceHolder(i18n.T("KeePass_password"))
+	passEntry.Hide()
+	formatSelect.OnChanged = func(format string) {
+		if format == importer.FormatKeePass {
+			passEntry.Show()
+		} else {
+			passEntry.Hide()
+		}
+	}
+	formatSelect.SetSelected(importer.FormatBitwarden)
+
+	chooseBtn := widget.NewButtonWithIcon(i18n.T("Choose_file"), theme.FolderOpenIcon(), func() {
+		dialog.ShowFileOpen(func(in fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if in == nil {
+				return
+			}
+			defer in.Close()
+			if data, err = io.ReadAll(in); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			fileLabel.SetText(in.URI().Name())
+			if format := importer.DetectFormat(in.URI().Name()); format != "" {
+				formatSelect.SetSelected(format)
+			}
+		}, w)
+	})
+
+	summary := widget.NewLabel("")
+	mappingBox := container.NewVBox()
+	skipCheck := widget.NewCheck(i18n.T("Skip_duplicates"), nil)
+	skipCheck.SetChecked(true)
+
+	list := widget.NewList(
+		func() int { return len(preview) },
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			it := preview[i]
+			text := it.Service + " — " + it.Username
+			if it.Category != "" {
+				text += "  📂 " + it.Category
+			}
+			if it.DuplicateOf != 0 {
+				text = "⚠ " + text + "  (" + i18n.T("Duplicate_of") + strconv.Itoa(it.DuplicateOf) + ")"
+			}
+			o.(*widget.Label).SetText(text)
+		},
+	)
+
+	// refresh пересчитывает предпросмотр после смены сопоставления
+	refresh := func() {
+		existing, err := appInstance.DB.GetAllPasswords()
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		preview = importer.Preview(items, existing, mapping)
+		duplicates := 0
+		for _, it := range preview {
+			if it.DuplicateOf != 0 {
+				duplicates++
+			}
+		}
+		summary.SetText(strconv.Itoa(len(preview)) + " / ⚠ " + strconv.Itoa(duplicates))
+		list.Refresh()
+	}
+
+	previewBtn := widget.NewButtonWithIcon(i18n.T("Preview"), theme.VisibilityIcon(), func() {
+		if data == nil {
+			dialog.ShowError(errors.New(i18n.T("No_file_selected")), w)
+			return
+		}
+		var err error
+		items, err = importer.Parse(formatSelect.Selected, bytes.NewReader(data), passEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		// папки файла → папки хранилища: по умолчанию тот же путь
+		folders := folderPaths(appInstance)
+		mapping = importer.Mapping{}
+		mappingBox.RemoveAll()
+		for _, folder := range importer.Folders(items) {
+			target := widget.NewSelectEntry(folders)
+			target.SetText(folder)
+			target.OnChanged = func(category string) {
+				mapping[folder] = category
+				refresh()
+			}
+			mappingBox.Add(container.NewGridWithColumns(2, widget.NewLabel("📁 "+folder), target))
+		}
+		refresh()
+	})
+
+	importBtn := widget.NewButtonWithIcon(i18n.T("Import_entries"), theme.DownloadIcon(), func() {
+		if len(preview) == 0 {
+			dialog.ShowInformation(i18n.T("Import_other"), i18n.T("Nothing_to_import"), w)
+			return
+		}
+		res, err := importer.Apply(appInstance.DB, preview, skipCheck.Checked)
+		if err != nil {
+			// импорт откатывается целиком — превью остаётся, можно повторить
+			dialog.ShowError(err, w)
+			return
+		}
+		if onSuccess != nil && res.Imported > 0 {
+			onSuccess()
+		}
+		dialog.ShowInformation(i18n.T("Import_other"),
+			i18n.T("Imported_count")+" "+strconv.Itoa(res.Imported)+", "+i18n.T("Skipped_count")+" "+strconv.Itoa(res.Skipped), w)
+		preview, items = nil, nil
+		mappingBox.RemoveAll()
+		summary.SetText("")
+		list.Refresh()
+	})
+	importBtn.Importance = widget.HighImportance
+
+	top := container.NewVBox(
+		widget.NewLabelWithStyle("📥 "+i18n.T("Import_other"), fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+		container.NewBorder(nil, nil, widget.NewLabel(i18n.T("Import_format")), nil, formatSelect),
+		container.NewBorder(nil, nil, nil, chooseBtn, fileLabel),
+		passEntry,
+		previewBtn,
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle(i18n.T("Folder_mapping"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		mappingBox,
+		widget.NewSeparator(),
+		summary,
+	)
+	bottom := container.NewVBox(widget.NewSeparator(), skipCheck, importBtn)
+
+	w.SetContent(container.NewPadded(container.NewBorder(top, bottom, nil, nil, list)))
+	trackActivity(w)
+	w.Show()
+}
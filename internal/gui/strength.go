@@ -0,0 +1,94 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"password-manager/internal/i18n"
+	"password-manager/pkg/security"
+)
+
+// Подсказки оценщика стойкости → ключи перевода
+var strengthHintKeys = map[security.Hint]string{
+	security.HintTopTen:           "Strength_top_ten",
+	security.HintTopHundred:       "Strength_top_hundred",
+	security.HintVeryCommon:       "Strength_very_common",
+	security.HintSimilarToCommon:  "Strength_similar_to_common",
+	security.HintWordByItself:     "Strength_word_by_itself",
+	security.HintNamesByThemself:  "Strength_names_by_themselves",
+	security.HintCommonNames:      "Strength_common_names",
+	security.HintUserInputs:       "Strength_user_inputs",
+	security.HintStraightRows:     "Strength_straight_rows",
+	security.HintKeyboardPattern:  "Strength_keyboard_pattern",
+	security.HintRepeatChar:       "Strength_repeat_char",
+	security.HintRepeatPattern:    "Strength_repeat_pattern",
+	security.HintSequence:         "Strength_sequence",
+	security.HintRecentYears:      "Strength_recent_years",
+	security.HintDates:            "Strength_dates",
+	security.HintUseWords:         "Strength_use_words",
+	security.HintNoNeedForSymbols: "Strength_no_need_for_symbols",
+	security.HintAddWord:          "Strength_add_word",
+	security.HintCapitalization:   "Strength_capitalization",
+	security.HintAllUppercase:     "Strength_all_uppercase",
+	security.HintReversedWords:    "Strength_reversed_words",
+	security.HintL33t:             "Strength_l33t",
+	security.HintLongerKeyboard:   "Strength_longer_keyboard",
+	security.HintAvoidRepeats:     "Strength_avoid_repeats",
+	security.HintAvoidSequences:   "Strength_avoid_sequences",
+	security.HintAvoidYears:       "Strength_avoid_years",
+	security.HintAvoidDates:       "Strength_avoid_dates",
+}
+
+var crackUnitKeys = map[string]string{
+	security.CrackSeconds: "Unit_seconds",
+	security.CrackMinutes: "Unit_minutes",
+	security.CrackHours:   "Unit_hours",
+	security.CrackDays:    "Unit_days",
+	security.CrackMonths:  "Unit_months",
+	security.CrackYears:   "Unit_years",
+}
+
+func hintText(h security.Hint) string {
+	if key, ok := strengthHintKeys[h]; ok {
+		return i18n.T(key)
+	}
+	return string(h)
+}
+
+func crackTimeText(seconds float64) string {
+	n, unit := security.CrackTimeUnit(seconds)
+	switch unit {
+	case security.CrackInstant:
+		return i18n.T("Crack_instant")
+	case security.CrackCenturies:
+		return i18n.T("Crack_centuries")
+	}
+	return fmt.Sprintf("%d %s", n, i18n.T(crackUnitKeys[unit]))
+}
+
+// strengthText — оценка под полем пароля: вердикт, балл, время офлайн-перебора,
+// находка в локальной базе утечек и подсказки; inputs — сервис, логин и ссылка
+// из той же формы
+func strengthText(password string, inputs ...string) string {
+	if password == "" {
+		return ""
+	}
+	st := security.EstimateStrength(password, inputs...)
+	// ошибка чтения базы утечек не мешает остальной оценке
+	breached, _ := security.Breaches.Count(password)
+	verdict := "✅ " + i18n.T("Strong_password")
+	if st.Weak() || breached > 0 {
+		verdict = "❌ " + i18n.T("Weak_password")
+	}
+	lines := []string{fmt.Sprintf("%s (%d/4) · %s: %s", verdict, st.Score, i18n.T("Crack_time"), crackTimeText(st.CrackTimes.OfflineSlowHash))}
+	if breached > 0 {
+		lines = append(lines, fmt.Sprintf("⚠ %s: %d", i18n.T("Found_in_breaches"), breached))
+	}
+	if st.Feedback.Warning != "" {
+		lines = append(lines, hintText(st.Feedback.Warning))
+	}
+	for _, s := range st.Feedback.Suggestions {
+		lines = append(lines, "• "+hintText(s))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,121 @@
+package gui
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"password-manager/internal/app"
+	"password-manager/internal/app/model"
+	"password-manager/internal/i18n"
+)
+
+// buildTrashTab: вкладка «Корзина» — удалённые записи, восстановление,
+// окончательное удаление и срок хранения. onChange вызывается после
+// восстановления, чтобы обновить основной список; refresh — перечитать корзину.
+func buildTrashTab(w fyne.Window, appInstance *app.App, onChange func()) (content fyne.CanvasObject, refresh func()) {
+	var trash []model.PasswordListItem
+	statusLabel := widget.NewLabel("")
+
+	list := widget.NewList(
+		func() int { return len(trash) },
+		func() fyne.CanvasObject {
+			restoreBtn := widget.NewButtonWithIcon(i18n.T("Restore"), theme.ContentUndoIcon(), nil)
+			purgeBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), nil)
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return container.NewBorder(nil, nil, nil, container.NewHBox(restoreBtn, purgeBtn), label)
+		},
+		nil,
+	)
+
+	refresh = func() {
+		items, err := appInstance.DB.GetTrash()
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		trash = items
+		list.Refresh()
+	}
+
+	list.UpdateItem = func(i widget.ListItemID, o fyne.CanvasObject) {
+		if i >= len(trash) {
+			return
+		}
+		entry := trash[i]
+		row := o.(*fyne.Container)
+		label := row.Objects[0].(*widget.Label)
+		buttons := row.Objects[1].(*fyne.Container)
+		restoreBtn := buttons.Objects[0].(*widget.Button)
+		purgeBtn := buttons.Objects[1].(*widget.Button)
+
+		deleted := entry.DeletedAt
+		if t, err := time.Parse(time.RFC3339, entry.DeletedAt); err == nil {
+			deleted = t.Local().Format("02 Jan 2006, 15:04")
+		}
+		label.SetText("#" + strconv.Itoa(entry.ID) + "  " + entry.Service + " — " + entry.Username + "  (🗑 " + deleted + ")")
+
+		id := strconv.Itoa(entry.ID)
+		restoreBtn.OnTapped = func() {
+			if err := appInstance.DB.RestoreFromTrash(id); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			statusLabel.SetText(i18n.T("Restored_from_trash"))
+			clearStatusLater(statusLabel)
+			refresh()
+			if onChange != nil {
+				onChange()
+			}
+		}
+		purgeBtn.OnTapped = func() {
+			dialog.ShowConfirm(i18n.T("Delete_permanently"), i18n.T("Delete_permanently_confirm")+"\n"+entry.Service+" — "+entry.Username, func(ok bool) {
+				if !ok {
+					return
+				}
+				if err := appInstance.DB.PurgePassword(id); err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				refresh()
+			}, w)
+		}
+	}
+
+	// Срок хранения корзины
+	retentionEntry := widget.NewEntry()
+	retentionEntry.SetText(strconv.Itoa(appInstance.DB.TrashRetentionDays()))
+	saveRetention := widget.NewButtonWithIcon(i18n.T("Save"), theme.ConfirmIcon(), func() {
+		days, err := strconv.Atoi(strings.TrimSpace(retentionEntry.Text))
+		if err != nil || days < 0 {
+			dialog.ShowError(errors.New(i18n.T("Invalid_retention")), w)
+			return
+		}
+		if err := appInstance.DB.SetTrashRetentionDays(days); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		statusLabel.SetText(i18n.T("Settings_saved"))
+		clearStatusLater(statusLabel)
+		refresh()
+	})
+	retentionRow := container.NewBorder(nil, nil,
+		widget.NewLabel(i18n.T("Trash_retention_days")), saveRetention, retentionEntry,
+	)
+
+	refresh()
+	content = container.NewBorder(
+		container.NewVBox(retentionRow, widget.NewSeparator()),
+		statusLabel, nil, nil,
+		list,
+	)
+	return content, refresh
+}
@@ -5,6 +5,10 @@ package gui
 import (
     "errors"
     "fmt"
+    "log"
+    "os"
+    "path/filepath"
+    "strings"
 
     "fyne.io/fyne/v2"
     "fyne.io/fyne/v2/container"
@@ -16,16 +20,39 @@ import (
     "password-manager/internal/i18n"
 )
 
+// LaunchWithUnlock открывает последнее использованное хранилище из реестра
 func LaunchWithUnlock(a fyne.App) {
+    reg, err := pmapp.LoadDefaultRegistry()
+    if err != nil {
+        log.Fatal(err)
+    }
+    vault, err := reg.DefaultVault()
+    if err != nil {
+        log.Fatal(err)
+    }
+    appInstance := pmapp.InitDesktopApp(vault.Path)
+    appInstance.Vault = vault.Name
+    showVault(a, reg, appInstance)
+}
+
+// showUnlockWindow: разблокировка уже открытого хранилища (после автоблокировки)
+func showUnlockWindow(a fyne.App, appInstance *pmapp.App) {
+    reg, err := pmapp.LoadDefaultRegistry()
+    if err != nil {
+        log.Fatal(err)
+    }
+    showVault(a, reg, appInstance)
+}
+
+// showVault: экран разблокировки или, для нового хранилища, создания мастер-пароля
+func showVault(a fyne.App, reg *pmapp.Registry, appInstance *pmapp.App) {
     factory := CurrentFactory()
     a.Settings().SetTheme(factory.Theme())
 
-    appInstance := pmapp.InitDesktopApp("passwords.db")
-
     // Было: appInstance.DB.HasMasterPassword()
     // Стало: appInstance.HasMeta()
     if !appInstance.HasMeta() {
-        showCreateMasterPasswordForm(a, appInstance)
+        showCreateMasterPasswordForm(a, reg, appInstance)
         return
     }
 
@@ -69,6 +96,7 @@ func LaunchWithUnlock(a fyne.App) {
 
     form := container.NewVBox(
         title,
+        vaultPicker(a, w, reg, appInstance),
         widget.NewSeparator(),
         help,
         passwordEntry,
@@ -82,7 +110,7 @@ func LaunchWithUnlock(a fyne.App) {
     w.Show()
 }
 
-func showCreateMasterPasswordForm(a fyne.App, appInstance *pmapp.App) {
+func showCreateMasterPasswordForm(a fyne.App, reg *pmapp.Registry, appInstance *pmapp.App) {
     factory := CurrentFactory()
     a.Settings().SetTheme(factory.Theme())
 
@@ -123,7 +151,8 @@ func showCreateMasterPasswordForm(a fyne.App, appInstance *pmapp.App) {
         }
         dialog.ShowInformation(i18n.T("Success"), i18n.T("Master_password_saved"), w)
         w.Hide()
-        LaunchWithUnlock(a)
+        appInstance.Lock()
+        showVault(a, reg, appInstance)
     })
     save.Importance = widget.HighImportance
 
@@ -142,6 +171,7 @@ func showCreateMasterPasswordForm(a fyne.App, appInstance *pmapp.App) {
 
     form := container.NewVBox(
         title,
+        vaultPicker(a, w, reg, appInstance),
         widget.NewSeparator(),
         widget.NewLabel("📧 "+i18n.T("Email")), emailEntry,
         widget.NewLabel("🔑 "+i18n.T("Password")), passwordEntry,
@@ -156,3 +186,93 @@ func showCreateMasterPasswordForm(a fyne.App, appInstance *pmapp.App) {
     w.SetContent(container.NewCenter(container.NewPadded(form)))
     w.Show()
 }
+
+// vaultPicker: выбор хранилища, создание нового и подключение существующего файла.
+// Переключение закрывает текущую базу и показывает экран выбранного хранилища.
+func vaultPicker(a fyne.App, w fyne.Window, reg *pmapp.Registry, current *pmapp.App) fyne.CanvasObject {
+    switchTo := func(name string) bool {
+        vault, err := reg.Get(name)
+        if err != nil {
+            dialog.ShowError(err, w)
+            return false
+        }
+        next, err := pmapp.OpenDesktopApp(vault.Path)
+        if err != nil {
+            dialog.ShowError(err, w)
+            return false
+        }
+        next.Vault = vault.Name
+
+        // последнее открытое хранилище станет выбранным при следующем запуске
+        reg.SetDefault(vault.Name)
+        if err := reg.Save(); err != nil {
+            log.Printf("save vault registry: %v", err)
+        }
+        w.Hide()
+        current.Lock()
+        current.DB.Close()
+        showVault(a, reg, next)
+        return true
+    }
+
+    vaultSelect := widget.NewSelect(reg.Names(), nil)
+    vaultSelect.SetSelected(current.Vault)
+    vaultSelect.OnChanged = func(name string) {
+        if name != current.Vault && !switchTo(name) {
+            vaultSelect.SetSelected(current.Vault)
+        }
+    }
+
+    register := func(name, path string) {
+        if err := reg.Add(name, path); err != nil {
+            dialog.ShowError(err, w)
+            return
+        }
+        if err := reg.Save(); err != nil {
+            dialog.ShowError(err, w)
+            return
+        }
+        switchTo(strings.TrimSpace(name))
+    }
+
+    newBtn := widget.NewButtonWithIcon("", theme.ContentAddIcon(), func() {
+        nameEntry := widget.NewEntry()
+        items := []*widget.FormItem{widget.NewFormItem(i18n.T("Vault_name"), nameEntry)}
+        dialog.ShowForm(i18n.T("New_vault"), i18n.T("Confirm"), i18n.T("Cancel"), items, func(ok bool) {
+            if !ok {
+                return
+            }
+            path := reg.PathFor(nameEntry.Text)
+            if _, err := os.Stat(path); err == nil {
+                dialog.ShowError(errors.New(i18n.T("Vault_file_exists")), w)
+                return
+            }
+            register(nameEntry.Text, path)
+        }, w)
+    })
+
+    openBtn := widget.NewButtonWithIcon("", theme.FolderOpenIcon(), func() {
+        dialog.ShowFileOpen(func(in fyne.URIReadCloser, err error) {
+            if err != nil {
+                dialog.ShowError(err, w)
+                return
+            }
+            if in == nil {
+                return
+            }
+            path := in.URI().Path()
+            in.Close()
+
+            nameEntry := widget.NewEntry()
+            nameEntry.SetText(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+            items := []*widget.FormItem{widget.NewFormItem(i18n.T("Vault_name"), nameEntry)}
+            dialog.ShowForm(i18n.T("Add_existing_vault"), i18n.T("Confirm"), i18n.T("Cancel"), items, func(ok bool) {
+                if ok {
+                    register(nameEntry.Text, path)
+                }
+            }, w)
+        }, w)
+    })
+
+    return container.NewBorder(nil, nil, widget.NewIcon(theme.StorageIcon()), container.NewHBox(newBtn, openBtn), vaultSelect)
+}
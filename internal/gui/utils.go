@@ -1,9 +1,17 @@
 package gui
 
-import "password-manager/internal/app/model"
+import (
+    "fmt"
+    "time"
+
+    "fyne.io/fyne/v2"
+
+    "password-manager/internal/app/db"
+    "password-manager/internal/app/model"
+)
 
 // extractSuggestions возвращает уникальные значения для автоподсказок
-func extractSuggestions(passwords []model.PasswordListItem) (services, usernames, categories, links []string) {
+func extractSuggestions(passwords []model.PasswordListItem) (services, usernames, links []string) {
     unique := func(values []string) []string {
         m := map[string]bool{}
         var result []string
@@ -16,12 +24,46 @@ func extractSuggestions(passwords []model.PasswordListItem) (services, usernames
         return result
     }
 
-    var s, u, c, l []string
+    var s, u, l []string
     for _, p := range passwords {
         s = append(s, p.Service)
         u = append(u, p.Username)
-        c = append(c, p.Category)
         l = append(l, p.Link)
     }
-    return unique(s), unique(u), unique(c), unique(l)
+    return unique(s), unique(u), unique(l)
+}
+
+func hasTOTP(list []model.PasswordListItem) bool {
+    for _, p := range list {
+        if p.HasTOTP {
+            return true
+        }
+    }
+    return false
+}
+
+// totpCellText: текущий код и секунды до смены, например "123 456 · 17s".
+// Ключ хранилище отдаёт из кэша — здесь только вычисление кода.
+func totpCellText(storage db.Storage, row model.PasswordListItem) string {
+    if !row.HasTOTP {
+        return ""
+    }
+    t, err := storage.GetTOTP(row.ID)
+    if err != nil {
+        return "—"
+    }
+    now := time.Now()
+    code := t.Code(now)
+    half := len(code) / 2
+    return fmt.Sprintf("%s %s · %ds", code[:half], code[half:], int(t.Remaining(now)/time.Second))
+}
+
+// windowOpen: закрытое окно пропадает из списка окон драйвера
+func windowOpen(w fyne.Window) bool {
+    for _, other := range fyne.CurrentApp().Driver().AllWindows() {
+        if other == w {
+            return true
+        }
+    }
+    return false
 }
@@ -1,6 +1,7 @@
 package gui
 
 import (
+	"database/sql"
 	"errors"
 	"strconv"
 	"strings"
@@ -16,6 +17,7 @@ import (
 	"password-manager/internal/app/db"
 	"password-manager/internal/app/model"
 	"password-manager/internal/i18n"
+	"password-manager/pkg/security"
 	"password-manager/pkg/utils"
 )
 
@@ -23,37 +25,20 @@ func ShowMainWindow(a fyne.App, appInstance *app.App) {
 	factory := CurrentFactory()
 	a.Settings().SetTheme(factory.Theme())
 
-	w := a.NewWindow(i18n.T("Password_Manager"))
+	w := a.NewWindow(mainWindowTitle(appInstance))
 	configureWindow(w)
-	w.SetOnClosed(func() { a.Quit() })
+	w.SetOnClosed(func() {
+		// очистка стоит в очереди главного потока раньше выхода
+		utils.Clipboard.Clear()
+		fyne.Do(a.Quit)
+	})
 	w.Resize(factory.WindowSize())
 	w.CenterOnScreen()
 
-	// --- Автоблокировка при бездействии ---
-	const idleTimeout = 2 * time.Minute
-	var idleTimer *time.Timer
-
-	resetIdleTimer := func() {
-		if idleTimer != nil {
-			idleTimer.Stop()
-		}
-		idleTimer = time.AfterFunc(idleTimeout, func() {
-			fyne.Do(func() {
-
-				w.Hide()
-
-				LaunchWithUnlock(a)
-
-			})
-		})
-	}
-
-	canvas := w.Canvas()
-	canvas.SetOnTypedKey(func(ev *fyne.KeyEvent) { resetIdleTimer() })
-	canvas.SetOnTypedRune(func(r rune) { resetIdleTimer() })
-
-	// запускаем таймер при старте
-	resetIdleTimer()
+	// --- Автоблокировка по политике хранилища (см. autolock.go) ---
+	startAutoLock(a, appInstance)
+	// Локальная база утечек для оценки паролей (см. health.go)
+	loadBreachIndex()
 
 	// Загружаем список
 	passwords, err := appInstance.DB.GetAllPasswords()
@@ -67,10 +52,31 @@ func ShowMainWindow(a fyne.App, appInstance *app.App) {
 	statusLabel.Wrapping = fyne.TextTruncate
 	statusLabel.Alignment = fyne.TextAlignLeading
 
-	cryptoSvc := appInstance.Crypto
-
 	var table *widget.Table
-	table, tableContent := buildPasswordTable(currentList, statusLabel, w, cryptoSvc, appInstance.DB)
+	table, tableContent := buildPasswordTable(currentList, statusLabel, w, appInstance.DB)
+
+	// Поиск по мере ввода: нечёткий, по сервису, логину, ссылке, заметкам и тегам
+	searchEntry := widget.NewEntry()
+	searchEntry.SetPlaceHolder("🔍 " + i18n.T("Search_placeholder"))
+
+	// Дерево папок и выбор тега фильтруют список (см. folders.go)
+	showList := func(f model.PasswordFilter) {
+		f.Query = searchEntry.Text
+		newList, err := appInstance.DB.GetFilteredPasswords(f)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		*currentList = newList
+		table.Refresh()
+	}
+	folderPanel := newFolderSidebar(w, appInstance, showList)
+	// reload — после изменения записей: счётчики папок, новые папки и теги
+	reload := func() {
+		folderPanel.Reload()
+		showList(folderPanel.Filter())
+	}
+	searchEntry.OnChanged = func(string) { showList(folderPanel.Filter()) }
 
 	// Сохраняем ссылки на элементы, чтобы обновлять при смене языка
 	welcomeLabel := widget.NewLabel("🔐 " + i18n.T("Welcome_to_Manager"))
@@ -81,43 +87,82 @@ func ShowMainWindow(a fyne.App, appInstance *app.App) {
 	headerLabel.TextStyle = fyne.TextStyle{Bold: true}
 
 	addBtn := widget.NewButtonWithIcon(i18n.T("Add"), theme.ContentAddIcon(), func() {
-		ShowCreateForm(a, appInstance, func() {
-			newList, _ := appInstance.DB.GetAllPasswords()
-			*currentList = newList
-			table.Refresh()
-		})
+		ShowCreateForm(a, appInstance, reload)
 	})
 	updateBtn := widget.NewButtonWithIcon(i18n.T("Update"), theme.DocumentCreateIcon(), func() {
-		ShowUpdateWindow(a, appInstance, func() {
-			newList, _ := appInstance.DB.GetAllPasswords()
-			*currentList = newList
-			table.Refresh()
-		})
+		ShowUpdateWindow(a, appInstance, reload)
 	})
+	// Корзина: после восстановления обновляем основной список
+	trashContent, refreshTrash := buildTrashTab(w, appInstance, reload)
+
+	// Здоровье хранилища: проверка по кнопке, см. health.go
+	healthContent := buildHealthTab(w, appInstance)
+
 	deleteBtn := widget.NewButtonWithIcon(i18n.T("Delete"), theme.DeleteIcon(), func() {
 		ShowDeleteWindow(a, appInstance, func() {
-			newList, _ := appInstance.DB.GetAllPasswords()
-			*currentList = newList
-			table.Refresh()
+			reload()
+			refreshTrash()
 		})
 	})
 	filterBtn := widget.NewButtonWithIcon(i18n.T("Show_Filters"), theme.SearchIcon(), func() {
 		ShowFilterWindow(a, appInstance)
 	})
+	historyBtn := widget.NewButtonWithIcon(i18n.T("Password_history"), theme.HistoryIcon(), func() {
+		ShowHistoryWindow(a, appInstance, reload)
+	})
+	changeMasterBtn := widget.NewButtonWithIcon(i18n.T("Change_Master_Password"), theme.AccountIcon(), func() {
+		ShowChangeMasterWindow(a, appInstance)
+	})
+
+	// Шифрование всех полей записи, а не только пароля
+	sealCheck := widget.NewCheck(i18n.T("Encrypt_all_fields"), nil)
+	sealCheck.SetChecked(appInstance.DB.SealRecords())
+	var onSealChanged func(bool)
+	onSealChanged = func(enabled bool) {
+		if err := appInstance.DB.SetSealRecords(enabled); err != nil {
+			dialog.ShowError(err, w)
+			// откат галочки без повторного вызова обработчика
+			sealCheck.OnChanged = nil
+			sealCheck.SetChecked(!enabled)
+			sealCheck.OnChanged = onSealChanged
+			return
+		}
+		reload()
+	}
+	sealCheck.OnChanged = onSealChanged
 
-	mainContent := container.NewBorder(
-		container.NewVBox(welcomeLabel, headerLabel, widget.NewSeparator()),
+	// Меню «Хранилище»: экспорт/импорт; пересоздаётся при смене языка
+	onImport := func() {
+		reload()
+		refreshTrash()
+	}
+	// Смена хранилища (только десктоп): блокировка и экран выбора
+	var onSwitch func()
+	if !fyne.CurrentDevice().IsMobile() {
+		onSwitch = func() {
+			stopAutoLock()
+			appInstance.Lock()
+			replaceWindows(a, func() { LaunchWithUnlock(a) })
+			appInstance.DB.Close()
+		}
+	}
+	w.SetMainMenu(buildVaultMenu(w, appInstance, onImport, onSwitch))
+
+	passwordsContent := container.NewBorder(
+		container.NewVBox(welcomeLabel, headerLabel, searchEntry, widget.NewSeparator()),
 		nil, nil, nil,
 		tableContent,
 	)
 
 	if fyne.CurrentDevice().IsMobile() {
-		sidebarTop := container.NewVBox(addBtn, updateBtn, deleteBtn, filterBtn)
-		sidebarContent := container.NewBorder(nil, nil, nil, nil, sidebarTop)
+		sidebarTop := container.NewVBox(addBtn, updateBtn, deleteBtn, filterBtn, historyBtn, changeMasterBtn, widget.NewSeparator(), sealCheck, widget.NewSeparator())
+		sidebarContent := container.NewBorder(sidebarTop, nil, nil, nil, folderPanel.Content())
 
 		tabs := container.NewAppTabs(
 			container.NewTabItem(i18n.T("Menu"), sidebarContent),
-			container.NewTabItem(i18n.T("Passwords"), mainContent),
+			container.NewTabItem(i18n.T("Passwords"), passwordsContent),
+			container.NewTabItem(i18n.T("Trash"), trashContent),
+			container.NewTabItem(i18n.T("Health"), healthContent),
 		)
 		tabs.SetTabLocation(container.TabLocationBottom)
 
@@ -127,16 +172,25 @@ func ShowMainWindow(a fyne.App, appInstance *app.App) {
 				dialog.ShowError(err, w)
 				return
 			}
-			w.SetTitle(i18n.T("Password_Manager"))
+			w.SetTitle(mainWindowTitle(appInstance))
 			welcomeLabel.SetText("🔐 " + i18n.T("Welcome_to_Manager"))
 			headerLabel.SetText("🔑 " + i18n.T("Your_Passwords"))
 			addBtn.SetText(i18n.T("Add"))
 			updateBtn.SetText(i18n.T("Update"))
 			deleteBtn.SetText(i18n.T("Delete"))
 			filterBtn.SetText(i18n.T("Show_Filters"))
+			historyBtn.SetText(i18n.T("Password_history"))
+			changeMasterBtn.SetText(i18n.T("Change_Master_Password"))
+			sealCheck.Text = i18n.T("Encrypt_all_fields")
+			sealCheck.Refresh()
+			folderPanel.RefreshTexts()
+			searchEntry.SetPlaceHolder("🔍 " + i18n.T("Search_placeholder"))
 			tabs.Items[0].Text = i18n.T("Menu")
 			tabs.Items[1].Text = i18n.T("Passwords")
+			tabs.Items[2].Text = i18n.T("Trash")
+			tabs.Items[3].Text = i18n.T("Health")
 			tabs.Refresh()
+			w.SetMainMenu(buildVaultMenu(w, appInstance, onImport, onSwitch))
 		})
 		langSelect.SetSelected(i18n.CurrentLang())
 
@@ -145,11 +199,16 @@ func ShowMainWindow(a fyne.App, appInstance *app.App) {
 		langSelect := widget.NewSelect([]string{"en", "ru", "be"}, nil)
 		langSelect.SetSelected(i18n.CurrentLang())
 
-		sidebarTop := container.NewVBox(addBtn, updateBtn, deleteBtn, filterBtn)
-		sidebarBottom := container.NewVBox(widget.NewSeparator(), langSelect)
-		sidebarContent := container.NewBorder(nil, sidebarBottom, nil, nil, sidebarTop)
+		sidebarTop := container.NewVBox(addBtn, updateBtn, deleteBtn, filterBtn, historyBtn, changeMasterBtn, widget.NewSeparator())
+		sidebarBottom := container.NewVBox(widget.NewSeparator(), sealCheck, langSelect)
+		sidebarContent := container.NewBorder(sidebarTop, sidebarBottom, nil, nil, folderPanel.Content())
 
-		split := container.NewHSplit(sidebarContent, mainContent)
+		mainTabs := container.NewAppTabs(
+			container.NewTabItem(i18n.T("Passwords"), passwordsContent),
+			container.NewTabItem(i18n.T("Trash"), trashContent),
+			container.NewTabItem(i18n.T("Health"), healthContent),
+		)
+		split := container.NewHSplit(sidebarContent, mainTabs)
 		split.Offset = 0.2
 		w.SetContent(split)
 
@@ -159,20 +218,40 @@ func ShowMainWindow(a fyne.App, appInstance *app.App) {
 				dialog.ShowError(err, w)
 				return
 			}
-			w.SetTitle(i18n.T("Password_Manager"))
+			w.SetTitle(mainWindowTitle(appInstance))
 			welcomeLabel.SetText("🔐 " + i18n.T("Welcome_to_Manager"))
 			headerLabel.SetText("🔑 " + i18n.T("Your_Passwords"))
 			addBtn.SetText(i18n.T("Add"))
 			updateBtn.SetText(i18n.T("Update"))
 			deleteBtn.SetText(i18n.T("Delete"))
 			filterBtn.SetText(i18n.T("Show_Filters"))
+			historyBtn.SetText(i18n.T("Password_history"))
+			changeMasterBtn.SetText(i18n.T("Change_Master_Password"))
+			sealCheck.Text = i18n.T("Encrypt_all_fields")
+			sealCheck.Refresh()
+			folderPanel.RefreshTexts()
+			searchEntry.SetPlaceHolder("🔍 " + i18n.T("Search_placeholder"))
+			mainTabs.Items[0].Text = i18n.T("Passwords")
+			mainTabs.Items[1].Text = i18n.T("Trash")
+			mainTabs.Items[2].Text = i18n.T("Health")
+			mainTabs.Refresh()
 			table.Refresh()
+			w.SetMainMenu(buildVaultMenu(w, appInstance, onImport, onSwitch))
 			split.Refresh()
 		}
 	}
+	trackActivity(w)
 	w.Show()
 }
 
+// Имя хранилища в заголовке, чтобы не перепутать открытые базы
+func mainWindowTitle(appInstance *app.App) string {
+	if appInstance.Vault == "" {
+		return i18n.T("Password_Manager")
+	}
+	return i18n.T("Password_Manager") + " — " + appInstance.Vault
+}
+
 func clearStatusLater(label *widget.Label) {
 	go func() {
 		time.Sleep(3 * time.Second)
@@ -182,25 +261,44 @@ func clearStatusLater(label *widget.Label) {
 	}()
 }
 
+// requireLive: записи из корзины не копируются и не правятся — таблица
+// могла устареть, если запись удалили в другом окне или через REST
+func requireLive(storage db.Storage, id int, w fyne.Window) bool {
+	err := storage.RequireLive(id)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, db.ErrInTrash):
+		dialog.ShowInformation(i18n.T("Info"), i18n.T("Entry_in_trash"), w)
+	case errors.Is(err, sql.ErrNoRows):
+		dialog.ShowInformation(i18n.T("Info"), i18n.T("Entry_not_found"), w)
+	default:
+		dialog.ShowError(err, w)
+	}
+	return false
+}
+
 var tableColumns = []string{
 	i18n.T("ID"),
 	i18n.T("Service"),
 	i18n.T("Username"),
-	i18n.T("Category"),
+	i18n.T("Folder"),
+	i18n.T("Tags"),
 	i18n.T("Created_At"),
 	i18n.T("Link"),
+	i18n.T("TOTP"),
 	i18n.T("Password"),
+	i18n.T("Details"),
 }
 
 func buildPasswordTable(
 	currentList *[]model.PasswordListItem,
 	statusLabel *widget.Label,
 	w fyne.Window,
-	cryptoSvc *utils.CryptoService,
 	storage db.Storage,
 ) (*widget.Table, fyne.CanvasObject) {
 
-	columnWidths := []float32{60, 180, 180, 140, 160, 220, 120}
+	columnWidths := []float32{60, 180, 180, 140, 140, 160, 220, 130, 120, 100}
 	rowHeights := make(map[int]float32)
 
 	// объявляем table заранее
@@ -248,57 +346,74 @@ func buildPasswordTable(
 			case 3:
 				text = row.Category
 			case 4:
+				text = tagChips(row.Tags)
+			case 5:
 				if t, err := time.Parse(time.RFC3339, row.CreatedAt); err == nil {
 					text = t.Local().Format("02 Jan 2006, 15:04")
 				} else {
 					text = row.CreatedAt
 				}
-			case 5:
-				text = row.Link
 			case 6:
+				text = row.Link
+			case 7:
+				text = totpCellText(storage, row)
+			case 8:
 				text = i18n.T("Copy")
+			case 9:
+				if row.HasDetails {
+					text = "📝 " + i18n.T("Open")
+				}
 			}
 
 			label.SetText(text)
 			label.Alignment = fyne.TextAlignLeading
 			label.Show()
 
-			if cell.Col == 6 {
+			if cell.Col == 7 {
 				tap.onTap = func() {
-					sqlStore, ok := storage.(*db.SQLStorage)
-					if !ok {
-						dialog.ShowError(errors.New("storage backend does not support direct access"), w)
+					if !row.HasTOTP {
 						return
 					}
-
-					encB64, err := sqlStore.GetEncryptedPasswordByID(row.ID)
+					if !requireLive(storage, row.ID, w) {
+						return
+					}
+					t, err := storage.GetTOTP(row.ID)
 					if err != nil {
 						dialog.ShowError(err, w)
 						return
 					}
+					copySecret(storage, statusLabel, t.Code(time.Now()), "Code_copied")
+				}
+				return
+			}
 
-					if err := utils.CopyToClipboard(encB64, cryptoSvc); err != nil {
+			if cell.Col == 8 {
+				tap.onTap = func() {
+					if !requireLive(storage, row.ID, w) {
+						return
+					}
+					plain, err := storage.DecryptPassword(row.ID)
+					if err != nil {
 						dialog.ShowError(err, w)
 						return
 					}
 
-					statusLabel.SetText(i18n.T("Password_copied"))
-					clearStatusLater(statusLabel)
+					copySecret(storage, statusLabel, plain, "Password_copied")
+				}
+				return
+			}
 
-					go func() {
-						time.Sleep(15 * time.Second)
-						fyne.Do(func() {
-							fyne.CurrentApp().Clipboard().SetContent("")
-						})
-					}()
+			if cell.Col == 9 {
+				tap.onTap = func() {
+					ShowDetailsWindow(storage, row)
 				}
 				return
 			}
 
-			if cell.Col == 5 {
+			if cell.Col == 6 {
 				tap.onTap = func() {
 					if strings.TrimSpace(row.Link) != "" {
-						fyne.CurrentApp().Clipboard().SetContent(row.Link)
+						_ = utils.Clipboard.Copy(row.Link)
 						statusLabel.SetText(i18n.T("Link_copied"))
 						clearStatusLater(statusLabel)
 					}
@@ -332,6 +447,24 @@ func buildPasswordTable(
 		table.SetRowHeight(r, 32)
 	}
 
+	// Живые TOTP-коды: раз в секунду перерисовываем таблицу, пока окно открыто
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			open := true
+			fyne.DoAndWait(func() {
+				open = windowOpen(w)
+				if open && hasTOTP(*currentList) {
+					table.Refresh()
+				}
+			})
+			if !open {
+				return
+			}
+		}
+	}()
+
 	scroll := container.NewScroll(table)
 	scroll.SetMinSize(fyne.NewSize(w.Canvas().Size().Width, w.Canvas().Size().Height*0.6))
 
@@ -349,14 +482,23 @@ func ShowCreateForm(a fyne.App, appInstance *app.App, onSuccess func()) {
 	w.CenterOnScreen()
 
 	passwords, _ := appInstance.DB.GetAllPasswords()
-	services, usernames, categories, links := extractSuggestions(passwords)
+	services, usernames, links := extractSuggestions(passwords)
 
 	// Поля ввода
 	service := widget.NewSelectEntry(services)
 	username := widget.NewSelectEntry(usernames)
 	link := widget.NewSelectEntry(links)
-	category := widget.NewSelectEntry(categories)
+	// путь папки, недостающие папки создаются
+	category := widget.NewSelectEntry(folderPaths(appInstance))
+	category.SetPlaceHolder(i18n.T("Folder_placeholder"))
+	tagsEntry := widget.NewEntry()
+	tagsEntry.SetPlaceHolder(i18n.T("Tags_placeholder"))
 	passwordEntry := widget.NewPasswordEntry()
+	totpEntry := widget.NewPasswordEntry()
+	totpEntry.SetPlaceHolder(i18n.T("TOTP_placeholder"))
+	notesEntry := widget.NewMultiLineEntry()
+	notesEntry.SetMinRowsVisible(3)
+	fields := newFieldsEditor()
 	localStatus := widget.NewLabel("")
 
 	// Сила пароля
@@ -365,79 +507,13 @@ func ShowCreateForm(a fyne.App, appInstance *app.App, onSuccess func()) {
 	strengthLabel.Wrapping = fyne.TextWrapWord
 
 	passwordEntry.OnChanged = func(p string) {
-		missing := []string{}
-		if len(p) < 8 {
-			missing = append(missing, i18n.T("length_8"))
-		}
-		if !strings.ContainsAny(p, "0123456789") {
-			missing = append(missing, i18n.T("digit"))
-		}
-		if !strings.ContainsAny(p, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") {
-			missing = append(missing, i18n.T("uppercase"))
-		}
-		if !strings.ContainsAny(p, "abcdefghijklmnopqrstuvwxyz") {
-			missing = append(missing, i18n.T("lowercase"))
-		}
-		if !strings.ContainsAny(p, "!@#$%^&*()-_=+[]{}<>?/") {
-			missing = append(missing, i18n.T("symbol"))
-		}
-
-		if len(missing) > 0 {
-			strengthLabel.SetText("❌ " + i18n.T("Weak_password") + ": " + i18n.T("missing") + " " + strings.Join(missing, ", "))
-		} else {
-			if err := utils.ValidatePasswordStrength(p, 60); err != nil {
-				strengthLabel.SetText("❌ " + i18n.T("Weak_password") + ": " + err.Error())
-			} else {
-				strengthLabel.SetText("✅ " + i18n.T("Strong_password"))
-			}
-		}
-		strengthLabel.Refresh()
+		strengthLabel.SetText(strengthText(p, service.Text, username.Text, link.Text))
 	}
 
-	// Опции генерации
-	lengthEntry := widget.NewEntry()
-	lengthEntry.SetText("16")
-	excludeEntry := widget.NewEntry()
-
-	useUpper := widget.NewCheck("A-Z", nil)
-	useUpper.SetChecked(true)
-	useLower := widget.NewCheck("a-z", nil)
-	useLower.SetChecked(true)
-	useDigits := widget.NewCheck("0-9", nil)
-	useDigits.SetChecked(true)
-	useSymbols := widget.NewCheck("!@#", nil)
-	useSymbols.SetChecked(true)
-
+	// Генератор: символы по правилам или парольная фраза
+	genMode := newGeneratorMode()
 	generateBtn := widget.NewButtonWithIcon(i18n.T("Generate"), theme.ViewRefreshIcon(), func() {
-		length, err := strconv.Atoi(lengthEntry.Text)
-		if err != nil || length <= 0 {
-			localStatus.SetText(i18n.T("Invalid_length"))
-			clearStatusLater(localStatus)
-			return
-		}
-
-		var password string
-		for {
-			password, err = utils.GeneratePassword(length, useUpper.Checked, useLower.Checked, useDigits.Checked, useSymbols.Checked, excludeEntry.Text)
-			if err != nil {
-				localStatus.SetText(i18n.T("Generation_error") + ": " + err.Error())
-				clearStatusLater(localStatus)
-				return
-			}
-			// Проверяем, что пароль проходит все условия
-			if len(password) >= 8 &&
-				strings.ContainsAny(password, "0123456789") &&
-				strings.ContainsAny(password, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") &&
-				strings.ContainsAny(password, "abcdefghijklmnopqrstuvwxyz") &&
-				strings.ContainsAny(password, "!@#$%^&*()-_=+[]{}<>?/") {
-				break
-			}
-			// иначе генерируем заново
-		}
-
-		passwordEntry.SetText(password)
-		localStatus.SetText(i18n.T("Generated_inserted"))
-		clearStatusLater(localStatus)
+		genMode.Generate(passwordEntry, localStatus)
 	})
 	generateBtn.Importance = widget.MediumImportance
 
@@ -445,19 +521,18 @@ func ShowCreateForm(a fyne.App, appInstance *app.App, onSuccess func()) {
 		container.NewVBox(passwordEntry),
 		container.NewVBox(generateBtn),
 	)
-	optionsGrid := container.NewGridWithColumns(2,
-		container.NewVBox(widget.NewLabel(i18n.T("Length")), lengthEntry),
-		container.NewVBox(widget.NewLabel(i18n.T("Exclude")), excludeEntry),
-	)
-	checkboxGrid := container.NewGridWithColumns(4, useUpper, useLower, useDigits, useSymbols)
-	passwordSection := container.NewVBox(passwordRow, optionsGrid, checkboxGrid, strengthLabel)
+	passwordSection := container.NewVBox(passwordRow, genMode.Content(), strengthLabel)
 
 	form := container.NewVBox(
 		widget.NewLabelWithStyle("🔧 "+i18n.T("Service"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), service,
 		widget.NewLabelWithStyle("👤 "+i18n.T("Username"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), username,
 		widget.NewLabelWithStyle("🔗 "+i18n.T("Link"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), link,
-		widget.NewLabelWithStyle("📂 "+i18n.T("Category"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), category,
+		widget.NewLabelWithStyle("📂 "+i18n.T("Folder"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), category,
+		widget.NewLabelWithStyle("🏷 "+i18n.T("Tags"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), tagsEntry,
 		widget.NewLabelWithStyle("🔑 "+i18n.T("Password"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), passwordSection,
+		widget.NewLabelWithStyle("⏱ "+i18n.T("TOTP_secret"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), totpEntry,
+		widget.NewLabelWithStyle("📝 "+i18n.T("Notes"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), notesEntry,
+		widget.NewLabelWithStyle("🧩 "+i18n.T("Custom_fields"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), fields.Content(),
 	)
 
 	submitBtn := widget.NewButtonWithIcon(i18n.T("Save"), theme.ConfirmIcon(), func() {
@@ -467,15 +542,24 @@ func ShowCreateForm(a fyne.App, appInstance *app.App, onSuccess func()) {
 			Link:      link.Text,
 			Password:  passwordEntry.Text,
 			Category:  category.Text,
+			Tags:      parseTags(tagsEntry.Text),
 			CreatedAt: time.Now().Format(time.RFC3339),
 		}
-		encrypted, err := appInstance.Crypto.Encrypt(p.Password)
+		if secret := strings.TrimSpace(totpEntry.Text); secret != "" {
+			p.TOTPSecret = &secret
+		}
+		notes := notesEntry.Text
+		p.Notes, p.Fields = &notes, fields.Fields()
+		if st := security.EstimateStrength(p.Password, p.Service, p.Username, p.Link); st.Weak() {
+			dialog.ShowError(errors.New(i18n.T("Weak_password")+": "+hintText(st.MainHint())), w)
+			return
+		}
+		rules, err := genMode.Rules()
 		if err != nil {
 			dialog.ShowError(err, w)
 			return
 		}
-		p.Password = encrypted
-
+		p.Generator = rules
 		if _, _, err := appInstance.DB.CreatePassword(p); err != nil {
 			dialog.ShowError(err, w)
 			return
@@ -499,6 +583,7 @@ func ShowCreateForm(a fyne.App, appInstance *app.App, onSuccess func()) {
 	scroll := container.NewVScroll(content)
 	scroll.SetMinSize(factory.SmallWindowSize())
 	w.SetContent(container.NewPadded(scroll))
+	trackActivity(w)
 	w.Show()
 }
 
@@ -511,14 +596,23 @@ func ShowFilterWindow(a fyne.App, appInstance *app.App) {
 	w.CenterOnScreen()
 
 	passwords, _ := appInstance.DB.GetAllPasswords()
-	services, usernames, categories, _ := extractSuggestions(passwords)
+	services, usernames, _ := extractSuggestions(passwords)
 
 	service := widget.NewSelectEntry(services)
 	service.PlaceHolder = i18n.T("Any")
 	username := widget.NewSelectEntry(usernames)
 	username.PlaceHolder = i18n.T("Any")
-	category := widget.NewSelectEntry(categories)
+	// папка — вместе с подпапками
+	category := widget.NewSelectEntry(folderPaths(appInstance))
 	category.PlaceHolder = i18n.T("Any")
+	var tagNames []string
+	if tags, err := appInstance.DB.GetTags(); err == nil {
+		for _, t := range tags {
+			tagNames = append(tagNames, t.Name)
+		}
+	}
+	tag := widget.NewSelectEntry(tagNames)
+	tag.PlaceHolder = i18n.T("Any")
 
 	resultBox := container.NewVBox(widget.NewLabel(i18n.T("No_results_yet")))
 
@@ -527,12 +621,19 @@ func ShowFilterWindow(a fyne.App, appInstance *app.App) {
 		service,
 		widget.NewLabelWithStyle("👤 "+i18n.T("Username"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
 		username,
-		widget.NewLabelWithStyle("📂 "+i18n.T("Category"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle("📂 "+i18n.T("Folder"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
 		category,
+		widget.NewLabelWithStyle("🏷 "+i18n.T("Tag"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		tag,
 	)
 
 	filterBtn := widget.NewButton("🔍 "+i18n.T("Filter"), func() {
-		list, err := appInstance.DB.GetFilteredPasswords(service.Text, username.Text, category.Text)
+		list, err := appInstance.DB.GetFilteredPasswords(model.PasswordFilter{
+			Service:  service.Text,
+			Username: username.Text,
+			Folder:   category.Text,
+			Tag:      tag.Text,
+		})
 		if err != nil {
 			dialog.ShowError(err, w)
 			return
@@ -545,7 +646,7 @@ func ShowFilterWindow(a fyne.App, appInstance *app.App) {
 
 		statusLabel := widget.NewLabel("")
 		// используем единый CryptoService и DB
-		_, content := buildPasswordTable(&list, statusLabel, w, appInstance.Crypto, appInstance.DB)
+		_, content := buildPasswordTable(&list, statusLabel, w, appInstance.DB)
 
 		resultBox.Objects = []fyne.CanvasObject{
 			content,
@@ -568,6 +669,7 @@ func ShowFilterWindow(a fyne.App, appInstance *app.App) {
 	scroll.SetMinSize(factory.WindowSize())
 
 	w.SetContent(container.NewPadded(scroll))
+	trackActivity(w)
 	w.Show()
 }
 
@@ -580,89 +682,81 @@ func ShowUpdateWindow(a fyne.App, appInstance *app.App, onSuccess func()) {
 	w.CenterOnScreen()
 
 	passwords, _ := appInstance.DB.GetAllPasswords()
-	services, usernames, categories, links := extractSuggestions(passwords)
+	services, usernames, links := extractSuggestions(passwords)
 
 	// Поля ввода
 	idEntry := widget.NewEntry()
 	service := widget.NewSelectEntry(services)
 	username := widget.NewSelectEntry(usernames)
 	link := widget.NewSelectEntry(links)
-	category := widget.NewSelectEntry(categories)
+	category := widget.NewSelectEntry(folderPaths(appInstance))
+	category.SetPlaceHolder(i18n.T("Folder_placeholder"))
+	// теги меняются, только если запись загружена или поле заполнено
+	tagsEntry := widget.NewEntry()
+	tagsEntry.SetPlaceHolder(i18n.T("Tags_placeholder"))
+	tagsLoaded := false
 	passwordEntry := widget.NewPasswordEntry()
+	// пустое поле — секрет 2FA не меняется
+	totpEntry := widget.NewPasswordEntry()
+	totpEntry.SetPlaceHolder(i18n.T("TOTP_keep_placeholder"))
+	removeTOTP := widget.NewCheck(i18n.T("Remove_TOTP"), func(checked bool) {
+		if checked {
+			totpEntry.SetText("")
+			totpEntry.Disable()
+		} else {
+			totpEntry.Enable()
+		}
+	})
+	notesEntry := widget.NewMultiLineEntry()
+	notesEntry.SetMinRowsVisible(3)
+	fields := newFieldsEditor()
+	genMode := newGeneratorMode()
 	localStatus := widget.NewLabel("")
 
+	// Подгрузка записи по ID: метаданные, заметки, поля и правила генератора (пароль и 2FA не показываются)
+	loadBtn := widget.NewButtonWithIcon(i18n.T("Load"), theme.DownloadIcon(), func() {
+		idStr := strings.TrimSpace(idEntry.Text)
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			dialog.ShowInformation(i18n.T("Info"), i18n.T("Please_enter_ID"), w)
+			return
+		}
+		if !requireLive(appInstance.DB, id, w) {
+			return
+		}
+		entry, err := appInstance.DB.GetPasswordByID(idStr)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		details, err := appInstance.DB.GetDetails(id)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		service.SetText(entry.Service)
+		username.SetText(entry.Username)
+		link.SetText(entry.Link)
+		category.SetText(entry.Category)
+		tagsEntry.SetText(strings.Join(entry.Tags, ", "))
+		tagsLoaded = true
+		notesEntry.SetText(details.Notes)
+		fields.SetFields(details.Fields)
+		genMode.LoadRules(details.Generator)
+	})
+
 	// Сила пароля
 	strengthLabel := widget.NewLabel("")
 	strengthLabel.TextStyle = fyne.TextStyle{Bold: true}
 	strengthLabel.Wrapping = fyne.TextWrapWord
 
 	passwordEntry.OnChanged = func(p string) {
-		missing := []string{}
-		if len(p) < 8 {
-			missing = append(missing, i18n.T("length_8"))
-		}
-		if !strings.ContainsAny(p, "0123456789") {
-			missing = append(missing, i18n.T("digit"))
-		}
-		if !strings.ContainsAny(p, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") {
-			missing = append(missing, i18n.T("uppercase"))
-		}
-		if !strings.ContainsAny(p, "abcdefghijklmnopqrstuvwxyz") {
-			missing = append(missing, i18n.T("lowercase"))
-		}
-		if !strings.ContainsAny(p, "!@#$%^&*()-_=+[]{}<>?/") {
-			missing = append(missing, i18n.T("symbol"))
-		}
-
-		if len(missing) > 0 {
-			strengthLabel.SetText("❌ " + i18n.T("Weak_password") + ": " + i18n.T("missing") + " " + strings.Join(missing, ", "))
-		} else {
-			if err := utils.ValidatePasswordStrength(p, 60); err != nil {
-				strengthLabel.SetText("❌ " + i18n.T("Weak_password") + ": " + err.Error())
-			} else {
-				strengthLabel.SetText("✅ " + i18n.T("Strong_password"))
-			}
-		}
-		strengthLabel.Refresh()
+		strengthLabel.SetText(strengthText(p, service.Text, username.Text, link.Text))
 	}
 
-	// Генерация пароля — по умолчанию длина 16, все опции включены
-	lengthEntry := widget.NewEntry()
-	lengthEntry.SetText("16")
-	excludeEntry := widget.NewEntry()
-
-	useUpper := widget.NewCheck("A-Z", nil)
-	useUpper.SetChecked(true)
-	useLower := widget.NewCheck("a-z", nil)
-	useLower.SetChecked(true)
-	useDigits := widget.NewCheck("0-9", nil)
-	useDigits.SetChecked(true)
-	useSymbols := widget.NewCheck("!@#", nil)
-	useSymbols.SetChecked(true)
-
+	// Генератор — по умолчанию длина 16, все классы символов
 	generateBtn := widget.NewButtonWithIcon(i18n.T("Generate"), theme.ViewRefreshIcon(), func() {
-		length, err := strconv.Atoi(lengthEntry.Text)
-		if err != nil || length <= 0 {
-			localStatus.SetText(i18n.T("Invalid_length"))
-			clearStatusLater(localStatus)
-			return
-		}
-		password, err := utils.GeneratePassword(
-			length,
-			useUpper.Checked,
-			useLower.Checked,
-			useDigits.Checked,
-			useSymbols.Checked,
-			excludeEntry.Text,
-		)
-		if err != nil {
-			localStatus.SetText(i18n.T("Generation_error") + ": " + err.Error())
-			clearStatusLater(localStatus)
-			return
-		}
-		passwordEntry.SetText(password)
-		localStatus.SetText(i18n.T("Generated_inserted"))
-		clearStatusLater(localStatus)
+		genMode.Generate(passwordEntry, localStatus)
 	})
 	generateBtn.Importance = widget.MediumImportance
 
@@ -671,23 +765,21 @@ func ShowUpdateWindow(a fyne.App, appInstance *app.App, onSuccess func()) {
 		container.NewVBox(passwordEntry),
 		container.NewVBox(generateBtn),
 	)
-	optionsGrid := container.NewGridWithColumns(2,
-		container.NewVBox(widget.NewLabel(i18n.T("Length")), lengthEntry),
-		container.NewVBox(widget.NewLabel(i18n.T("Exclude")), excludeEntry),
-	)
-	checkboxGrid := container.NewGridWithColumns(4,
-		useUpper, useLower, useDigits, useSymbols,
-	)
-	passwordSection := container.NewVBox(passwordRow, optionsGrid, checkboxGrid, strengthLabel)
+	passwordSection := container.NewVBox(passwordRow, genMode.Content(), strengthLabel)
 
 	// Форма
 	form := container.NewVBox(
-		widget.NewLabelWithStyle("🆔 "+i18n.T("ID"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), idEntry,
+		widget.NewLabelWithStyle("🆔 "+i18n.T("ID"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		container.NewBorder(nil, nil, nil, loadBtn, idEntry),
 		widget.NewLabelWithStyle("🔧 "+i18n.T("Service"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), service,
 		widget.NewLabelWithStyle("👤 "+i18n.T("Username"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), username,
 		widget.NewLabelWithStyle("🔗 "+i18n.T("Link"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), link,
-		widget.NewLabelWithStyle("📂 "+i18n.T("Category"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), category,
+		widget.NewLabelWithStyle("📂 "+i18n.T("Folder"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), category,
+		widget.NewLabelWithStyle("🏷 "+i18n.T("Tags"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), tagsEntry,
 		widget.NewLabelWithStyle("🔑 "+i18n.T("Password"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), passwordSection,
+		widget.NewLabelWithStyle("⏱ "+i18n.T("TOTP_secret"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), totpEntry, removeTOTP,
+		widget.NewLabelWithStyle("📝 "+i18n.T("Notes"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), notesEntry,
+		widget.NewLabelWithStyle("🧩 "+i18n.T("Custom_fields"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), fields.Content(),
 	)
 
 	// Сабмит с onSuccess
@@ -705,14 +797,24 @@ func ShowUpdateWindow(a fyne.App, appInstance *app.App, onSuccess func()) {
 			Category:  category.Text,
 			CreatedAt: time.Now().Format(time.RFC3339),
 		}
-		encrypted, err := appInstance.Crypto.Encrypt(p.Password)
+		if secret := strings.TrimSpace(totpEntry.Text); secret != "" || removeTOTP.Checked {
+			p.TOTPSecret = &secret
+		}
+		if tagsLoaded || strings.TrimSpace(tagsEntry.Text) != "" {
+			p.Tags = parseTags(tagsEntry.Text)
+		}
+		notes := notesEntry.Text
+		p.Notes, p.Fields = &notes, fields.Fields()
+		rules, err := genMode.Rules()
 		if err != nil {
 			dialog.ShowError(err, w)
 			return
 		}
-		p.Password = encrypted
-
-		if err := appInstance.DB.UpdatePassword(idStr, p); err != nil {
+		p.Generator = rules
+		if err := appInstance.DB.UpdatePassword(idStr, p); errors.Is(err, db.ErrInTrash) {
+			dialog.ShowInformation(i18n.T("Info"), i18n.T("Entry_in_trash"), w)
+			return
+		} else if err != nil {
 			dialog.ShowError(err, w)
 			return
 		}
@@ -735,6 +837,7 @@ func ShowUpdateWindow(a fyne.App, appInstance *app.App, onSuccess func()) {
 	scroll := container.NewVScroll(content)
 	scroll.SetMinSize(factory.SmallWindowSize())
 	w.SetContent(container.NewPadded(scroll))
+	trackActivity(w)
 	w.Show()
 }
 
@@ -752,15 +855,27 @@ func ShowDeleteWindow(a fyne.App, appInstance *app.App, onSuccess func()) {
 			dialog.ShowInformation(i18n.T("Info"), i18n.T("Please_enter_ID"), w)
 			return
 		}
-		if err := appInstance.DB.DeletePassword(idStr); err != nil {
-			dialog.ShowError(err, w)
+		// Показываем, что именно удаляется, и просим подтверждения
+		entry, err := appInstance.DB.GetPasswordByID(idStr)
+		if err != nil || entry.DeletedAt != "" {
+			dialog.ShowInformation(i18n.T("Info"), i18n.T("Entry_not_found"), w)
 			return
 		}
-		dialog.ShowInformation(i18n.T("Success"), i18n.T("Deleted_successfully"), w)
-		if onSuccess != nil {
-			onSuccess()
-		}
-		w.Close()
+		message := i18n.T("Move_to_trash_confirm") + "\n#" + idStr + "  " + entry.Service + " — " + entry.Username
+		dialog.ShowConfirm(i18n.T("Delete"), message, func(ok bool) {
+			if !ok {
+				return
+			}
+			if err := appInstance.DB.DeletePassword(idStr); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			dialog.ShowInformation(i18n.T("Success"), i18n.T("Moved_to_trash"), w)
+			if onSuccess != nil {
+				onSuccess()
+			}
+			w.Close()
+		}, w)
 	})
 
 	content := container.NewVBox(
@@ -770,5 +885,71 @@ func ShowDeleteWindow(a fyne.App, appInstance *app.App, onSuccess func()) {
 	)
 
 	w.SetContent(content)
+	trackActivity(w)
+	w.Show()
+}
+
+func ShowChangeMasterWindow(a fyne.App, appInstance *app.App) {
+	factory := CurrentFactory()
+	a.Settings().SetTheme(factory.Theme())
+
+	w := a.NewWindow(i18n.T("Change_Master_Password"))
+	w.Resize(factory.SmallWindowSize())
+	w.CenterOnScreen()
+
+	oldEntry := widget.NewPasswordEntry()
+	oldEntry.SetPlaceHolder(i18n.T("Current_master_password"))
+	newEntry := widget.NewPasswordEntry()
+	newEntry.SetPlaceHolder(i18n.T("New_master_password"))
+	confirmEntry := widget.NewPasswordEntry()
+	confirmEntry.SetPlaceHolder(i18n.T("Confirm_master_password"))
+
+	saveBtn := widget.NewButtonWithIcon(i18n.T("Save"), theme.ConfirmIcon(), func() {
+		if oldEntry.Text == "" || newEntry.Text == "" {
+			dialog.ShowError(errors.New(i18n.T("Fill_all_fields")), w)
+			return
+		}
+		if newEntry.Text != confirmEntry.Text {
+			dialog.ShowError(errors.New(i18n.T("passwords_do_not_match")), w)
+			return
+		}
+
+		err := appInstance.ChangeMasterPassword(oldEntry.Text, newEntry.Text)
+		var decErr *db.DecryptError
+		switch {
+		case err == nil:
+			dialog.ShowInformation(i18n.T("Success"), i18n.T("Master_password_changed"), w)
+			w.Close()
+		case errors.Is(err, security.ErrInvalidMasterPassword):
+			dialog.ShowError(errors.New(i18n.T("invalid_master_password")), w)
+		case errors.As(err, &decErr):
+			ids := make([]string, len(decErr.IDs))
+			for i, id := range decErr.IDs {
+				ids[i] = strconv.Itoa(id)
+			}
+			dialog.ShowError(errors.New(i18n.T("Entries_failed_to_decrypt")+": "+strings.Join(ids, ", ")), w)
+		default:
+			dialog.ShowError(err, w)
+		}
+	})
+	saveBtn.Importance = widget.HighImportance
+
+	form := container.NewVBox(
+		widget.NewLabelWithStyle("🔑 "+i18n.T("Current_master_password"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), oldEntry,
+		widget.NewLabelWithStyle("🆕 "+i18n.T("New_master_password"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), newEntry,
+		widget.NewLabelWithStyle("✅ "+i18n.T("Confirm_master_password"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), confirmEntry,
+	)
+
+	content := container.NewVBox(
+		widget.NewLabelWithStyle("🔐 "+i18n.T("Change_Master_Password"), fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+		widget.NewSeparator(),
+		form,
+		widget.NewLabel(i18n.T("Remember_master_password_hint")),
+		widget.NewSeparator(),
+		saveBtn,
+	)
+
+	w.SetContent(container.NewPadded(content))
+	trackActivity(w)
 	w.Show()
 }
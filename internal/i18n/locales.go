@@ -8,15 +8,15 @@ import "fyne.io/fyne/v2"
 var resourceEnYaml = &fyne.StaticResource{
 	StaticName: "en.yaml",
 	StaticContent: []byte(
-		"Password_Manager: { other: \"Password Manager\" }\nUnlock_Password_Manager: { other: \"Unlock Password Manager\" }\nEnter_master_password: { other: \"Enter master password\" }\ninvalid_master_password: { other: \"Invalid master password\" }\nCreate_Master_Password: { other: \"Create Master Password\" }\nEnter_your_email: { other: \"Enter your email\" }\nConfirm_master_password: { other: \"Confirm master password\" }\nSave: { other: \"Save\" }\nSuccess: { other: \"Success\" }\nMaster_password_saved: { other: \"Master password saved\" }\npasswords_do_not_match: { other: \"Passwords do not match\" }\nemail_and_password_required: { other: \"Email and password are required\" }\n\nSession_locked: { other: \"Session locked due to inactivity\" }\nMaster_Password: { other: \"Master Password\" }\nUnlock_Session: { other: \"Unlock Session\" }\nUnlock: { other: \"Unlock\" }\nExit: { other: \"Exit\" }\n\nShow_Filters: { other: \"Filters\" }\nService: { other: \"Service\" }\nUsername: { other: \"Username\" }\nCategory: { other: \"Category\" }\nApply: { other: \"Apply\" }\nFilter_Passwords: { other: \"Filter Passwords\" }\nClose: { other: \"Close\" }\nRefresh: { other: \"Refresh\" }\n\nID: { other: \"ID\" }\nCreated_At: { other: \"Created At\" }\nLink: { other: \"Link\" }\nCopy: { other: \"Copy\" }\nLink_copied: { other: \"Link copied to clipboard\" }\nPassword_copied: { other: \"Password copied to clipboard\" }\n\nActions: { other: \"Actions\" }\nAdd: { other: \"Add\" }\nUpdate: { other: \"Update\" }\nDelete: { other: \"Delete\" }\nYour_Passwords: { other: \"Your Passwords\" }\n\nCreate_Password: { other: \"Create Password\" }\nLength: { other: \"Length\" }\nExclude: { other: \"Exclude\" }\nExclude_chars: { other: \"Exclude chars\" }\nGenerate: { other: \"Generate\" }\nInvalid_length: { other: \"Invalid length\" }\nGeneration_error: { other: \"Generation error\" }\nGenerated_inserted: { other: \"Generated password inserted\" }\n\nWeak_password: { other: \"Weak password\" }\nStrong_password: { other: \"Strong password\" }\nChoose_stronger: { other: \"Please choose a stronger password\" }\nmissing: { other: \"missing\" }\nlength_8: { other: \"length ≥ 8\" }\ndigit: { other: \"digit\" }\nuppercase: { other: \"uppercase\" }\nlowercase: { other: \"lowercase\" }\nsymbol: { other: \"symbol\" }\n\nUpdate_Password: { other: \"Update Password\" }\nUpdated: { other: \"Updated\" }\nPassword_updated: { other: \"Password updated successfully\" }\n\nDelete_Password: { other: \"Delete Password\" }\nEnter_ID_to_delete: { other: \"Enter ID to delete\" }\nDeleted: { other: \"Deleted\" }\nPassword_deleted: { other: \"Password deleted successfully\" }\n\nRemember_master_password_hint: { other: \"Remember your master password — it cannot be recovered if lost.\" }\nFirst-time_setup: { other: \"First-time setup\" }\nEmail: { other: \"Email\" }\nPassword: { other: \"Password\" }\nConfirm: { other: \"Confirm\" }\nLanguage: { other: \"Language\" }\nEnter_master_password_to_continue: { other: \"Enter master password to continue\" }\nWelcome_to_Manager: { other: \"Welcome to Password Manager\" }\nAny: { other: \"Any\" }\nNo_results_yet: { other: \"No results yet\" }\nLeave_fields_empty_for_all: { other: \"Leave fields empty to show all\" }\nNo_matching_entries: { other: \"No matching entries\" }"),
+		"Password_Manager: { other: \"Password Manager\" }\nUnlock_Password_Manager: { other: \"Unlock Password Manager\" }\nEnter_master_password: { other: \"Enter master password\" }\ninvalid_master_password: { other: \"Invalid master password\" }\nCreate_Master_Password: { other: \"Create Master Password\" }\nEnter_your_email: { other: \"Enter your email\" }\nConfirm_master_password: { other: \"Confirm master password\" }\nSave: { other: \"Save\" }\nSuccess: { other: \"Success\" }\nMaster_password_saved: { other: \"Master password saved\" }\npasswords_do_not_match: { other: \"Passwords do not match\" }\nemail_and_password_required: { other: \"Email and password are required\" }\n\nSession_locked: { other: \"Session locked due to inactivity\" }\nMaster_Password: { other: \"Master Password\" }\nUnlock_Session: { other: \"Unlock Session\" }\nUnlock: { other: \"Unlock\" }\nExit: { other: \"Exit\" }\n\nShow_Filters: { other: \"Filters\" }\nService: { other: \"Service\" }\nUsername: { other: \"Username\" }\nApply: { other: \"Apply\" }\nFilter_Passwords: { other: \"Filter Passwords\" }\nClose: { other: \"Close\" }\nRefresh: { other: \"Refresh\" }\n\nID: { other: \"ID\" }\nCreated_At: { other: \"Created At\" }\nLink: { other: \"Link\" }\nCopy: { other: \"Copy\" }\nLink_copied: { other: \"Link copied to clipboard\" }\nPassword_copied: { other: \"Password copied to clipboard\" }\n\nActions: { other: \"Actions\" }\nAdd: { other: \"Add\" }\nUpdate: { other: \"Update\" }\nDelete: { other: \"Delete\" }\nYour_Passwords: { other: \"Your Passwords\" }\n\nCreate_Password: { other: \"Create Password\" }\nLength: { other: \"Length\" }\nExclude: { other: \"Exclude\" }\nExclude_chars: { other: \"Exclude chars\" }\nGenerate: { other: \"Generate\" }\nInvalid_length: { other: \"Invalid length\" }\nGeneration_error: { other: \"Generation error\" }\nGenerated_inserted: { other: \"Generated password inserted\" }\n\nWeak_password: { other: \"Weak password\" }\nStrong_password: { other: \"Strong password\" }\nChoose_stronger: { other: \"Please choose a stronger password\" }\n\nUpdate_Password: { other: \"Update Password\" }\nUpdated: { other: \"Updated\" }\nPassword_updated: { other: \"Password updated successfully\" }\n\nDelete_Password: { other: \"Delete Password\" }\nEnter_ID_to_delete: { other: \"Enter ID to delete\" }\nDeleted: { other: \"Deleted\" }\nPassword_deleted: { other: \"Password deleted successfully\" }\n\nRemember_master_password_hint: { other: \"Remember your master password — it cannot be recovered if lost.\" }\nFirst-time_setup: { other: \"First-time setup\" }\nEmail: { other: \"Email\" }\nPassword: { other: \"Password\" }\nConfirm: { other: \"Confirm\" }\nLanguage: { other: \"Language\" }\nEnter_master_password_to_continue: { other: \"Enter master password to continue\" }\nWelcome_to_Manager: { other: \"Welcome to Password Manager\" }\nAny: { other: \"Any\" }\nNo_results_yet: { other: \"No results yet\" }\nLeave_fields_empty_for_all: { other: \"Leave fields empty to show all\" }\nNo_matching_entries: { other: \"No matching entries\" }\n\nChange_Master_Password: { other: \"Change master password\" }\nCurrent_master_password: { other: \"Current master password\" }\nNew_master_password: { other: \"New master password\" }\nMaster_password_changed: { other: \"Master password changed\" }\nEntries_failed_to_decrypt: { other: \"Master password not changed, entries failed to decrypt\" }\n\nFill_all_fields: { other: \"Please fill in all fields\" }\n\nEncrypt_all_fields: { other: \"Encrypt all fields\" }\n\nTOTP: { other: \"2FA code\" }\nTOTP_secret: { other: \"2FA secret (TOTP)\" }\nTOTP_placeholder: { other: \"otpauth:// URI or base32 secret (optional)\" }\nTOTP_keep_placeholder: { other: \"Leave empty to keep the current secret\" }\nRemove_TOTP: { other: \"Remove 2FA secret\" }\nCode_copied: { other: \"2FA code copied to clipboard\" }\n\nNotes: { other: \"Notes\" }\nCustom_fields: { other: \"Custom fields\" }\nAdd_field: { other: \"Add field\" }\nField_name: { other: \"Field name\" }\nDetails: { other: \"Details\" }\nOpen: { other: \"Open\" }\nLoad: { other: \"Load\" }\nNo_notes: { other: \"No notes\" }\nValue_copied: { other: \"Value copied to clipboard\" }\nHidden_value_copied: { other: \"Hidden value copied to clipboard\" }\n\nPassword_history: { other: \"Password history\" }\nNo_history: { other: \"No previous passwords\" }\nShow: { other: \"Show\" }\nRestore: { other: \"Restore\" }\nRestore_password_confirm: { other: \"Make this password current again? The current one will be kept in history.\" }\nPassword_restored: { other: \"Password restored\" }\n\nTrash: { other: \"Trash\" }\nRestored_from_trash: { other: \"Entry restored from trash\" }\nDelete_permanently: { other: \"Delete permanently\" }\nDelete_permanently_confirm: { other: \"Delete this entry permanently? This cannot be undone.\" }\nTrash_retention_days: { other: \"Keep deleted entries, days (0 = forever)\" }\nInvalid_retention: { other: \"Retention must be a non-negative number of days\" }\nSettings_saved: { other: \"Settings saved\" }\nEntry_not_found: { other: \"Entry not found\" }\nEntry_in_trash: { other: \"Entry is in the trash — restore it first\" }\nMove_to_trash_confirm: { other: \"Move this entry to the trash?\" }\nMoved_to_trash: { other: \"Moved to trash\" }\n\nVault_menu: { other: \"Vault\" }\nExport_vault: { other: \"Export…\" }\nImport_vault: { other: \"Import…\" }\nExport_passphrase: { other: \"Export passphrase\" }\nExport_passphrase_hint: { other: \"The file is encrypted with this passphrase; it is needed to import it.\" }\nConfirm_passphrase: { other: \"Confirm passphrase\" }\nPassphrase_required: { other: \"Passphrase must not be empty\" }\nPassphrases_mismatch: { other: \"Passphrases do not match\" }\nVault_exported: { other: \"Vault exported\" }\nEntries_imported: { other: \"Entries imported:\" }\nWrong_passphrase: { other: \"Wrong passphrase or corrupted file\" }\n\nCancel: { other: \"Cancel\" }\n\nImport_other: { other: \"Import from another manager…\" }\nImport_format: { other: \"Format\" }\nChoose_file: { other: \"Choose file…\" }\nNo_file_selected: { other: \"No file selected\" }\nKeePass_password: { other: \"KeePass master password\" }\nPreview: { other: \"Preview\" }\nFolder_mapping: { other: \"Folders → categories\" }\nSkip_duplicates: { other: \"Skip duplicates\" }\nDuplicate_of: { other: \"duplicate of #\" }\nImport_entries: { other: \"Import\" }\nNothing_to_import: { other: \"Nothing to import\" }\nImported_count: { other: \"Imported:\" }\nSkipped_count: { other: \"skipped:\" }\n\nExport_plain: { other: \"Export unencrypted (CSV/JSON)…\" }\nExport_plain_warning: { other: \"The file will contain every password in plain text. Anyone who gets it can read your whole vault. Delete it as soon as you no longer need it.\" }\nExport_plain_confirm: { other: \"I understand the file will NOT be encrypted\" }\nConfirm_risk_required: { other: \"Please confirm that you understand the risk\" }\nInvalid_master_password: { other: \"Invalid master password\" }\n\nNew_vault: { other: \"New vault\" }\nAdd_existing_vault: { other: \"Open existing vault file\" }\nVault_name: { other: \"Vault name\" }\nVault_file_exists: { other: \"A file for this vault name already exists; open it with \\\"Open existing vault file\\\"\" }\nSwitch_vault: { other: \"Switch vault…\" }\n\nAuto_lock: { other: \"Auto-lock…\" }\nLock_now: { other: \"Lock now\" }\nLock_idle_minutes: { other: \"Lock after inactivity, min\" }\nLock_max_session_minutes: { other: \"Maximum session length, min\" }\nLock_minutes_hint: { other: \"0 — never\" }\nLock_on_focus_loss: { other: \"Lock when the app loses focus\" }\nLock_on_focus_loss_hint: { other: \"Switching to another window counts too, not only minimizing\" }\nLock_on_suspend: { other: \"Lock when the system goes to sleep\" }\nLock_minutes_invalid: { other: \"Minutes must be a whole number from 0 to 10080\" }\n\nClipboard_settings: { other: \"Clipboard…\" }\nClipboard_clear_seconds: { other: \"Clear copied secrets after, s\" }\nClipboard_seconds_invalid: { other: \"Seconds must be a whole number from 5 to 600\" }\n\nCharacters: { other: \"Characters\" }\nPassphrase: { other: \"Passphrase\" }\nCapitalize_words: { other: \"Capitalize words\" }\nWord_count: { other: \"Words\" }\nSeparator: { other: \"Separator\" }\nDigits_to_insert: { other: \"Digits\" }\nPassphrase_options_invalid: { other: \"Word and digit counts must be numbers\" }\nPassphrase_inserted: { other: \"Generated passphrase inserted\" }\nbits: { other: \"bits\" }\n\nExclude_ambiguous: { other: \"No look-alikes\" }\nNo_repeat: { other: \"No repeats\" }\nPronounceable: { other: \"Pronounceable\" }\nSave_generator_rules: { other: \"Save these rules with the entry\" }\nMin_count: { other: \"min\" }\nMin_count_invalid: { other: \"Minimum counts must be numbers\" }\nSymbol_set: { other: \"Symbols to use\" }\n\nStrength_top_ten: { other: \"This is a top-10 common password\" }\nStrength_top_hundred: { other: \"This is a top-100 common password\" }\nStrength_very_common: { other: \"This is a very common password\" }\nStrength_similar_to_common: { other: \"This is similar to a commonly used password\" }\nStrength_word_by_itself: { other: \"A word by itself is easy to guess\" }\nStrength_names_by_themselves: { other: \"Names and surnames by themselves are easy to guess\" }\nStrength_common_names: { other: \"Common names and surnames are easy to guess\" }\nStrength_user_inputs: { other: \"This contains the service name or username\" }\nStrength_straight_rows: { other: \"Straight rows of keys are easy to guess\" }\nStrength_keyboard_pattern: { other: \"Short keyboard patterns are easy to guess\" }\nStrength_repeat_char: { other: \"Repeats like \\\"aaa\\\" are easy to guess\" }\nStrength_repeat_pattern: { other: \"Repeats like \\\"abcabcabc\\\" are only slightly harder to guess than \\\"abc\\\"\" }\nStrength_sequence: { other: \"Sequences like abc or 6543 are easy to guess\" }\nStrength_recent_years: { other: \"Recent years are easy to guess\" }\nStrength_dates: { other: \"Dates are often easy to guess\" }\nStrength_use_words: { other: \"Use a few words, avoid common phrases\" }\nStrength_no_need_for_symbols: { other: \"No need for symbols, digits, or uppercase letters\" }\nStrength_add_word: { other: \"Add another word or two. Uncommon words are better.\" }\nStrength_capitalization: { other: \"Capitalization doesn't help very much\" }\nStrength_all_uppercase: { other: \"All-uppercase is almost as easy to guess as all-lowercase\" }\nStrength_reversed_words: { other: \"Reversed words aren't much harder to guess\" }\nStrength_l33t: { other: \"Predictable substitutions like '@' instead of 'a' don't help very much\" }\nStrength_longer_keyboard: { other: \"Use a longer keyboard pattern with more turns\" }\nStrength_avoid_repeats: { other: \"Avoid repeated words and characters\" }\nStrength_avoid_sequences: { other: \"Avoid sequences\" }\nStrength_avoid_years: { other: \"Avoid recent years and years that are associated with you\" }\nStrength_avoid_dates: { other: \"Avoid dates and years that are associated with you\" }\nCrack_time: { other: \"Offline crack time\" }\nCrack_instant: { other: \"less than a second\" }\nCrack_centuries: { other: \"centuries\" }\nUnit_seconds: { other: \"s\" }\nUnit_minutes: { other: \"min\" }\nUnit_hours: { other: \"h\" }\nUnit_days: { other: \"d\" }\nUnit_months: { other: \"mo\" }\nUnit_years: { other: \"yr\" }\n\nHealth: { other: \"Health\" }\nHealth_hint: { other: \"Finds reused, weak and old passwords. All entries are decrypted in memory only.\" }\nCheck_health: { other: \"Check passwords\" }\nChecking: { other: \"Checking…\" }\nMin_score: { other: \"Weak below score (0–4)\" }\nMax_age_days: { other: \"Old after, days (0 — off)\" }\nInvalid_max_age: { other: \"Enter the number of days from 0 to 36500\" }\nEntries_checked: { other: \"Entries checked\" }\nIssues_found: { other: \"With issues\" }\nNo_issues: { other: \"No issues found\" }\nUndecryptable_entries: { other: \"Failed to decrypt\" }\nReused_passwords: { other: \"Reused passwords\" }\nWeak_passwords: { other: \"Weak passwords\" }\nOld_passwords: { other: \"Old passwords\" }\ndays: { other: \"days\" }\n\nFound_in_breaches: { other: \"Times seen in data breaches\" }\nBreached_passwords: { other: \"Found in breaches\" }\nBreach_check_off: { other: \"Breach check is off: choose a local Pwned Passwords file\" }\nBreach_index: { other: \"Pwned Passwords SHA-1 file or range folder (offline)\" }\nBreach_index_placeholder: { other: \"Path; empty — check is off\" }\nBreach_index_invalid: { other: \"Cannot use this breach file\" }\n\nFolder: { other: \"Folder\" }\nFolders: { other: \"Folders\" }\nTags: { other: \"Tags\" }\nTag: { other: \"Tag\" }\nAll_entries: { other: \"All entries\" }\nNew_folder: { other: \"New folder\" }\nRename: { other: \"Rename\" }\nMerge: { other: \"Merge\" }\nMerge_into: { other: \"Merge into\" }\nDelete_folder_confirm: { other: \"Delete this folder? Its entries and subfolders move to the parent folder.\" }\nDelete_tag_confirm: { other: \"Remove this tag from all entries?\" }\nManage_tags: { other: \"Manage tags\" }\nAny_tag: { other: \"Any tag\" }\nFolder_name: { other: \"Folder name\" }\nTag_name: { other: \"Tag name\" }\nTop_level: { other: \"Top level\" }\nSelect_folder: { other: \"Select a folder first\" }\nSelect_tag: { other: \"Select a tag first\" }\nFolder_placeholder: { other: \"Work/Clients — missing folders are created\" }\nTags_placeholder: { other: \"work, urgent\" }\nInfo: { other: \"Info\" }\n\nSearch_placeholder: { other: \"Search service, login, link, notes or tags\" }"),
 }
 var resourceRuYaml = &fyne.StaticResource{
 	StaticName: "ru.yaml",
 	StaticContent: []byte(
-		"Password_Manager: { other: \"Менеджер паролей\" }\nUnlock_Password_Manager: { other: \"Разблокировать менеджер паролей\" }\nEnter_master_password: { other: \"Введите мастер-пароль\" }\ninvalid_master_password: { other: \"Неверный мастер-пароль\" }\nCreate_Master_Password: { other: \"Создать мастер-пароль\" }\nEnter_your_email: { other: \"Введите ваш email\" }\nConfirm_master_password: { other: \"Подтвердите мастер-пароль\" }\nSave: { other: \"Сохранить\" }\nSuccess: { other: \"Успех\" }\nMaster_password_saved: { other: \"Мастер-пароль сохранён\" }\npasswords_do_not_match: { other: \"Пароли не совпадают\" }\nemail_and_password_required: { other: \"Требуются email и пароль\" }\n\nSession_locked: { other: \"Сессия заблокирована из-за бездействия\" }\nMaster_Password: { other: \"Мастер-пароль\" }\nUnlock_Session: { other: \"Разблокировать сессию\" }\nUnlock: { other: \"Разблокировать\" }\nExit: { other: \"Выход\" }\n\nShow_Filters: { other: \"Фильтры\" }\nService: { other: \"Сервис\" }\nUsername: { other: \"Имя пользователя\" }\nCategory: { other: \"Категория\" }\nApply: { other: \"Применить\" }\nFilter_Passwords: { other: \"Фильтр паролей\" }\nClose: { other: \"Закрыть\" }\nRefresh: { other: \"Обновить\" }\n\nID: { other: \"ID\" }\nCreated_At: { other: \"Создано\" }\nLink: { other: \"Ссылка\" }\nCopy: { other: \"Копировать\" }\nLink_copied: { other: \"Ссылка скопирована в буфер обмена\" }\nPassword_copied: { other: \"Пароль скопирован в буфер обмена\" }\n\nActions: { other: \"Действия\" }\nAdd: { other: \"Добавить\" }\nUpdate: { other: \"Обновить\" }\nDelete: { other: \"Удалить\" }\nYour_Passwords: { other: \"Ваши пароли\" }\n\nCreate_Password: { other: \"Создать пароль\" }\nLength: { other: \"Длина\" }\nExclude: { other: \"Исключить\" }\nExclude_chars: { other: \"Исключить символы\" }\nGenerate: { other: \"Сгенерировать\" }\nInvalid_length: { other: \"Некорректная длина\" }\nGeneration_error: { other: \"Ошибка генерации\" }\nGenerated_inserted: { other: \"Сгенерированный пароль вставлен\" }\n\nWeak_password: { other: \"Слабый пароль\" }\nStrong_password: { other: \"Надёжный пароль\" }\nChoose_stronger: { other: \"Пожалуйста, выберите более надёжный пароль\" }\nmissing: { other: \"отсутствует\" }\nlength_8: { other: \"длина ≥ 8\" }\ndigit: { other: \"цифра\" }\nuppercase: { other: \"прописная буква\" }\nlowercase: { other: \"строчная буква\" }\nsymbol: { other: \"символ\" }\n\nUpdate_Password: { other: \"Обновить пароль\" }\nUpdated: { other: \"Обновлено\" }\nPassword_updated: { other: \"Пароль успешно обновлён\" }\n\nDelete_Password: { other: \"Удалить пароль\" }\nEnter_ID_to_delete: { other: \"Введите ID для удаления\" }\nDeleted: { other: \"Удалено\" }\nPassword_deleted: { other: \"Пароль успешно удалён\" }\n\nRemember_master_password_hint: { other: \"Запомните мастер‑пароль — восстановить его в случае утери невозможно.\" }\nFirst-time_setup: { other: \"Первоначальная настройка\" }\nEmail: { other: \"Электронная почта\" }\nPassword: { other: \"Пароль\" }\nConfirm: { other: \"Подтверждение\" }\nLanguage: { other: \"Язык\" }\nEnter_master_password_to_continue: { other: \"Введите мастер‑пароль для продолжения\" }\nWelcome_to_Manager: { other: \"Добро пожаловать в менеджер паролей\" }\nAny: { other: \"Любой\" }\nNo_results_yet: { other: \"Пока нет результатов\" }\nLeave_fields_empty_for_all: { other: \"Оставьте поля пустыми, чтобы показать все\" }\nNo_matching_entries: { other: \"Нет совпадающих записей\" }"),
+		"Password_Manager: { other: \"Менеджер паролей\" }\nUnlock_Password_Manager: { other: \"Разблокировать менеджер паролей\" }\nEnter_master_password: { other: \"Введите мастер-пароль\" }\ninvalid_master_password: { other: \"Неверный мастер-пароль\" }\nCreate_Master_Password: { other: \"Создать мастер-пароль\" }\nEnter_your_email: { other: \"Введите ваш email\" }\nConfirm_master_password: { other: \"Подтвердите мастер-пароль\" }\nSave: { other: \"Сохранить\" }\nSuccess: { other: \"Успех\" }\nMaster_password_saved: { other: \"Мастер-пароль сохранён\" }\npasswords_do_not_match: { other: \"Пароли не совпадают\" }\nemail_and_password_required: { other: \"Требуются email и пароль\" }\n\nSession_locked: { other: \"Сессия заблокирована из-за бездействия\" }\nMaster_Password: { other: \"Мастер-пароль\" }\nUnlock_Session: { other: \"Разблокировать сессию\" }\nUnlock: { other: \"Разблокировать\" }\nExit: { other: \"Выход\" }\n\nShow_Filters: { other: \"Фильтры\" }\nService: { other: \"Сервис\" }\nUsername: { other: \"Имя пользователя\" }\nApply: { other: \"Применить\" }\nFilter_Passwords: { other: \"Фильтр паролей\" }\nClose: { other: \"Закрыть\" }\nRefresh: { other: \"Обновить\" }\n\nID: { other: \"ID\" }\nCreated_At: { other: \"Создано\" }\nLink: { other: \"Ссылка\" }\nCopy: { other: \"Копировать\" }\nLink_copied: { other: \"Ссылка скопирована в буфер обмена\" }\nPassword_copied: { other: \"Пароль скопирован в буфер обмена\" }\n\nActions: { other: \"Действия\" }\nAdd: { other: \"Добавить\" }\nUpdate: { other: \"Обновить\" }\nDelete: { other: \"Удалить\" }\nYour_Passwords: { other: \"Ваши пароли\" }\n\nCreate_Password: { other: \"Создать пароль\" }\nLength: { other: \"Длина\" }\nExclude: { other: \"Исключить\" }\nExclude_chars: { other: \"Исключить символы\" }\nGenerate: { other: \"Сгенерировать\" }\nInvalid_length: { other: \"Некорректная длина\" }\nGeneration_error: { other: \"Ошибка генерации\" }\nGenerated_inserted: { other: \"Сгенерированный пароль вставлен\" }\n\nWeak_password: { other: \"Слабый пароль\" }\nStrong_password: { other: \"Надёжный пароль\" }\nChoose_stronger: { other: \"Пожалуйста, выберите более надёжный пароль\" }\n\nUpdate_Password: { other: \"Обновить пароль\" }\nUpdated: { other: \"Обновлено\" }\nPassword_updated: { other: \"Пароль успешно обновлён\" }\n\nDelete_Password: { other: \"Удалить пароль\" }\nEnter_ID_to_delete: { other: \"Введите ID для удаления\" }\nDeleted: { other: \"Удалено\" }\nPassword_deleted: { other: \"Пароль успешно удалён\" }\n\nRemember_master_password_hint: { other: \"Запомните мастер‑пароль — восстановить его в случае утери невозможно.\" }\nFirst-time_setup: { other: \"Первоначальная настройка\" }\nEmail: { other: \"Электронная почта\" }\nPassword: { other: \"Пароль\" }\nConfirm: { other: \"Подтверждение\" }\nLanguage: { other: \"Язык\" }\nEnter_master_password_to_continue: { other: \"Введите мастер‑пароль для продолжения\" }\nWelcome_to_Manager: { other: \"Добро пожаловать в менеджер паролей\" }\nAny: { other: \"Любой\" }\nNo_results_yet: { other: \"Пока нет результатов\" }\nLeave_fields_empty_for_all: { other: \"Оставьте поля пустыми, чтобы показать все\" }\nNo_matching_entries: { other: \"Нет совпадающих записей\" }\n\nChange_Master_Password: { other: \"Сменить мастер-пароль\" }\nCurrent_master_password: { other: \"Текущий мастер-пароль\" }\nNew_master_password: { other: \"Новый мастер-пароль\" }\nMaster_password_changed: { other: \"Мастер-пароль изменён\" }\nEntries_failed_to_decrypt: { other: \"Мастер-пароль не изменён, не удалось расшифровать записи\" }\n\nFill_all_fields: { other: \"Заполните все поля\" }\n\nEncrypt_all_fields: { other: \"Шифровать все поля\" }\n\nTOTP: { other: \"Код 2FA\" }\nTOTP_secret: { other: \"Секрет 2FA (TOTP)\" }\nTOTP_placeholder: { other: \"otpauth:// URI или base32-секрет (необязательно)\" }\nTOTP_keep_placeholder: { other: \"Оставьте пустым, чтобы не менять секрет\" }\nRemove_TOTP: { other: \"Удалить секрет 2FA\" }\nCode_copied: { other: \"Код 2FA скопирован в буфер обмена\" }\n\nNotes: { other: \"Заметки\" }\nCustom_fields: { other: \"Дополнительные поля\" }\nAdd_field: { other: \"Добавить поле\" }\nField_name: { other: \"Название поля\" }\nDetails: { other: \"Подробности\" }\nOpen: { other: \"Открыть\" }\nLoad: { other: \"Загрузить\" }\nNo_notes: { other: \"Нет заметок\" }\nValue_copied: { other: \"Значение скопировано в буфер обмена\" }\nHidden_value_copied: { other: \"Скрытое значение скопировано в буфер обмена\" }\n\nPassword_history: { other: \"История паролей\" }\nNo_history: { other: \"Прежних паролей нет\" }\nShow: { other: \"Показать\" }\nRestore: { other: \"Восстановить\" }\nRestore_password_confirm: { other: \"Сделать этот пароль текущим? Текущий сохранится в истории.\" }\nPassword_restored: { other: \"Пароль восстановлен\" }\n\nTrash: { other: \"Корзина\" }\nRestored_from_trash: { other: \"Запись восстановлена из корзины\" }\nDelete_permanently: { other: \"Удалить навсегда\" }\nDelete_permanently_confirm: { other: \"Удалить запись навсегда? Это нельзя отменить.\" }\nTrash_retention_days: { other: \"Хранить удалённые записи, дней (0 = всегда)\" }\nInvalid_retention: { other: \"Срок хранения должен быть неотрицательным числом дней\" }\nSettings_saved: { other: \"Настройки сохранены\" }\nEntry_not_found: { other: \"Запись не найдена\" }\nEntry_in_trash: { other: \"Запись в корзине — сначала восстановите её\" }\nMove_to_trash_confirm: { other: \"Переместить запись в корзину?\" }\nMoved_to_trash: { other: \"Перемещено в корзину\" }\n\nVault_menu: { other: \"Хранилище\" }\nExport_vault: { other: \"Экспорт…\" }\nImport_vault: { other: \"Импорт…\" }\nExport_passphrase: { other: \"Пароль экспорта\" }\nExport_passphrase_hint: { other: \"Файл шифруется этим паролем; он понадобится для импорта.\" }\nConfirm_passphrase: { other: \"Повторите пароль\" }\nPassphrase_required: { other: \"Пароль не может быть пустым\" }\nPassphrases_mismatch: { other: \"Пароли не совпадают\" }\nVault_exported: { other: \"Хранилище экспортировано\" }\nEntries_imported: { other: \"Импортировано записей:\" }\nWrong_passphrase: { other: \"Неверный пароль или файл повреждён\" }\n\nCancel: { other: \"Отмена\" }\n\nImport_other: { other: \"Импорт из другого менеджера…\" }\nImport_format: { other: \"Формат\" }\nChoose_file: { other: \"Выбрать файл…\" }\nNo_file_selected: { other: \"Файл не выбран\" }\nKeePass_password: { other: \"Мастер-пароль KeePass\" }\nPreview: { other: \"Предпросмотр\" }\nFolder_mapping: { other: \"Папки → категории\" }\nSkip_duplicates: { other: \"Пропускать дубликаты\" }\nDuplicate_of: { other: \"дубликат #\" }\nImport_entries: { other: \"Импортировать\" }\nNothing_to_import: { other: \"Нечего импортировать\" }\nImported_count: { other: \"Импортировано:\" }\nSkipped_count: { other: \"пропущено:\" }\n\nExport_plain: { other: \"Экспорт без шифрования (CSV/JSON)…\" }\nExport_plain_warning: { other: \"Файл будет содержать все пароли в открытом виде. Любой, кто его получит, прочитает всё хранилище. Удалите его, как только он станет не нужен.\" }\nExport_plain_confirm: { other: \"Я понимаю, что файл НЕ будет зашифрован\" }\nConfirm_risk_required: { other: \"Подтвердите, что понимаете риск\" }\nInvalid_master_password: { other: \"Неверный мастер-пароль\" }\n\nNew_vault: { other: \"Новое хранилище\" }\nAdd_existing_vault: { other: \"Открыть файл хранилища\" }\nVault_name: { other: \"Имя хранилища\" }\nVault_file_exists: { other: \"Файл для хранилища с таким именем уже есть — откройте его через «Открыть файл хранилища»\" }\nSwitch_vault: { other: \"Сменить хранилище…\" }\n\nAuto_lock: { other: \"Автоблокировка…\" }\nLock_now: { other: \"Заблокировать\" }\nLock_idle_minutes: { other: \"Блокировать после бездействия, мин\" }\nLock_max_session_minutes: { other: \"Максимальная длина сессии, мин\" }\nLock_minutes_hint: { other: \"0 — никогда\" }\nLock_on_focus_loss: { other: \"Блокировать при потере фокуса\" }\nLock_on_focus_loss_hint: { other: \"Срабатывает и при переключении на другое окно, а не только при сворачивании\" }\nLock_on_suspend: { other: \"Блокировать при переходе системы в сон\" }\nLock_minutes_invalid: { other: \"Минуты — целое число от 0 до 10080\" }\n\nClipboard_settings: { other: \"Буфер обмена…\" }\nClipboard_clear_seconds: { other: \"Очищать скопированное через, с\" }\nClipboard_seconds_invalid: { other: \"Секунды — целое число от 5 до 600\" }\n\nCharacters: { other: \"Символы\" }\nPassphrase: { other: \"Парольная фраза\" }\nCapitalize_words: { other: \"Слова с заглавной буквы\" }\nWord_count: { other: \"Слов\" }\nSeparator: { other: \"Разделитель\" }\nDigits_to_insert: { other: \"Цифр\" }\nPassphrase_options_invalid: { other: \"Количество слов и цифр должно быть числом\" }\nPassphrase_inserted: { other: \"Сгенерированная фраза вставлена\" }\nbits: { other: \"бит\" }\n\nExclude_ambiguous: { other: \"Без похожих\" }\nNo_repeat: { other: \"Без повторов\" }\nPronounceable: { other: \"Произносимый\" }\nSave_generator_rules: { other: \"Сохранить правила в записи\" }\nMin_count: { other: \"мин.\" }\nMin_count_invalid: { other: \"Минимумы должны быть числами\" }\nSymbol_set: { other: \"Набор символов\" }\n\nStrength_top_ten: { other: \"Это один из 10 самых частых паролей\" }\nStrength_top_hundred: { other: \"Это один из 100 самых частых паролей\" }\nStrength_very_common: { other: \"Это очень распространённый пароль\" }\nStrength_similar_to_common: { other: \"Похоже на распространённый пароль\" }\nStrength_word_by_itself: { other: \"Одно слово легко угадать\" }\nStrength_names_by_themselves: { other: \"Имена и фамилии сами по себе легко угадать\" }\nStrength_common_names: { other: \"Распространённые имена и фамилии легко угадать\" }\nStrength_user_inputs: { other: \"Пароль содержит название сервиса или логин\" }\nStrength_straight_rows: { other: \"Ряды клавиш подряд легко угадать\" }\nStrength_keyboard_pattern: { other: \"Короткие клавиатурные узоры легко угадать\" }\nStrength_repeat_char: { other: \"Повторы вида «aaa» легко угадать\" }\nStrength_repeat_pattern: { other: \"Повторы вида «abcabcabc» ненамного сложнее, чем «abc»\" }\nStrength_sequence: { other: \"Последовательности вроде abc или 6543 легко угадать\" }\nStrength_recent_years: { other: \"Недавние годы легко угадать\" }\nStrength_dates: { other: \"Даты часто легко угадать\" }\nStrength_use_words: { other: \"Используйте несколько слов, но не расхожие фразы\" }\nStrength_no_need_for_symbols: { other: \"Символы, цифры и заглавные буквы не обязательны\" }\nStrength_add_word: { other: \"Добавьте ещё слово-другое, лучше редкие\" }\nStrength_capitalization: { other: \"Заглавная буква почти не помогает\" }\nStrength_all_uppercase: { other: \"Всё заглавными угадать почти так же легко, как строчными\" }\nStrength_reversed_words: { other: \"Слова задом наперёд ненамного сложнее угадать\" }\nStrength_l33t: { other: \"Предсказуемые замены вроде «@» вместо «a» почти не помогают\" }\nStrength_longer_keyboard: { other: \"Используйте более длинный узор с поворотами\" }\nStrength_avoid_repeats: { other: \"Избегайте повторов слов и символов\" }\nStrength_avoid_sequences: { other: \"Избегайте последовательностей\" }\nStrength_avoid_years: { other: \"Избегайте недавних и памятных вам годов\" }\nStrength_avoid_dates: { other: \"Избегайте памятных вам дат и годов\" }\nCrack_time: { other: \"Время подбора офлайн\" }\nCrack_instant: { other: \"меньше секунды\" }\nCrack_centuries: { other: \"века\" }\nUnit_seconds: { other: \"с\" }\nUnit_minutes: { other: \"мин\" }\nUnit_hours: { other: \"ч\" }\nUnit_days: { other: \"дн.\" }\nUnit_months: { other: \"мес.\" }\nUnit_years: { other: \"г.\" }\n\nHealth: { other: \"Здоровье\" }\nHealth_hint: { other: \"Находит повторяющиеся, слабые и старые пароли. Записи расшифровываются только в памяти.\" }\nCheck_health: { other: \"Проверить пароли\" }\nChecking: { other: \"Проверка…\" }\nMin_score: { other: \"Слабый, если оценка ниже (0–4)\" }\nMax_age_days: { other: \"Старый через, дней (0 — выкл.)\" }\nInvalid_max_age: { other: \"Введите число дней от 0 до 36500\" }\nEntries_checked: { other: \"Проверено записей\" }\nIssues_found: { other: \"С проблемами\" }\nNo_issues: { other: \"Проблем не найдено\" }\nUndecryptable_entries: { other: \"Не удалось расшифровать\" }\nReused_passwords: { other: \"Повторяющиеся пароли\" }\nWeak_passwords: { other: \"Слабые пароли\" }\nOld_passwords: { other: \"Старые пароли\" }\ndays: { other: \"дн.\" }\n\nFound_in_breaches: { other: \"Встречался в утечках, раз\" }\nBreached_passwords: { other: \"Найдены в утечках\" }\nBreach_check_off: { other: \"Проверка по утечкам выключена: укажите локальный файл Pwned Passwords\" }\nBreach_index: { other: \"Файл SHA-1 Pwned Passwords или папка диапазонов (офлайн)\" }\nBreach_index_placeholder: { other: \"Путь; пусто — проверка выключена\" }\nBreach_index_invalid: { other: \"Этот файл утечек не подходит\" }\n\nFolder: { other: \"Папка\" }\nFolders: { other: \"Папки\" }\nTags: { other: \"Теги\" }\nTag: { other: \"Тег\" }\nAll_entries: { other: \"Все записи\" }\nNew_folder: { other: \"Новая папка\" }\nRename: { other: \"Переименовать\" }\nMerge: { other: \"Объединить\" }\nMerge_into: { other: \"Объединить с\" }\nDelete_folder_confirm: { other: \"Удалить папку? Её записи и подпапки перейдут в родительскую папку.\" }\nDelete_tag_confirm: { other: \"Снять этот тег со всех записей?\" }\nManage_tags: { other: \"Управление тегами\" }\nAny_tag: { other: \"Любой тег\" }\nFolder_name: { other: \"Имя папки\" }\nTag_name: { other: \"Имя тега\" }\nTop_level: { other: \"Верхний уровень\" }\nSelect_folder: { other: \"Сначала выберите папку\" }\nSelect_tag: { other: \"Сначала выберите тег\" }\nFolder_placeholder: { other: \"Работа/Клиенты — недостающие папки будут созданы\" }\nTags_placeholder: { other: \"работа, срочно\" }\nInfo: { other: \"Информация\" }\n\nSearch_placeholder: { other: \"Поиск по сервису, логину, ссылке, заметкам и тегам\" }"),
 }
 var resourceBeYaml = &fyne.StaticResource{
 	StaticName: "be.yaml",
 	StaticContent: []byte(
-		"Password_Manager: { other: \"Менеджар пароляў\" }\nUnlock_Password_Manager: { other: \"Разблакіраваць менеджар пароляў\" }\nEnter_master_password: { other: \"Увядзіце майстар-пароль\" }\ninvalid_master_password: { other: \"Няправільны майстар-пароль\" }\nCreate_Master_Password: { other: \"Стварыць майстар-пароль\" }\nEnter_your_email: { other: \"Увядзіце ваш email\" }\nConfirm_master_password: { other: \"Пацвердзіце майстар-пароль\" }\nSave: { other: \"Захаваць\" }\nSuccess: { other: \"Поспех\" }\nMaster_password_saved: { other: \"Майстар-пароль захаваны\" }\npasswords_do_not_match: { other: \"Паролі не супадаюць\" }\nemail_and_password_required: { other: \"Патрабуюцца email і пароль\" }\n\nSession_locked: { other: \"Сесія заблакіравана праз бяздзейнасць\" }\nMaster_Password: { other: \"Майстар-пароль\" }\nUnlock_Session: { other: \"Разблакіраваць сесію\" }\nUnlock: { other: \"Разблакіраваць\" }\nExit: { other: \"Выйсці\" }\n\nShow_Filters: { other: \"Фільтры\" }\nService: { other: \"Сэрвіс\" }\nUsername: { other: \"Імя карыстальніка\" }\nCategory: { other: \"Катэгорыя\" }\nApply: { other: \"Ужыць\" }\nFilter_Passwords: { other: \"Фільтр пароляў\" }\nClose: { other: \"Закрыць\" }\nRefresh: { other: \"Абнавіць\" }\n\nID: { other: \"ID\" }\nCreated_At: { other: \"Створана\" }\nLink: { other: \"Спасылка\" }\nCopy: { other: \"Капіраваць\" }\nLink_copied: { other: \"Спасылка скапіравана ў буфер абмену\" }\nPassword_copied: { other: \"Пароль скапіраваны ў буфер абмену\" }\n\nActions: { other: \"Дзеянні\" }\nAdd: { other: \"Дадаць\" }\nUpdate: { other: \"Абнавіць\" }\nDelete: { other: \"Выдаліць\" }\nYour_Passwords: { other: \"Вашы паролі\" }\n\nCreate_Password: { other: \"Стварыць пароль\" }\nLength: { other: \"Даўжыня\" }\nExclude: { other: \"Выключыць\" }\nExclude_chars: { other: \"Выключыць сімвалы\" }\nGenerate: { other: \"Згенераваць\" }\nInvalid_length: { other: \"Некарэктная даўжыня\" }\nGeneration_error: { other: \"Памылка генерацыі\" }\nGenerated_inserted: { other: \"Згенераваны пароль устаўлены\" }\n\nWeak_password: { other: \"Слабы пароль\" }\nStrong_password: { other: \"Моцны пароль\" }\nChoose_stronger: { other: \"Калі ласка, абярыце больш моцны пароль\" }\nmissing: { other: \"ня хапае\" }\nlength_8: { other: \"даўжыня ≥ 8\" }\ndigit: { other: \"лічба\" }\nuppercase: { other: \"вялікая літара\" }\nlowercase: { other: \"малая літара\" }\nsymbol: { other: \"сімвал\" }\n\nUpdate_Password: { other: \"Абнавіць пароль\" }\nUpdated: { other: \"Абноўлена\" }\nPassword_updated: { other: \"Пароль паспяхова абноўлены\" }\n\nDelete_Password: { other: \"Выдаліць пароль\" }\nEnter_ID_to_delete: { other: \"Увядзіце ID для выдалення\" }\nDeleted: { other: \"Выдалена\" }\nPassword_deleted: { other: \"Пароль паспяхова выдалены\" }\n\nRemember_master_password_hint: { other: \"Запомніце майстар‑пароль — аднавіць яго пры страце немагчыма.\" }\nFirst-time_setup: { other: \"Першапачатковая налада\" }\nEmail: { other: \"Электронная пошта\" }\nPassword: { other: \"Пароль\" }\nConfirm: { other: \"Пацверджанне\" }\nLanguage: { other: \"Мова\" }\nEnter_master_password_to_continue: { other: \"Увядзіце майстар‑пароль, каб працягнуць\" }\nWelcome_to_Manager: { other: \"Сардэчна запрашаем у менеджар пароляў\" }\nAny: { other: \"Любы\" }\nNo_results_yet: { other: \"Пакуль няма вынікаў\" }\nLeave_fields_empty_for_all: { other: \"Пакіньце палі пустымі, каб паказаць усе\" }\nNo_matching_entries: { other: \"Няма адпаведных запісаў\" }"),
+		"Password_Manager: { other: \"Менеджар пароляў\" }\nUnlock_Password_Manager: { other: \"Разблакіраваць менеджар пароляў\" }\nEnter_master_password: { other: \"Увядзіце майстар-пароль\" }\ninvalid_master_password: { other: \"Няправільны майстар-пароль\" }\nCreate_Master_Password: { other: \"Стварыць майстар-пароль\" }\nEnter_your_email: { other: \"Увядзіце ваш email\" }\nConfirm_master_password: { other: \"Пацвердзіце майстар-пароль\" }\nSave: { other: \"Захаваць\" }\nSuccess: { other: \"Поспех\" }\nMaster_password_saved: { other: \"Майстар-пароль захаваны\" }\npasswords_do_not_match: { other: \"Паролі не супадаюць\" }\nemail_and_password_required: { other: \"Патрабуюцца email і пароль\" }\n\nSession_locked: { other: \"Сесія заблакіравана праз бяздзейнасць\" }\nMaster_Password: { other: \"Майстар-пароль\" }\nUnlock_Session: { other: \"Разблакіраваць сесію\" }\nUnlock: { other: \"Разблакіраваць\" }\nExit: { other: \"Выйсці\" }\n\nShow_Filters: { other: \"Фільтры\" }\nService: { other: \"Сэрвіс\" }\nUsername: { other: \"Імя карыстальніка\" }\nApply: { other: \"Ужыць\" }\nFilter_Passwords: { other: \"Фільтр пароляў\" }\nClose: { other: \"Закрыць\" }\nRefresh: { other: \"Абнавіць\" }\n\nID: { other: \"ID\" }\nCreated_At: { other: \"Створана\" }\nLink: { other: \"Спасылка\" }\nCopy: { other: \"Капіраваць\" }\nLink_copied: { other: \"Спасылка скапіравана ў буфер абмену\" }\nPassword_copied: { other: \"Пароль скапіраваны ў буфер абмену\" }\n\nActions: { other: \"Дзеянні\" }\nAdd: { other: \"Дадаць\" }\nUpdate: { other: \"Абнавіць\" }\nDelete: { other: \"Выдаліць\" }\nYour_Passwords: { other: \"Вашы паролі\" }\n\nCreate_Password: { other: \"Стварыць пароль\" }\nLength: { other: \"Даўжыня\" }\nExclude: { other: \"Выключыць\" }\nExclude_chars: { other: \"Выключыць сімвалы\" }\nGenerate: { other: \"Згенераваць\" }\nInvalid_length: { other: \"Некарэктная даўжыня\" }\nGeneration_error: { other: \"Памылка генерацыі\" }\nGenerated_inserted: { other: \"Згенераваны пароль устаўлены\" }\n\nWeak_password: { other: \"Слабы пароль\" }\nStrong_password: { other: \"Моцны пароль\" }\nChoose_stronger: { other: \"Калі ласка, абярыце больш моцны пароль\" }\n\nUpdate_Password: { other: \"Абнавіць пароль\" }\nUpdated: { other: \"Абноўлена\" }\nPassword_updated: { other: \"Пароль паспяхова абноўлены\" }\n\nDelete_Password: { other: \"Выдаліць пароль\" }\nEnter_ID_to_delete: { other: \"Увядзіце ID для выдалення\" }\nDeleted: { other: \"Выдалена\" }\nPassword_deleted: { other: \"Пароль паспяхова выдалены\" }\n\nRemember_master_password_hint: { other: \"Запомніце майстар‑пароль — аднавіць яго пры страце немагчыма.\" }\nFirst-time_setup: { other: \"Першапачатковая налада\" }\nEmail: { other: \"Электронная пошта\" }\nPassword: { other: \"Пароль\" }\nConfirm: { other: \"Пацверджанне\" }\nLanguage: { other: \"Мова\" }\nEnter_master_password_to_continue: { other: \"Увядзіце майстар‑пароль, каб працягнуць\" }\nWelcome_to_Manager: { other: \"Сардэчна запрашаем у менеджар пароляў\" }\nAny: { other: \"Любы\" }\nNo_results_yet: { other: \"Пакуль няма вынікаў\" }\nLeave_fields_empty_for_all: { other: \"Пакіньце палі пустымі, каб паказаць усе\" }\nNo_matching_entries: { other: \"Няма адпаведных запісаў\" }\n\nChange_Master_Password: { other: \"Змяніць майстар-пароль\" }\nCurrent_master_password: { other: \"Бягучы майстар-пароль\" }\nNew_master_password: { other: \"Новы майстар-пароль\" }\nMaster_password_changed: { other: \"Майстар-пароль зменены\" }\nEntries_failed_to_decrypt: { other: \"Майстар-пароль не зменены, не ўдалося расшыфраваць запісы\" }\n\nFill_all_fields: { other: \"Запоўніце ўсе палі\" }\n\nEncrypt_all_fields: { other: \"Шыфраваць усе палі\" }\n\nTOTP: { other: \"Код 2FA\" }\nTOTP_secret: { other: \"Сакрэт 2FA (TOTP)\" }\nTOTP_placeholder: { other: \"otpauth:// URI або base32-сакрэт (неабавязкова)\" }\nTOTP_keep_placeholder: { other: \"Пакіньце пустым, каб не мяняць сакрэт\" }\nRemove_TOTP: { other: \"Выдаліць сакрэт 2FA\" }\nCode_copied: { other: \"Код 2FA скапіяваны ў буфер абмену\" }\n\nNotes: { other: \"Нататкі\" }\nCustom_fields: { other: \"Дадатковыя палі\" }\nAdd_field: { other: \"Дадаць поле\" }\nField_name: { other: \"Назва поля\" }\nDetails: { other: \"Падрабязнасці\" }\nOpen: { other: \"Адкрыць\" }\nLoad: { other: \"Загрузіць\" }\nNo_notes: { other: \"Няма нататак\" }\nValue_copied: { other: \"Значэнне скапіявана ў буфер абмену\" }\nHidden_value_copied: { other: \"Схаванае значэнне скапіявана ў буфер абмену\" }\n\nPassword_history: { other: \"Гісторыя пароляў\" }\nNo_history: { other: \"Ранейшых пароляў няма\" }\nShow: { other: \"Паказаць\" }\nRestore: { other: \"Аднавіць\" }\nRestore_password_confirm: { other: \"Зрабіць гэты пароль бягучым? Бягучы захаваецца ў гісторыі.\" }\nPassword_restored: { other: \"Пароль адноўлены\" }\n\nTrash: { other: \"Сметніца\" }\nRestored_from_trash: { other: \"Запіс адноўлены са сметніцы\" }\nDelete_permanently: { other: \"Выдаліць назаўсёды\" }\nDelete_permanently_confirm: { other: \"Выдаліць запіс назаўсёды? Гэта нельга адмяніць.\" }\nTrash_retention_days: { other: \"Захоўваць выдаленыя запісы, дзён (0 = заўсёды)\" }\nInvalid_retention: { other: \"Тэрмін захоўвання павінен быць неадмоўным лікам дзён\" }\nSettings_saved: { other: \"Налады захаваны\" }\nEntry_not_found: { other: \"Запіс не знойдзены\" }\nEntry_in_trash: { other: \"Запіс у сметніцы — спачатку аднавіце яго\" }\nMove_to_trash_confirm: { other: \"Перамясціць запіс у сметніцу?\" }\nMoved_to_trash: { other: \"Перамешчана ў сметніцу\" }\n\nVault_menu: { other: \"Сховішча\" }\nExport_vault: { other: \"Экспарт…\" }\nImport_vault: { other: \"Імпарт…\" }\nExport_passphrase: { other: \"Пароль экспарту\" }\nExport_passphrase_hint: { other: \"Файл шыфруецца гэтым паролем; ён спатрэбіцца для імпарту.\" }\nConfirm_passphrase: { other: \"Паўтарыце пароль\" }\nPassphrase_required: { other: \"Пароль не можа быць пустым\" }\nPassphrases_mismatch: { other: \"Паролі не супадаюць\" }\nVault_exported: { other: \"Сховішча экспартавана\" }\nEntries_imported: { other: \"Імпартавана запісаў:\" }\nWrong_passphrase: { other: \"Няправільны пароль або файл пашкоджаны\" }\n\nCancel: { other: \"Адмена\" }\n\nImport_other: { other: \"Імпарт з іншага менеджара…\" }\nImport_format: { other: \"Фармат\" }\nChoose_file: { other: \"Выбраць файл…\" }\nNo_file_selected: { other: \"Файл не выбраны\" }\nKeePass_password: { other: \"Майстар-пароль KeePass\" }\nPreview: { other: \"Папярэдні прагляд\" }\nFolder_mapping: { other: \"Папкі → катэгорыі\" }\nSkip_duplicates: { other: \"Прапускаць дублікаты\" }\nDuplicate_of: { other: \"дублікат #\" }\nImport_entries: { other: \"Імпартаваць\" }\nNothing_to_import: { other: \"Няма чаго імпартаваць\" }\nImported_count: { other: \"Імпартавана:\" }\nSkipped_count: { other: \"прапушчана:\" }\n\nExport_plain: { other: \"Экспарт без шыфравання (CSV/JSON)…\" }\nExport_plain_warning: { other: \"Файл будзе змяшчаць усе паролі ў адкрытым выглядзе. Любы, хто яго атрымае, прачытае ўсё сховішча. Выдаліце яго, як толькі ён стане непатрэбны.\" }\nExport_plain_confirm: { other: \"Я разумею, што файл НЕ будзе зашыфраваны\" }\nConfirm_risk_required: { other: \"Пацвердзіце, што разумееце рызыку\" }\nInvalid_master_password: { other: \"Няправільны майстар-пароль\" }\n\nNew_vault: { other: \"Новае сховішча\" }\nAdd_existing_vault: { other: \"Адкрыць файл сховішча\" }\nVault_name: { other: \"Імя сховішча\" }\nVault_file_exists: { other: \"Файл для сховішча з такім імем ужо ёсць — адкрыйце яго праз «Адкрыць файл сховішча»\" }\nSwitch_vault: { other: \"Змяніць сховішча…\" }\n\nAuto_lock: { other: \"Аўтаблакіроўка…\" }\nLock_now: { other: \"Заблакіраваць\" }\nLock_idle_minutes: { other: \"Блакіраваць пасля бяздзейнасці, хв\" }\nLock_max_session_minutes: { other: \"Максімальная даўжыня сесіі, хв\" }\nLock_minutes_hint: { other: \"0 — ніколі\" }\nLock_on_focus_loss: { other: \"Блакіраваць пры страце фокусу\" }\nLock_on_focus_loss_hint: { other: \"Спрацоўвае і пры пераключэнні на іншае акно, а не толькі пры згортванні\" }\nLock_on_suspend: { other: \"Блакіраваць пры пераходзе сістэмы ў сон\" }\nLock_minutes_invalid: { other: \"Хвіліны — цэлы лік ад 0 да 10080\" }\n\nClipboard_settings: { other: \"Буфер абмену…\" }\nClipboard_clear_seconds: { other: \"Ачышчаць скапіраванае праз, с\" }\nClipboard_seconds_invalid: { other: \"Секунды — цэлы лік ад 5 да 600\" }\n\nCharacters: { other: \"Сімвалы\" }\nPassphrase: { other: \"Парольная фраза\" }\nCapitalize_words: { other: \"Словы з вялікай літары\" }\nWord_count: { other: \"Слоў\" }\nSeparator: { other: \"Раздзяляльнік\" }\nDigits_to_insert: { other: \"Лічбаў\" }\nPassphrase_options_invalid: { other: \"Колькасць слоў і лічбаў павінна быць лікам\" }\nPassphrase_inserted: { other: \"Згенераваная фраза ўстаўлена\" }\nbits: { other: \"біт\" }\n\nExclude_ambiguous: { other: \"Без падобных\" }\nNo_repeat: { other: \"Без паўтораў\" }\nPronounceable: { other: \"Вымаўляльны\" }\nSave_generator_rules: { other: \"Захаваць правілы ў запісе\" }\nMin_count: { other: \"мін.\" }\nMin_count_invalid: { other: \"Мінімумы павінны быць лікамі\" }\nSymbol_set: { other: \"Набор сімвалаў\" }\n\nStrength_top_ten: { other: \"Гэта адзін з 10 самых частых пароляў\" }\nStrength_top_hundred: { other: \"Гэта адзін са 100 самых частых пароляў\" }\nStrength_very_common: { other: \"Гэта вельмі распаўсюджаны пароль\" }\nStrength_similar_to_common: { other: \"Падобна на распаўсюджаны пароль\" }\nStrength_word_by_itself: { other: \"Адно слова лёгка адгадаць\" }\nStrength_names_by_themselves: { other: \"Імёны і прозвішчы самі па сабе лёгка адгадаць\" }\nStrength_common_names: { other: \"Распаўсюджаныя імёны і прозвішчы лёгка адгадаць\" }\nStrength_user_inputs: { other: \"Пароль змяшчае назву сэрвісу або лагін\" }\nStrength_straight_rows: { other: \"Рады клавіш запар лёгка адгадаць\" }\nStrength_keyboard_pattern: { other: \"Кароткія клавіятурныя ўзоры лёгка адгадаць\" }\nStrength_repeat_char: { other: \"Паўторы выгляду «aaa» лёгка адгадаць\" }\nStrength_repeat_pattern: { other: \"Паўторы выгляду «abcabcabc» ненашмат складаней, чым «abc»\" }\nStrength_sequence: { other: \"Паслядоўнасці накшталт abc або 6543 лёгка адгадаць\" }\nStrength_recent_years: { other: \"Нядаўнія гады лёгка адгадаць\" }\nStrength_dates: { other: \"Даты часта лёгка адгадаць\" }\nStrength_use_words: { other: \"Выкарыстоўвайце некалькі слоў, але не агульнавядомыя фразы\" }\nStrength_no_need_for_symbols: { other: \"Сімвалы, лічбы і вялікія літары не абавязковыя\" }\nStrength_add_word: { other: \"Дадайце яшчэ слова-другое, лепш рэдкія\" }\nStrength_capitalization: { other: \"Вялікая літара амаль не дапамагае\" }\nStrength_all_uppercase: { other: \"Усё вялікімі адгадаць амаль гэтак жа лёгка, як малымі\" }\nStrength_reversed_words: { other: \"Словы задам наперад ненашмат складаней адгадаць\" }\nStrength_l33t: { other: \"Прадказальныя замены накшталт «@» замест «a» амаль не дапамагаюць\" }\nStrength_longer_keyboard: { other: \"Выкарыстоўвайце даўжэйшы ўзор з паваротамі\" }\nStrength_avoid_repeats: { other: \"Пазбягайце паўтораў слоў і сімвалаў\" }\nStrength_avoid_sequences: { other: \"Пазбягайце паслядоўнасцяў\" }\nStrength_avoid_years: { other: \"Пазбягайце нядаўніх і памятных вам гадоў\" }\nStrength_avoid_dates: { other: \"Пазбягайце памятных вам дат і гадоў\" }\nCrack_time: { other: \"Час падбору афлайн\" }\nCrack_instant: { other: \"менш за секунду\" }\nCrack_centuries: { other: \"стагоддзі\" }\nUnit_seconds: { other: \"с\" }\nUnit_minutes: { other: \"хв\" }\nUnit_hours: { other: \"г\" }\nUnit_days: { other: \"дз.\" }\nUnit_months: { other: \"мес.\" }\nUnit_years: { other: \"г.\" }\n\nHealth: { other: \"Здароўе\" }\nHealth_hint: { other: \"Знаходзіць паўторныя, слабыя і старыя паролі. Запісы расшыфроўваюцца толькі ў памяці.\" }\nCheck_health: { other: \"Праверыць паролі\" }\nChecking: { other: \"Праверка…\" }\nMin_score: { other: \"Слабы, калі ацэнка ніжэй (0–4)\" }\nMax_age_days: { other: \"Стары праз, дзён (0 — выкл.)\" }\nInvalid_max_age: { other: \"Увядзіце колькасць дзён ад 0 да 36500\" }\nEntries_checked: { other: \"Праверана запісаў\" }\nIssues_found: { other: \"З праблемамі\" }\nNo_issues: { other: \"Праблем не знойдзена\" }\nUndecryptable_entries: { other: \"Не ўдалося расшыфраваць\" }\nReused_passwords: { other: \"Паўторныя паролі\" }\nWeak_passwords: { other: \"Слабыя паролі\" }\nOld_passwords: { other: \"Старыя паролі\" }\ndays: { other: \"дз.\" }\n\nFound_in_breaches: { other: \"Сустракаўся ва ўцечках, разоў\" }\nBreached_passwords: { other: \"Знойдзены ва ўцечках\" }\nBreach_check_off: { other: \"Праверка па ўцечках выключана: пакажыце лакальны файл Pwned Passwords\" }\nBreach_index: { other: \"Файл SHA-1 Pwned Passwords або папка дыяпазонаў (афлайн)\" }\nBreach_index_placeholder: { other: \"Шлях; пуста — праверка выключана\" }\nBreach_index_invalid: { other: \"Гэты файл уцечак не падыходзіць\" }\n\nFolder: { other: \"Папка\" }\nFolders: { other: \"Папкі\" }\nTags: { other: \"Тэгі\" }\nTag: { other: \"Тэг\" }\nAll_entries: { other: \"Усе запісы\" }\nNew_folder: { other: \"Новая папка\" }\nRename: { other: \"Перайменаваць\" }\nMerge: { other: \"Аб'яднаць\" }\nMerge_into: { other: \"Аб'яднаць з\" }\nDelete_folder_confirm: { other: \"Выдаліць папку? Яе запісы і падпапкі пяройдуць у бацькоўскую папку.\" }\nDelete_tag_confirm: { other: \"Зняць гэты тэг з усіх запісаў?\" }\nManage_tags: { other: \"Кіраванне тэгамі\" }\nAny_tag: { other: \"Любы тэг\" }\nFolder_name: { other: \"Імя папкі\" }\nTag_name: { other: \"Імя тэга\" }\nTop_level: { other: \"Верхні ўзровень\" }\nSelect_folder: { other: \"Спачатку выберыце папку\" }\nSelect_tag: { other: \"Спачатку выберыце тэг\" }\nFolder_placeholder: { other: \"Праца/Кліенты — адсутныя папкі будуць створаны\" }\nTags_placeholder: { other: \"праца, тэрмінова\" }\nInfo: { other: \"Інфармацыя\" }\n\nSearch_placeholder: { other: \"Пошук па сэрвісе, лагіне, спасылцы, нататках і тэгах\" }"),
 }
@@ -18,7 +18,6 @@ func InitApp(a fyne.App) *pmapp.App {
     }
 
     return &pmapp.App{
-        DB:     storage,
-        Crypto: nil,
+        DB: storage,
     }
 }
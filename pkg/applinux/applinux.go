@@ -4,10 +4,23 @@
 package applinux
 
 import (
+    "log"
+
     "github.com/labstack/echo/v4"
     pmapp "password-manager/internal/app"
 )
 
+// Хранилище по умолчанию из реестра пользователя (см. pmapp.LoadDefaultRegistry)
 func InitApp(e *echo.Echo) *pmapp.App {
-    return pmapp.InitApp(e, "passwords.db")
+    reg, err := pmapp.LoadDefaultRegistry()
+    if err != nil {
+        log.Fatal(err)
+    }
+    vault, err := reg.DefaultVault()
+    if err != nil {
+        log.Fatal(err)
+    }
+    appInstance := pmapp.InitApp(e, vault.Path)
+    appInstance.Vault = vault.Name
+    return appInstance
 }
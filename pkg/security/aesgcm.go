@@ -10,6 +10,17 @@ import (
 
 // EncryptAESGCM: шифрует plaintext, возвращает nonce||ciphertext.
 func EncryptAESGCM(key []byte, plaintext []byte) ([]byte, error) {
+    return SealAESGCM(key, plaintext, nil)
+}
+
+// DecryptAESGCM: принимает nonce||ciphertext, возвращает plaintext.
+func DecryptAESGCM(key []byte, data []byte) ([]byte, error) {
+    return OpenAESGCM(key, data, nil)
+}
+
+// SealAESGCM: как EncryptAESGCM, но с дополнительными данными (AAD),
+// которые не шифруются, но должны совпасть при расшифровке.
+func SealAESGCM(key []byte, plaintext, ad []byte) ([]byte, error) {
     block, err := aes.NewCipher(key)
     if err != nil {
         return nil, err
@@ -22,15 +33,15 @@ func EncryptAESGCM(key []byte, plaintext []byte) ([]byte, error) {
     if _, err := rand.Read(nonce); err != nil {
         return nil, err
     }
-    ct := aead.Seal(nil, nonce, plaintext, nil)
+    ct := aead.Seal(nil, nonce, plaintext, ad)
     out := make([]byte, len(nonce)+len(ct))
     copy(out, nonce)
     copy(out[len(nonce):], ct)
     return out, nil
 }
 
-// DecryptAESGCM: принимает nonce||ciphertext, возвращает plaintext.
-func DecryptAESGCM(key []byte, data []byte) ([]byte, error) {
+// OpenAESGCM: принимает nonce||ciphertext и те же AAD, что при SealAESGCM.
+func OpenAESGCM(key []byte, data, ad []byte) ([]byte, error) {
     block, err := aes.NewCipher(key)
     if err != nil {
         return nil, err
@@ -43,5 +54,5 @@ func DecryptAESGCM(key []byte, data []byte) ([]byte, error) {
     if len(data) < n {
         return nil, errors.New("invalid data")
     }
-    return aead.Open(nil, data[:n], data[n:], nil)
+    return aead.Open(nil, data[:n], data[n:], ad)
 }
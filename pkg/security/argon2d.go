@@ -0,0 +1,256 @@
+// argon2d.go
+package security
+
+import (
+    "encoding/binary"
+    "hash"
+    "sync"
+
+    "golang.org/x/crypto/blake2b"
+)
+
+// Argon2d нужен только для чтения чужих хранилищ (KeePass KDBX 4 по умолчанию
+// использует именно его), а golang.org/x/crypto/argon2 экспортирует лишь
+// Argon2i и Argon2id. Ниже — та же реализация (RFC 9106), сокращённая до
+// режима d: все обращения к памяти зависят от данных.
+// Основано на golang.org/x/crypto/argon2 (BSD-style license, The Go Authors).
+
+const (
+    argon2Version    = 0x13
+    argon2dType      = 0
+    argon2BlockWords = 128 // 1 KiB
+    argon2SyncPoints = 4
+)
+
+type argon2Block [argon2BlockWords]uint64
+
+// Argon2dKey: ключ длиной keyLen; memory задаётся в KiB, как у argon2.IDKey.
+func Argon2dKey(password, salt []byte, time, memory uint32, threads uint8, keyLen uint32) []byte {
+    return argon2dKey(password, salt, nil, nil, time, memory, threads, keyLen)
+}
+
+func argon2dKey(password, salt, secret, data []byte, time, memory uint32, threads uint8, keyLen uint32) []byte {
+    if time < 1 || threads < 1 {
+        panic("argon2d: invalid parameters")
+    }
+    lanes := uint32(threads)
+    h0 := argon2InitHash(password, salt, secret, data, time, memory, lanes, keyLen)
+
+    memory = memory / (argon2SyncPoints * lanes) * (argon2SyncPoints * lanes)
+    if memory < 2*argon2SyncPoints*lanes {
+        memory = 2 * argon2SyncPoints * lanes
+    }
+    B := argon2InitBlocks(&h0, memory, lanes)
+    argon2dProcessBlocks(B, time, memory, lanes)
+    return argon2ExtractKey(B, memory, lanes, keyLen)
+}
+
+func argon2InitHash(password, salt, key, data []byte, time, memory, threads, keyLen uint32) [blake2b.Size + 8]byte {
+    var (
+        h0     [blake2b.Size + 8]byte
+        params [24]byte
+        tmp    [4]byte
+    )
+    b2, _ := blake2b.New512(nil)
+    binary.LittleEndian.PutUint32(params[0:4], threads)
+    binary.LittleEndian.PutUint32(params[4:8], keyLen)
+    binary.LittleEndian.PutUint32(params[8:12], memory)
+    binary.LittleEndian.PutUint32(params[12:16], time)
+    binary.LittleEndian.PutUint32(params[16:20], argon2Version)
+    binary.LittleEndian.PutUint32(params[20:24], argon2dType)
+    b2.Write(params[:])
+    for _, part := range [][]byte{password, salt, key, data} {
+        binary.LittleEndian.PutUint32(tmp[:], uint32(len(part)))
+        b2.Write(tmp[:])
+        b2.Write(part)
+    }
+    b2.Sum(h0[:0])
+    return h0
+}
+
+func argon2InitBlocks(h0 *[blake2b.Size + 8]byte, memory, threads uint32) []argon2Block {
+    var block0 [1024]byte
+    B := make([]argon2Block, memory)
+    for lane := uint32(0); lane < threads; lane++ {
+        j := lane * (memory / threads)
+        binary.LittleEndian.PutUint32(h0[blake2b.Size+4:], lane)
+        for k := uint32(0); k < 2; k++ {
+            binary.LittleEndian.PutUint32(h0[blake2b.Size:], k)
+            argon2Hash(block0[:], h0[:])
+            for i := range B[j+k] {
+                B[j+k][i] = binary.LittleEndian.Uint64(block0[i*8:])
+            }
+        }
+    }
+    return B
+}
+
+func argon2dProcessBlocks(B []argon2Block, time, memory, threads uint32) {
+    lanes := memory / threads
+    segments := lanes / argon2SyncPoints
+
+    processSegment := func(n, slice, lane uint32, wg *sync.WaitGroup) {
+        defer wg.Done()
+        index := uint32(0)
+        if n == 0 && slice == 0 {
+            index = 2 // первые два блока уже заполнены
+        }
+        offset := lane*lanes + slice*segments + index
+        for index < segments {
+            prev := offset - 1
+            if index == 0 && slice == 0 {
+                prev += lanes // последний блок полосы
+            }
+            ref := argon2IndexAlpha(B[prev][0], lanes, segments, threads, n, slice, lane, index)
+            argon2ProcessBlock(&B[offset], &B[prev], &B[ref], n > 0)
+            index, offset = index+1, offset+1
+        }
+    }
+
+    for n := uint32(0); n < time; n++ {
+        for slice := uint32(0); slice < argon2SyncPoints; slice++ {
+            var wg sync.WaitGroup
+            for lane := uint32(0); lane < threads; lane++ {
+                wg.Add(1)
+                go processSegment(n, slice, lane, &wg)
+            }
+            wg.Wait()
+        }
+    }
+}
+
+func argon2ExtractKey(B []argon2Block, memory, threads, keyLen uint32) []byte {
+    lanes := memory / threads
+    for lane := uint32(0); lane < threads-1; lane++ {
+        for i, v := range B[lane*lanes+lanes-1] {
+            B[memory-1][i] ^= v
+        }
+    }
+    var block [1024]byte
+    for i, v := range B[memory-1] {
+        binary.LittleEndian.PutUint64(block[i*8:], v)
+    }
+    key := make([]byte, keyLen)
+    argon2Hash(key, block[:])
+    return key
+}
+
+func argon2IndexAlpha(rand uint64, lanes, segments, threads, n, slice, lane, index uint32) uint32 {
+    refLane := uint32(rand>>32) % threads
+    if n == 0 && slice == 0 {
+        refLane = lane
+    }
+    m, s := 3*segments, ((slice+1)%argon2SyncPoints)*segments
+    if lane == refLane {
+        m += index
+    }
+    if n == 0 {
+        m, s = slice*segments, 0
+        if slice == 0 || lane == refLane {
+            m += index
+        }
+    }
+    if index == 0 || lane == refLane {
+        m--
+    }
+    p := rand & 0xFFFFFFFF
+    p = (p * p) >> 32
+    p = (p * uint64(m)) >> 32
+    return refLane*lanes + uint32((uint64(s)+uint64(m)-(p+1))%uint64(lanes))
+}
+
+// argon2ProcessBlock: функция сжатия G; со второго прохода результат XOR-ится с out
+func argon2ProcessBlock(out, in1, in2 *argon2Block, xor bool) {
+    var t argon2Block
+    for i := range t {
+        t[i] = in1[i] ^ in2[i]
+    }
+    for i := 0; i < argon2BlockWords; i += 16 {
+        argon2Round(
+            &t[i+0], &t[i+1], &t[i+2], &t[i+3], &t[i+4], &t[i+5], &t[i+6], &t[i+7],
+            &t[i+8], &t[i+9], &t[i+10], &t[i+11], &t[i+12], &t[i+13], &t[i+14], &t[i+15],
+        )
+    }
+    for i := 0; i < argon2BlockWords/8; i += 2 {
+        argon2Round(
+            &t[i], &t[i+1], &t[16+i], &t[16+i+1], &t[32+i], &t[32+i+1], &t[48+i], &t[48+i+1],
+            &t[64+i], &t[64+i+1], &t[80+i], &t[80+i+1], &t[96+i], &t[96+i+1], &t[112+i], &t[112+i+1],
+        )
+    }
+    for i := range t {
+        v := in1[i] ^ in2[i] ^ t[i]
+        if xor {
+            out[i] ^= v
+        } else {
+            out[i] = v
+        }
+    }
+}
+
+// argon2Round: раунд BLAKE2b с умножением (BlaMka) над 16 словами
+func argon2Round(v0, v1, v2, v3, v4, v5, v6, v7, v8, v9, v10, v11, v12, v13, v14, v15 *uint64) {
+    argon2G(v0, v4, v8, v12)
+    argon2G(v1, v5, v9, v13)
+    argon2G(v2, v6, v10, v14)
+    argon2G(v3, v7, v11, v15)
+    argon2G(v0, v5, v10, v15)
+    argon2G(v1, v6, v11, v12)
+    argon2G(v2, v7, v8, v13)
+    argon2G(v3, v4, v9, v14)
+}
+
+func argon2G(a, b, c, d *uint64) {
+    fBlaMka := func(x, y uint64) uint64 { return x + y + 2*uint64(uint32(x))*uint64(uint32(y)) }
+    *a = fBlaMka(*a, *b)
+    *d ^= *a
+    *d = *d>>32 | *d<<32
+    *c = fBlaMka(*c, *d)
+    *b ^= *c
+    *b = *b>>24 | *b<<40
+    *a = fBlaMka(*a, *b)
+    *d ^= *a
+    *d = *d>>16 | *d<<48
+    *c = fBlaMka(*c, *d)
+    *b ^= *c
+    *b = *b>>63 | *b<<1
+}
+
+// argon2Hash: H' из RFC 9106 — BLAKE2b с выходом произвольной длины
+func argon2Hash(out []byte, in []byte) {
+    var b2 hash.Hash
+    if n := len(out); n < blake2b.Size {
+        b2, _ = blake2b.New(n, nil)
+    } else {
+        b2, _ = blake2b.New512(nil)
+    }
+
+    var buffer [blake2b.Size]byte
+    binary.LittleEndian.PutUint32(buffer[:4], uint32(len(out)))
+    b2.Write(buffer[:4])
+    b2.Write(in)
+
+    if len(out) <= blake2b.Size {
+        b2.Sum(out[:0])
+        return
+    }
+
+    outLen := len(out)
+    b2.Sum(buffer[:0])
+    b2.Reset()
+    copy(out, buffer[:32])
+    out = out[32:]
+    for len(out) > blake2b.Size {
+        b2.Write(buffer[:])
+        b2.Sum(buffer[:0])
+        copy(out, buffer[:32])
+        out = out[32:]
+        b2.Reset()
+    }
+
+    if outLen%blake2b.Size > 0 {
+        r := ((outLen + 31) / 32) - 2
+        b2, _ = blake2b.New(outLen-32*r, nil)
+    }
+    b2.Write(buffer[:])
+    b2.Sum(out[:0])
+}
@@ -0,0 +1,249 @@
+// breach.go
+package security
+
+import (
+    "bytes"
+    "crypto/sha1"
+    "encoding/hex"
+    "errors"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "strconv"
+    "strings"
+    "sync"
+)
+
+// Проверка по локальной копии Have I Been Pwned (Pwned Passwords, SHA-1),
+// без сети. Поддерживаются два вида загрузки:
+//   - один файл "HASH:COUNT", отсортированный по хэшу (ordered-by-hash) —
+//     двоичный поиск по смещениям, файл целиком не читается;
+//   - каталог диапазонов PwnedPasswordsDownloader: файлы PREFIX.txt
+//     (первые 5 символов хэша) со строками "SUFFIX:COUNT" — как k-anonymity API.
+// Одна база на всё приложение (GUI и REST), см. Breaches.
+
+const (
+    breachPrefixLen = 5
+    maxBreachLine   = 64 // 40 hex + ':' + счётчик + "\r"
+)
+
+var (
+    ErrNoBreachIndex = errors.New("breached password index is not configured")
+    ErrBreachFormat  = errors.New("not a Pwned Passwords SHA-1 file")
+)
+
+type BreachIndex struct {
+    mu   sync.RWMutex
+    path string
+    dir  bool     // каталог диапазонов
+    file *os.File // отсортированный файл
+    size int64
+}
+
+var Breaches = &BreachIndex{}
+
+// Open подключает файл или каталог диапазонов вместо прежнего; "" — отключить проверку
+func (b *BreachIndex) Open(path string) error {
+    var file *os.File
+    var size int64
+    dir := false
+    if path != "" {
+        info, err := os.Stat(path)
+        if err != nil {
+            return err
+        }
+        if dir = info.IsDir(); dir {
+            // 00000.txt есть в любой полной загрузке
+            if err := checkBreachRange(filepath.Join(path, "00000.txt")); err != nil {
+                return err
+            }
+        } else {
+            if file, err = os.Open(path); err != nil {
+                return err
+            }
+            size = info.Size()
+            if err := checkBreachFile(file); err != nil {
+                file.Close()
+                return err
+            }
+        }
+    }
+
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    if b.file != nil {
+        b.file.Close()
+    }
+    b.path, b.dir, b.file, b.size = path, dir, file, size
+    return nil
+}
+
+func (b *BreachIndex) Close() error {
+    return b.Open("")
+}
+
+func (b *BreachIndex) Path() string {
+    b.mu.RLock()
+    defer b.mu.RUnlock()
+    return b.path
+}
+
+func (b *BreachIndex) Enabled() bool {
+    return b.Path() != ""
+}
+
+// Count: сколько раз пароль встречался в утечках (0 — не встречался)
+func (b *BreachIndex) Count(password string) (int, error) {
+    sum := sha1.Sum([]byte(password))
+    hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+    b.mu.RLock()
+    defer b.mu.RUnlock()
+    switch {
+    case b.path == "":
+        return 0, ErrNoBreachIndex
+    case b.dir:
+        return b.countInRange(hash)
+    default:
+        return b.countInFile(hash)
+    }
+}
+
+// countInRange: линейный просмотр файла диапазона (в нём сотни строк)
+func (b *BreachIndex) countInRange(hash string) (int, error) {
+    data, err := os.ReadFile(filepath.Join(b.path, hash[:breachPrefixLen]+".txt"))
+    if err != nil {
+        return 0, err
+    }
+    suffix := []byte(hash[breachPrefixLen:])
+    for _, line := range bytes.Split(data, []byte("\n")) {
+        h, n, ok := parseBreachLine(bytes.TrimRight(line, "\r"))
+        if ok && bytes.EqualFold(h, suffix) {
+            return n, nil
+        }
+    }
+    return 0, nil
+}
+
+// countInFile: двоичный поиск по смещениям; lo всегда начало строки,
+// искомая строка (если есть) начинается в [lo, hi)
+func (b *BreachIndex) countInFile(hash string) (int, error) {
+    target := []byte(hash)
+    lo, hi := int64(0), b.size
+    for lo < hi {
+        mid := lo + (hi-lo)/2
+        start, next, line, err := b.lineAt(mid)
+        if err != nil {
+            return 0, err
+        }
+        if start >= hi {
+            hi = mid
+            continue
+        }
+        h, n, ok := parseBreachLine(line)
+        if !ok || len(h) != sha1.Size*2 {
+            return 0, fmt.Errorf("%w: bad line at offset %d", ErrBreachFormat, start)
+        }
+        switch c := bytes.Compare(bytes.ToUpper(h), target); {
+        case c == 0:
+            return n, nil
+        case c < 0:
+            lo = next
+        default:
+            hi = mid
+        }
+    }
+    return 0, nil
+}
+
+// lineAt: первая строка, начинающаяся не раньше off (start == size — таких нет);
+// next — начало следующей строки
+func (b *BreachIndex) lineAt(off int64) (start, next int64, line []byte, err error) {
+    pos := off
+    if off > 0 {
+        pos = off - 1 // если строка начинается ровно с off, перед ней '\n'
+    }
+    buf := make([]byte, 2*maxBreachLine)
+    n, err := b.file.ReadAt(buf, pos)
+    if err != nil && err != io.EOF {
+        return 0, 0, nil, err
+    }
+    buf = buf[:n]
+    eof := pos+int64(n) >= b.size
+
+    i := 0
+    if off > 0 {
+        nl := bytes.IndexByte(buf, '\n')
+        if nl < 0 {
+            if eof {
+                return b.size, b.size, nil, nil
+            }
+            return 0, 0, nil, fmt.Errorf("%w: line too long at offset %d", ErrBreachFormat, pos)
+        }
+        i = nl + 1
+    }
+    rest := buf[i:]
+    end := bytes.IndexByte(rest, '\n')
+    switch {
+    case end >= 0:
+        next = pos + int64(i+end+1)
+    case eof:
+        end, next = len(rest), b.size
+    default:
+        return 0, 0, nil, fmt.Errorf("%w: line too long at offset %d", ErrBreachFormat, pos+int64(i))
+    }
+    return pos + int64(i), next, bytes.TrimRight(rest[:end], "\r"), nil
+}
+
+// parseBreachLine: "HASH:COUNT" → hex-часть и счётчик
+func parseBreachLine(line []byte) ([]byte, int, bool) {
+    h, count, ok := bytes.Cut(line, []byte(":"))
+    if !ok || len(h) == 0 {
+        return nil, 0, false
+    }
+    n, err := strconv.Atoi(string(count))
+    if err != nil {
+        return nil, 0, false
+    }
+    for _, c := range h {
+        if !isHexDigit(c) {
+            return nil, 0, false
+        }
+    }
+    return h, n, true
+}
+
+func isHexDigit(c byte) bool {
+    return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// checkBreachFile: первая строка — полный SHA-1 со счётчиком
+func checkBreachFile(f *os.File) error {
+    buf := make([]byte, maxBreachLine)
+    n, err := f.ReadAt(buf, 0)
+    if err != nil && err != io.EOF {
+        return err
+    }
+    line, _, _ := bytes.Cut(buf[:n], []byte("\n"))
+    if h, _, ok := parseBreachLine(bytes.TrimRight(line, "\r")); !ok || len(h) != sha1.Size*2 {
+        return ErrBreachFormat
+    }
+    return nil
+}
+
+// checkBreachRange: первая строка — суффикс SHA-1 (35 символов) со счётчиком
+func checkBreachRange(path string) error {
+    data, err := os.ReadFile(path)
+    if errors.Is(err, os.ErrNotExist) {
+        return fmt.Errorf("%w: %s not found", ErrBreachFormat, filepath.Base(path))
+    }
+    if err != nil {
+        return err
+    }
+    line, _, _ := bytes.Cut(data, []byte("\n"))
+    if h, _, ok := parseBreachLine(bytes.TrimRight(line, "\r")); !ok || len(h) != sha1.Size*2-breachPrefixLen {
+        return ErrBreachFormat
+    }
+    return nil
+}
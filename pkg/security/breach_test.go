@@ -0,0 +1,102 @@
+// breach_test.go
+package security
+
+import (
+    "crypto/sha1"
+    "encoding/hex"
+    "errors"
+    "os"
+    "path/filepath"
+    "sort"
+    "strconv"
+    "strings"
+    "testing"
+)
+
+// breachFixture: отсортированный по хэшу файл "HASH:COUNT"; счётчик пароля
+// passwords[i] — i+1, чтобы было видно, какая строка нашлась
+func breachFixture(t *testing.T, passwords []string, eol string) (*BreachIndex, []string) {
+    t.Helper()
+    type row struct {
+        hash  string
+        count int
+    }
+    rows := make([]row, len(passwords))
+    for i, p := range passwords {
+        sum := sha1.Sum([]byte(p))
+        rows[i] = row{strings.ToUpper(hex.EncodeToString(sum[:])), i + 1}
+    }
+    sort.Slice(rows, func(i, j int) bool { return rows[i].hash < rows[j].hash })
+
+    var sb strings.Builder
+    byHash := make([]string, len(rows))
+    for i, r := range rows {
+        sb.WriteString(r.hash + ":" + strconv.Itoa(r.count) + eol)
+        byHash[i] = passwords[r.count-1]
+    }
+    path := filepath.Join(t.TempDir(), "pwned.txt")
+    if err := os.WriteFile(path, []byte(sb.String()), 0o600); err != nil {
+        t.Fatal(err)
+    }
+    b := &BreachIndex{}
+    if err := b.Open(path); err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() { b.Close() })
+    return b, byHash
+}
+
+func TestBreachCountSortedFile(t *testing.T) {
+    passwords := []string{"password", "123456", "qwerty", "letmein", "dragon", "monkey", "iloveyou"}
+    for _, eol := range []string{"\n", "\r\n"} {
+        b, byHash := breachFixture(t, passwords, eol)
+        // первая, последняя и средняя строки файла — по порядку хэшей
+        for _, p := range []string{byHash[0], byHash[len(byHash)-1], byHash[len(byHash)/2]} {
+            want := 0
+            for i, q := range passwords {
+                if q == p {
+                    want = i + 1
+                }
+            }
+            if got, err := b.Count(p); err != nil || got != want {
+                t.Errorf("eol %q: Count(%q) = %d, %v; want %d", eol, p, got, err, want)
+            }
+        }
+        // все остальные тоже находятся
+        for i, p := range passwords {
+            if got, err := b.Count(p); err != nil || got != i+1 {
+                t.Errorf("eol %q: Count(%q) = %d, %v; want %d", eol, p, got, err, i+1)
+            }
+        }
+        for _, p := range []string{"correct horse battery staple", "", "Password"} {
+            if got, err := b.Count(p); err != nil || got != 0 {
+                t.Errorf("eol %q: Count(%q) = %d, %v; want 0", eol, p, got, err)
+            }
+        }
+    }
+}
+
+func TestBreachCountMalformedLine(t *testing.T) {
+    // первая строка в порядке (её проверяет Open), остальные — мусор
+    data := "0000000000000000000000000000000000000000:1\nnot a hash line\n" + strings.Repeat("Z", 20) + ":x\n"
+    path := filepath.Join(t.TempDir(), "pwned.txt")
+    if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+        t.Fatal(err)
+    }
+    b := &BreachIndex{}
+    if err := b.Open(path); err != nil {
+        t.Fatal(err)
+    }
+    defer b.Close()
+    if _, err := b.Count("password"); !errors.Is(err, ErrBreachFormat) {
+        t.Errorf("Count over a malformed line: err = %v, want ErrBreachFormat", err)
+    }
+
+    // и файл, у которого испорчена уже первая строка, не подключается
+    if err := os.WriteFile(path, []byte("hello:1\n"), 0o600); err != nil {
+        t.Fatal(err)
+    }
+    if err := (&BreachIndex{}).Open(path); !errors.Is(err, ErrBreachFormat) {
+        t.Errorf("Open(malformed) = %v, want ErrBreachFormat", err)
+    }
+}
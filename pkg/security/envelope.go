@@ -0,0 +1,47 @@
+// envelope.go
+package security
+
+import (
+    "crypto/hmac"
+    "crypto/rand"
+    "crypto/sha256"
+    "errors"
+)
+
+const dataKeyLength = 32 // AES-256
+
+// NewDataKey: случайный ключ хранилища, которым шифруются записи.
+func NewDataKey() ([]byte, error) {
+    key := make([]byte, dataKeyLength)
+    if _, err := rand.Read(key); err != nil {
+        return nil, err
+    }
+    return key, nil
+}
+
+// WrapKey: шифрует ключ хранилища ключом, выведенным из мастер-пароля.
+func WrapKey(kek, dataKey []byte) ([]byte, error) {
+    return EncryptAESGCM(kek, dataKey)
+}
+
+// UnwrapKey: обратная операция; GCM-тег заодно проверяет kek.
+func UnwrapKey(kek, wrapped []byte) ([]byte, error) {
+    key, err := DecryptAESGCM(kek, wrapped)
+    if err != nil {
+        return nil, errors.New("unwrap vault key: authentication failed")
+    }
+    if len(key) != dataKeyLength {
+        return nil, errors.New("unwrap vault key: invalid key length")
+    }
+    return key, nil
+}
+
+// KeyedHash: HMAC-SHA256 от msg под подключом, выведенным из ключа хранилища
+// для purpose, — сам ключ шифрования в HMAC не участвует.
+func KeyedHash(key []byte, purpose string, msg []byte) []byte {
+    sub := hmac.New(sha256.New, key)
+    sub.Write([]byte("pm/keyed-hash/" + purpose))
+    mac := hmac.New(sha256.New, sub.Sum(nil))
+    mac.Write(msg)
+    return mac.Sum(nil)
+}
@@ -3,7 +3,10 @@ package security
 
 import (
     "crypto/sha256"
+    "errors"
+    "fmt"
 
+    "golang.org/x/crypto/argon2"
     "golang.org/x/crypto/pbkdf2"
 )
 
@@ -12,7 +15,104 @@ const (
     derivedKeyLength = 32 // AES-256
 )
 
+// Алгоритмы KDF, как они записываются в meta.kdf_algorithm
+const (
+    KDFPBKDF2SHA256 = "pbkdf2-sha256"
+    KDFArgon2id     = "argon2id"
+)
+
+// KDFParams: параметры вывода ключа конкретного хранилища.
+// Для PBKDF2 используется только Iterations; Memory задаётся в KiB.
+type KDFParams struct {
+    Algorithm   string
+    Iterations  uint32
+    Memory      uint32
+    Parallelism uint8
+}
+
+// Пределы параметров: дороже ключ не выводится ни для хранилища, ни для
+// нашего зашифрованного экспорта — иначе чужой файл заставит считать часами
+// или выделить гигабайты памяти.
+const (
+    MaxArgon2Iterations  = 16
+    MaxArgon2Memory      = 1 << 20 // KiB, 1 GiB
+    MaxArgon2Parallelism = 16
+    MaxPBKDF2Iterations  = 10_000_000
+)
+
+// Пределы для баз других менеджеров (импорт KeePass). Шире, чем для
+// хранилищ: KeePassXC подбирает число итераций по секундному замеру,
+// а параллелизм — по числу ядер. Но и они конечны, чтобы файл не занял
+// сервер надолго.
+const (
+    MaxImportArgon2Iterations  = 256
+    MaxImportArgon2Memory      = 2 << 20     // KiB, 2 GiB
+    MaxImportArgon2Parallelism = 255         // больше argon2 не принимает
+    MaxAESKDFRounds            = 300_000_000 // AES-KDF старых баз: около 10 с
+)
+
+// ErrKDFLimits: параметры выходят за пределы выше
+var ErrKDFLimits = errors.New("kdf parameters out of range")
+
+// LegacyKDFParams: то, чем выводились ключи до появления параметров в meta.
+func LegacyKDFParams() KDFParams {
+    return KDFParams{Algorithm: KDFPBKDF2SHA256, Iterations: pbkdf2Iterations}
+}
+
+// DefaultKDFParams: Argon2id по рекомендации RFC 9106 (t=3, m=64 MiB, p=4).
+func DefaultKDFParams() KDFParams {
+    return KDFParams{Algorithm: KDFArgon2id, Iterations: 3, Memory: 64 * 1024, Parallelism: 4}
+}
+
+// WeakerThan: true, если параметры стоит поднять до target.
+func (p KDFParams) WeakerThan(target KDFParams) bool {
+    if p.Algorithm != target.Algorithm {
+        return target.Algorithm == KDFArgon2id
+    }
+    return p.Iterations < target.Iterations ||
+        p.Memory < target.Memory ||
+        p.Parallelism < target.Parallelism
+}
+
+// CheckLimits: ErrKDFLimits, если вывод ключа с такими параметрами дороже допустимого.
+func (p KDFParams) CheckLimits() error {
+    switch p.Algorithm {
+    case KDFPBKDF2SHA256:
+        if p.Iterations > MaxPBKDF2Iterations {
+            return ErrKDFLimits
+        }
+    case KDFArgon2id:
+        if p.Iterations > MaxArgon2Iterations || p.Memory > MaxArgon2Memory || p.Parallelism > MaxArgon2Parallelism {
+            return ErrKDFLimits
+        }
+    default:
+        return fmt.Errorf("unknown kdf algorithm %q", p.Algorithm)
+    }
+    return nil
+}
+
 // DeriveKey: ключ из пароля и соли через PBKDF2-SHA256.
 func DeriveKey(password, salt []byte) []byte {
     return pbkdf2.Key(password, salt, pbkdf2Iterations, derivedKeyLength, sha256.New)
 }
+
+// DeriveKeyWithParams: ключ из пароля и соли по параметрам хранилища.
+func DeriveKeyWithParams(password, salt []byte, p KDFParams) ([]byte, error) {
+    if err := p.CheckLimits(); err != nil {
+        return nil, err
+    }
+    switch p.Algorithm {
+    case KDFPBKDF2SHA256:
+        if p.Iterations == 0 {
+            return nil, fmt.Errorf("kdf %s: zero iterations", p.Algorithm)
+        }
+        return pbkdf2.Key(password, salt, int(p.Iterations), derivedKeyLength, sha256.New), nil
+    case KDFArgon2id:
+        if p.Iterations == 0 || p.Memory == 0 || p.Parallelism == 0 {
+            return nil, fmt.Errorf("kdf %s: invalid parameters", p.Algorithm)
+        }
+        return argon2.IDKey(password, salt, p.Iterations, p.Memory, p.Parallelism, derivedKeyLength), nil
+    default:
+        return nil, fmt.Errorf("unknown kdf algorithm %q", p.Algorithm)
+    }
+}
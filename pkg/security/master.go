@@ -8,90 +8,160 @@ import (
     "fmt"
 )
 
-// LoadOrInitMasterFromDB:
-// - Если в таблице meta нет записи — создаёт соль, выводит ключ из введённого пароля,
-//   сохраняет соль и верификатор, возвращает key.
-// - Если запись есть — проверяет пароль, возвращает key при успехе.
-func LoadOrInitMasterFromDB(db *sql.DB, masterPassword string) ([]byte, error) {
-    // создаём таблицу meta при необходимости
-    _, err := db.Exec(`CREATE TABLE IF NOT EXISTS meta (
-        id INTEGER PRIMARY KEY CHECK (id = 1),
-        salt TEXT NOT NULL,
-        verifier TEXT NOT NULL
-    )`)
-    if err != nil {
-        return nil, err
-    }
+// ErrInvalidMasterPassword: пароль не совпал с верификатором.
+var ErrInvalidMasterPassword = errors.New("invalid master password")
 
-    var saltB64, verB64 string
-    err = db.QueryRow("SELECT salt, verifier FROM meta WHERE id=1").Scan(&saltB64, &verB64)
-    if err == sql.ErrNoRows {
-        // первый запуск
-        salt := GenerateSalt(16)
-        key := DeriveKey([]byte(masterPassword), salt)
-        ver := sha256.Sum256(key)
+// MasterRecord: строка meta — соль, верификатор, параметры KDF
+// и обёрнутый ключ хранилища (nil у хранилищ до envelope-шифрования).
+type MasterRecord struct {
+    Salt       []byte
+    Verifier   []byte
+    KDF        KDFParams
+    WrappedKey []byte
+}
 
-        _, err = db.Exec(
-            "INSERT INTO meta (id, salt, verifier) VALUES (1, ?, ?)",
-            base64.StdEncoding.EncodeToString(salt),
-            base64.StdEncoding.EncodeToString(ver[:]),
-        )
-        if err != nil {
-            return nil, err
-        }
-        return key, nil
+// rowQuerier: *sql.DB или *sql.Tx.
+type rowQuerier interface {
+    QueryRow(query string, args ...any) *sql.Row
+}
+
+// execer: *sql.DB или *sql.Tx.
+type execer interface {
+    Exec(query string, args ...any) (sql.Result, error)
+}
+
+// metaStore: чтение и запись meta — *sql.DB или *sql.Tx.
+type metaStore interface {
+    rowQuerier
+    execer
+}
+
+// NewMasterRecord: новая соль, ключ из пароля по params и обёртка dataKey.
+// Возвращает запись и выведенный ключ (kek).
+func NewMasterRecord(password string, params KDFParams, dataKey []byte) (*MasterRecord, []byte, error) {
+    salt := GenerateSalt(16)
+    kek, err := DeriveKeyWithParams([]byte(password), salt, params)
+    if err != nil {
+        return nil, nil, err
     }
+    wrapped, err := WrapKey(kek, dataKey)
+    if err != nil {
+        return nil, nil, err
+    }
+    ver := sha256.Sum256(kek)
+    return &MasterRecord{Salt: salt, Verifier: ver[:], KDF: params, WrappedKey: wrapped}, kek, nil
+}
+
+// LoadMasterRecord читает meta; если записи нет — возвращает sql.ErrNoRows.
+func LoadMasterRecord(q rowQuerier) (*MasterRecord, error) {
+    var saltB64, verB64, algo string
+    var wrappedB64 sql.NullString
+    var iterations, memory, parallelism int64
+    err := q.QueryRow(
+        "SELECT salt, verifier, kdf_algorithm, kdf_iterations, kdf_memory, kdf_parallelism, wrapped_key FROM meta WHERE id=1",
+    ).Scan(&saltB64, &verB64, &algo, &iterations, &memory, &parallelism, &wrappedB64)
     if err != nil {
         return nil, err
     }
 
-    // повторный запуск: проверяем пароль
     salt, err := base64.StdEncoding.DecodeString(saltB64)
     if err != nil {
         return nil, err
     }
-    expectedVer, err := base64.StdEncoding.DecodeString(verB64)
+    ver, err := base64.StdEncoding.DecodeString(verB64)
     if err != nil {
         return nil, err
     }
+    var wrapped []byte
+    if wrappedB64.Valid && wrappedB64.String != "" {
+        if wrapped, err = base64.StdEncoding.DecodeString(wrappedB64.String); err != nil {
+            return nil, err
+        }
+    }
+    return &MasterRecord{
+        Salt:     salt,
+        Verifier: ver,
+        KDF: KDFParams{
+            Algorithm:   algo,
+            Iterations:  uint32(iterations),
+            Memory:      uint32(memory),
+            Parallelism: uint8(parallelism),
+        },
+        WrappedKey: wrapped,
+    }, nil
+}
+
+// SaveMasterRecord: вставляет или перезаписывает meta целиком.
+func SaveMasterRecord(e execer, r *MasterRecord) error {
+    var wrapped any
+    if r.WrappedKey != nil {
+        wrapped = base64.StdEncoding.EncodeToString(r.WrappedKey)
+    }
+    _, err := e.Exec(
+        `INSERT INTO meta (id, salt, verifier, kdf_algorithm, kdf_iterations, kdf_memory, kdf_parallelism, wrapped_key)
+         VALUES (1, ?, ?, ?, ?, ?, ?, ?)
+         ON CONFLICT(id) DO UPDATE SET salt=excluded.salt, verifier=excluded.verifier,
+             kdf_algorithm=excluded.kdf_algorithm, kdf_iterations=excluded.kdf_iterations,
+             kdf_memory=excluded.kdf_memory, kdf_parallelism=excluded.kdf_parallelism,
+             wrapped_key=excluded.wrapped_key`,
+        base64.StdEncoding.EncodeToString(r.Salt),
+        base64.StdEncoding.EncodeToString(r.Verifier),
+        r.KDF.Algorithm, r.KDF.Iterations, r.KDF.Memory, r.KDF.Parallelism,
+        wrapped,
+    )
+    return err
+}
 
-    key := DeriveKey([]byte(masterPassword), salt)
-    actualVer := sha256.Sum256(key)
-    if !BytesEqual(actualVer[:], expectedVer) {
-        return nil, errors.New("invalid master password")
+// Verify выводит ключ из пароля и сверяет его с верификатором.
+func (r *MasterRecord) Verify(password string) ([]byte, error) {
+    key, err := DeriveKeyWithParams([]byte(password), r.Salt, r.KDF)
+    if err != nil {
+        return nil, err
+    }
+    ver := sha256.Sum256(key)
+    if !BytesEqual(ver[:], r.Verifier) {
+        return nil, ErrInvalidMasterPassword
     }
     return key, nil
 }
 
-// ChangeMasterPassword обновляет верификатор под новый пароль.
-// Перешифровку данных выполняй снаружи, используя oldKey и newKey.
-func ChangeMasterPassword(db *sql.DB, oldPassword, newPassword string) ([]byte, []byte, error) {
-    var saltB64, verB64 string
-    if err := db.QueryRow("SELECT salt, verifier FROM meta WHERE id=1").Scan(&saltB64, &verB64); err != nil {
-        return nil, nil, fmt.Errorf("master not initialized: %w", err)
+// DataKey разворачивает ключ хранилища; nil, nil — хранилище ещё без обёрнутого ключа.
+func (r *MasterRecord) DataKey(kek []byte) ([]byte, error) {
+    if r.WrappedKey == nil {
+        return nil, nil
     }
+    return UnwrapKey(kek, r.WrappedKey)
+}
 
-    salt, err := base64.StdEncoding.DecodeString(saltB64)
+// ChangeMasterPassword перевыпускает обёртку ключа хранилища под новый пароль.
+// Записи не перешифровываются: ключ хранилища остаётся прежним,
+// меняются только соль, параметры KDF, верификатор и wrapped_key — одним UPDATE.
+// q — *sql.DB или *sql.Tx, если смена входит в транзакцию побольше.
+func ChangeMasterPassword(q metaStore, oldPassword, newPassword string) error {
+    rec, err := LoadMasterRecord(q)
     if err != nil {
-        return nil, nil, err
+        return fmt.Errorf("master not initialized: %w", err)
     }
 
-    oldKey := DeriveKey([]byte(oldPassword), salt)
-    oldVer := sha256.Sum256(oldKey)
-
-    expectedVer, err := base64.StdEncoding.DecodeString(verB64)
+    oldKek, err := rec.Verify(oldPassword)
     if err != nil {
-        return nil, nil, err
+        return fmt.Errorf("wrong old master password: %w", err)
     }
-    if !BytesEqual(oldVer[:], expectedVer) {
-        return nil, nil, errors.New("wrong old master password")
+    dataKey, err := rec.DataKey(oldKek)
+    if err != nil {
+        return err
+    }
+    if dataKey == nil {
+        return errors.New("vault key is not wrapped yet: unlock the vault once before changing the master password")
     }
 
-    newKey := DeriveKey([]byte(newPassword), salt)
-    newVer := sha256.Sum256(newKey)
-    _, err = db.Exec("UPDATE meta SET verifier=? WHERE id=1", base64.StdEncoding.EncodeToString(newVer[:]))
+    params := rec.KDF
+    if params.WeakerThan(DefaultKDFParams()) {
+        params = DefaultKDFParams()
+    }
+    next, _, err := NewMasterRecord(newPassword, params, dataKey)
     if err != nil {
-        return nil, nil, err
+        return err
     }
-    return oldKey, newKey, nil
+    return SaveMasterRecord(q, next)
 }
@@ -0,0 +1,540 @@
+// strength.go
+package security
+
+import (
+    "fmt"
+    "math"
+    "strings"
+    "time"
+    "unicode"
+)
+
+// Оценка стойкости пароля в духе zxcvbn: пароль разбирается на шаблоны
+// (strength_match.go), для каждого считается число попыток перебора, затем
+// выбирается разбиение с минимальным итогом. Оценка 0–4, время взлома и подсказки.
+
+const (
+    MinStrengthScore = 3   // ниже — пароль слабый (GUI и REST)
+    maxStrengthRunes = 100 // длиннее — оценивается начало; этого и так с запасом
+
+    minGuessesBeforeGrowingSequence = 10000
+    minSubmatchGuessesSingleChar    = 10
+    minSubmatchGuessesMultiChar     = 50
+    bruteforceCardinality           = 10
+    minYearSpace                    = 20
+    maxGuesses                      = 1e100 // без +Inf: результат уходит в JSON
+)
+
+// Hint — предупреждение или совет; текст стабилен, GUI переводит его по таблице
+type Hint string
+
+const (
+    HintTopTen          Hint = "This is a top-10 common password"
+    HintTopHundred      Hint = "This is a top-100 common password"
+    HintVeryCommon      Hint = "This is a very common password"
+    HintSimilarToCommon Hint = "This is similar to a commonly used password"
+    HintWordByItself    Hint = "A word by itself is easy to guess"
+    HintNamesByThemself Hint = "Names and surnames by themselves are easy to guess"
+    HintCommonNames     Hint = "Common names and surnames are easy to guess"
+    HintUserInputs      Hint = "This contains the service name or username"
+    HintStraightRows    Hint = "Straight rows of keys are easy to guess"
+    HintKeyboardPattern Hint = "Short keyboard patterns are easy to guess"
+    HintRepeatChar      Hint = "Repeats like \"aaa\" are easy to guess"
+    HintRepeatPattern   Hint = "Repeats like \"abcabcabc\" are only slightly harder to guess than \"abc\""
+    HintSequence        Hint = "Sequences like abc or 6543 are easy to guess"
+    HintRecentYears     Hint = "Recent years are easy to guess"
+    HintDates           Hint = "Dates are often easy to guess"
+
+    HintUseWords         Hint = "Use a few words, avoid common phrases"
+    HintNoNeedForSymbols Hint = "No need for symbols, digits, or uppercase letters"
+    HintAddWord          Hint = "Add another word or two. Uncommon words are better."
+    HintCapitalization   Hint = "Capitalization doesn't help very much"
+    HintAllUppercase     Hint = "All-uppercase is almost as easy to guess as all-lowercase"
+    HintReversedWords    Hint = "Reversed words aren't much harder to guess"
+    HintL33t             Hint = "Predictable substitutions like '@' instead of 'a' don't help very much"
+    HintLongerKeyboard   Hint = "Use a longer keyboard pattern with more turns"
+    HintAvoidRepeats     Hint = "Avoid repeated words and characters"
+    HintAvoidSequences   Hint = "Avoid sequences"
+    HintAvoidYears       Hint = "Avoid recent years and years that are associated with you"
+    HintAvoidDates       Hint = "Avoid dates and years that are associated with you"
+)
+
+// CrackTimes — время перебора в секундах для разных сценариев атаки
+type CrackTimes struct {
+    OnlineThrottled   float64 `json:"online_throttled"`   // 100 попыток в час
+    OnlineUnthrottled float64 `json:"online_unthrottled"` // 10 в секунду
+    OfflineSlowHash   float64 `json:"offline_slow_hash"`  // 1e4 в секунду: bcrypt, argon2
+    OfflineFastHash   float64 `json:"offline_fast_hash"`  // 1e10 в секунду: быстрый хеш на GPU
+}
+
+type StrengthFeedback struct {
+    Warning     Hint   `json:"warning,omitempty"`
+    Suggestions []Hint `json:"suggestions"`
+}
+
+// StrengthPattern — фрагмент пароля и как он угадывается
+type StrengthPattern struct {
+    Pattern    string  `json:"pattern"` // dictionary, spatial, repeat, sequence, year, date, bruteforce
+    Token      string  `json:"token"`
+    Guesses    float64 `json:"guesses"`
+    Dictionary string  `json:"dictionary,omitempty"`
+    Rank       int     `json:"rank,omitempty"`
+    L33t       bool    `json:"l33t,omitempty"`
+    Reversed   bool    `json:"reversed,omitempty"`
+}
+
+type Strength struct {
+    Score        int               `json:"score"` // 0 — угадывается сразу, 4 — стойкий
+    Guesses      float64           `json:"guesses"`
+    GuessesLog10 float64           `json:"guesses_log10"`
+    CrackTimes   CrackTimes        `json:"crack_times_seconds"`
+    CrackDisplay string            `json:"crack_time_display"` // для offline_slow_hash
+    Feedback     StrengthFeedback  `json:"feedback"`
+    Sequence     []StrengthPattern `json:"sequence"`
+}
+
+func (s Strength) Weak() bool {
+    return s.Score < MinStrengthScore
+}
+
+// MainHint — главное, что не так с паролем: предупреждение или первый совет
+func (s Strength) MainHint() Hint {
+    if s.Feedback.Warning != "" {
+        return s.Feedback.Warning
+    }
+    if len(s.Feedback.Suggestions) > 0 {
+        return s.Feedback.Suggestions[0]
+    }
+    return ""
+}
+
+// Reason — MainHint для сообщений об ошибке
+func (s Strength) Reason() string {
+    if h := s.MainHint(); h != "" {
+        return string(h)
+    }
+    return "estimated crack time: " + s.CrackDisplay
+}
+
+// EstimateStrength оценивает пароль; userInputs — то, что атакующий знает о записи
+// (сервис, логин, ссылка): совпадения с ними угадываются первыми.
+func EstimateStrength(password string, userInputs ...string) Strength {
+    pw := []rune(password)
+    if len(pw) > maxStrengthRunes {
+        pw = pw[:maxStrengthRunes]
+    }
+    best := mostGuessable(pw, userInputsDict(userInputs))
+
+    s := Strength{Guesses: best.guesses, GuessesLog10: math.Log10(best.guesses)}
+    s.Score = guessesToScore(best.guesses)
+    s.CrackTimes = CrackTimes{
+        OnlineThrottled:   best.guesses / (100.0 / 3600),
+        OnlineUnthrottled: best.guesses / 10,
+        OfflineSlowHash:   best.guesses / 1e4,
+        OfflineFastHash:   best.guesses / 1e10,
+    }
+    s.CrackDisplay = DisplayCrackTime(s.CrackTimes.OfflineSlowHash)
+    s.Feedback = strengthFeedback(s.Score, best.sequence, len(pw))
+    for _, m := range best.sequence {
+        s.Sequence = append(s.Sequence, StrengthPattern{
+            Pattern: m.pattern, Token: m.token, Guesses: m.guesses,
+            Dictionary: m.dictionary, Rank: m.rank, L33t: len(m.l33t) > 0, Reversed: m.reversed,
+        })
+    }
+    return s
+}
+
+func guessesToScore(g float64) int {
+    const delta = 5
+    switch {
+    case g < 1e3+delta:
+        return 0
+    case g < 1e6+delta:
+        return 1
+    case g < 1e8+delta:
+        return 2
+    case g < 1e10+delta:
+        return 3
+    }
+    return 4
+}
+
+// Единицы времени взлома; CrackTimeUnit отдаёт число и единицу, GUI переводит их сам
+const (
+    CrackInstant   = "instant" // меньше секунды
+    CrackSeconds   = "second"
+    CrackMinutes   = "minute"
+    CrackHours     = "hour"
+    CrackDays      = "day"
+    CrackMonths    = "month"
+    CrackYears     = "year"
+    CrackCenturies = "centuries"
+)
+
+func CrackTimeUnit(seconds float64) (int64, string) {
+    const (
+        minute  = 60.0
+        hour    = minute * 60
+        day     = hour * 24
+        month   = day * 31
+        year    = month * 12
+        century = year * 100
+    )
+    round := func(v float64) int64 { return int64(math.Round(v)) }
+    switch {
+    case seconds < 1:
+        return 0, CrackInstant
+    case seconds < minute:
+        return round(seconds), CrackSeconds
+    case seconds < hour:
+        return round(seconds / minute), CrackMinutes
+    case seconds < day:
+        return round(seconds / hour), CrackHours
+    case seconds < month:
+        return round(seconds / day), CrackDays
+    case seconds < year:
+        return round(seconds / month), CrackMonths
+    case seconds < century:
+        return round(seconds / year), CrackYears
+    }
+    return 0, CrackCenturies
+}
+
+// DisplayCrackTime: «3 hours», «centuries» — для REST
+func DisplayCrackTime(seconds float64) string {
+    n, unit := CrackTimeUnit(seconds)
+    switch unit {
+    case CrackInstant:
+        return "less than a second"
+    case CrackCenturies:
+        return "centuries"
+    }
+    if n != 1 {
+        unit += "s"
+    }
+    return fmt.Sprintf("%d %s", n, unit)
+}
+
+func referenceYear() int {
+    return time.Now().Year()
+}
+
+// --- Поиск разбиения с минимальным числом попыток ---
+
+type guessSequence struct {
+    guesses  float64
+    sequence []*strengthMatch
+}
+
+type dpState struct {
+    pi  float64 // произведение попыток по шаблонам
+    seq []*strengthMatch
+}
+
+// mostGuessable: для каждого префикса и числа шаблонов l хранится лучшее pi;
+// итог l!·pi + D^(l-1) штрафует разбиение на много мелких кусков (как в zxcvbn)
+func mostGuessable(pw []rune, user rankedDict) guessSequence {
+    n := len(pw)
+    if n == 0 {
+        return guessSequence{guesses: 1}
+    }
+    byEnd := make([][]*strengthMatch, n)
+    for _, m := range findMatches(pw, user) {
+        m.guesses = matchGuesses(m, n)
+        byEnd[m.j] = append(byEnd[m.j], m)
+    }
+
+    optimal := make([]map[int]dpState, n)
+    total := func(l int, pi float64) float64 {
+        return clampGuesses(factorial(l)*pi + math.Pow(minGuessesBeforeGrowingSequence, float64(l-1)))
+    }
+    update := func(k int, m *strengthMatch, prev *dpState, l int) {
+        pi := m.guesses
+        var seq []*strengthMatch
+        if prev != nil {
+            pi = clampGuesses(prev.pi * m.guesses)
+            seq = append(seq, prev.seq...)
+        }
+        g := total(l, pi)
+        // Разбиение длиннее и не лучше уже найденного короткого не нужно
+        for ol, st := range optimal[k] {
+            if ol <= l && total(ol, st.pi) <= g {
+                return
+            }
+        }
+        if optimal[k] == nil {
+            optimal[k] = map[int]dpState{}
+        }
+        optimal[k][l] = dpState{pi: pi, seq: append(seq, m)}
+    }
+    extend := func(k int, m *strengthMatch) {
+        if m.i == 0 {
+            update(k, m, nil, 1)
+            return
+        }
+        for l, st := range optimal[m.i-1] {
+            if m.pattern == "bruteforce" && st.seq[len(st.seq)-1].pattern == "bruteforce" {
+                continue // два перебора подряд — это один подлиннее
+            }
+            st := st
+            update(k, m, &st, l+1)
+        }
+    }
+
+    for k := 0; k < n; k++ {
+        for _, m := range byEnd[k] {
+            extend(k, m)
+        }
+        for i := 0; i <= k; i++ {
+            bf := &strengthMatch{pattern: "bruteforce", i: i, j: k, token: string(pw[i : k+1])}
+            bf.guesses = matchGuesses(bf, n)
+            extend(k, bf)
+        }
+    }
+
+    best := guessSequence{guesses: math.Inf(1)}
+    for l, st := range optimal[n-1] {
+        if g := total(l, st.pi); g < best.guesses {
+            best = guessSequence{guesses: g, sequence: st.seq}
+        }
+    }
+    return best
+}
+
+func clampGuesses(g float64) float64 {
+    if g > maxGuesses || math.IsNaN(g) {
+        return maxGuesses
+    }
+    return g
+}
+
+func factorial(n int) float64 {
+    f := 1.0
+    for i := 2; i <= n; i++ {
+        f *= float64(i)
+    }
+    return f
+}
+
+func binomial(n, k int) float64 {
+    if k > n || k < 0 {
+        return 0
+    }
+    r := 1.0
+    for i := 1; i <= k; i++ {
+        r = r * float64(n-k+i) / float64(i)
+    }
+    return r
+}
+
+// matchGuesses: сколько попыток нужно, чтобы угадать фрагмент
+func matchGuesses(m *strengthMatch, passwordLen int) float64 {
+    var g float64
+    switch m.pattern {
+    case "bruteforce":
+        g = math.Pow(bruteforceCardinality, float64(m.length()))
+        min := float64(minSubmatchGuessesMultiChar + 1)
+        if m.length() == 1 {
+            min = minSubmatchGuessesSingleChar + 1
+        }
+        g = math.Max(g, min)
+    case "dictionary":
+        g = float64(m.rank) * uppercaseVariations(m.token) * l33tVariations(m)
+        if m.reversed {
+            g *= 2
+        }
+    case "spatial":
+        g = spatialGuesses(m)
+    case "repeat":
+        g = m.baseGuesses * float64(m.repeats)
+    case "sequence":
+        first := []rune(m.token)[0]
+        base := 26.0
+        switch {
+        case strings.ContainsRune("aAzZ019", first):
+            base = 4
+        case unicode.IsDigit(first):
+            base = 10
+        }
+        if !m.ascending {
+            base *= 2
+        }
+        g = base * float64(m.length())
+    case "year":
+        g = float64(max(absInt(m.year-referenceYear()), minYearSpace))
+    case "date":
+        g = float64(max(absInt(m.year-referenceYear()), minYearSpace)) * 365
+        if m.separator != "" {
+            g *= 4
+        }
+    }
+    if m.length() < passwordLen {
+        min := float64(minSubmatchGuessesMultiChar)
+        if m.length() == 1 {
+            min = minSubmatchGuessesSingleChar
+        }
+        g = math.Max(g, min)
+    }
+    return clampGuesses(g)
+}
+
+func uppercaseVariations(token string) float64 {
+    upper, lower := 0, 0
+    for _, c := range token {
+        switch {
+        case unicode.IsUpper(c):
+            upper++
+        case unicode.IsLower(c):
+            lower++
+        }
+    }
+    if upper == 0 {
+        return 1
+    }
+    r := []rune(token)
+    // Типичные варианты: первая заглавная, последняя заглавная, всё заглавными
+    if lower == 0 || (upper == 1 && (unicode.IsUpper(r[0]) || unicode.IsUpper(r[len(r)-1]))) {
+        return 2
+    }
+    v := 0.0
+    for i := 1; i <= min(upper, lower); i++ {
+        v += binomial(upper+lower, i)
+    }
+    return v
+}
+
+func l33tVariations(m *strengthMatch) float64 {
+    if len(m.l33t) == 0 {
+        return 1
+    }
+    v := 1.0
+    lower := strings.ToLower(m.token)
+    for sub, letter := range m.l33t {
+        s, u := strings.Count(lower, string(sub)), strings.Count(lower, string(letter))
+        if s == 0 || u == 0 {
+            v *= 2
+            continue
+        }
+        possible := 0.0
+        for i := 1; i <= min(s, u); i++ {
+            possible += binomial(s+u, i)
+        }
+        v *= possible
+    }
+    return v
+}
+
+func spatialGuesses(m *strengthMatch) float64 {
+    var g *keyboardGraph
+    for _, kg := range keyboardGraphs() {
+        if kg.name == m.graph {
+            g = kg
+        }
+    }
+    L, t := m.length(), m.turns
+    guesses := 0.0
+    for i := 2; i <= L; i++ {
+        for j := 1; j <= min(t, i-1); j++ {
+            guesses += binomial(i-1, j-1) * float64(g.keys) * math.Pow(g.degree, float64(j))
+        }
+    }
+    // Shift на части клавиш
+    if s := m.shifted; s > 0 {
+        u := L - s
+        if u == 0 {
+            guesses *= 2
+        } else {
+            v := 0.0
+            for i := 1; i <= min(s, u); i++ {
+                v += binomial(s+u, i)
+            }
+            guesses *= v
+        }
+    }
+    return guesses
+}
+
+// --- Подсказки ---
+
+func strengthFeedback(score int, seq []*strengthMatch, length int) StrengthFeedback {
+    if length == 0 {
+        return StrengthFeedback{Suggestions: []Hint{HintUseWords, HintNoNeedForSymbols}}
+    }
+    if score >= MinStrengthScore {
+        return StrengthFeedback{Suggestions: []Hint{}}
+    }
+    var longest *strengthMatch
+    for _, m := range seq {
+        if longest == nil || m.length() > longest.length() {
+            longest = m
+        }
+    }
+    fb := StrengthFeedback{Suggestions: []Hint{HintAddWord}}
+    if longest == nil {
+        return fb
+    }
+    sole := len(seq) == 1
+
+    switch longest.pattern {
+    case "dictionary":
+        switch longest.dictionary {
+        case dictPasswords:
+            switch {
+            case sole && len(longest.l33t) == 0 && !longest.reversed && longest.rank <= 10:
+                fb.Warning = HintTopTen
+            case sole && len(longest.l33t) == 0 && !longest.reversed && longest.rank <= 100:
+                fb.Warning = HintTopHundred
+            case sole && len(longest.l33t) == 0 && !longest.reversed:
+                fb.Warning = HintVeryCommon
+            case math.Log10(longest.guesses) <= 4:
+                fb.Warning = HintSimilarToCommon
+            }
+        case dictEnglish:
+            if sole {
+                fb.Warning = HintWordByItself
+            }
+        case dictNames, dictSurnames:
+            fb.Warning = HintCommonNames
+            if sole {
+                fb.Warning = HintNamesByThemself
+            }
+        case dictUserInputs:
+            fb.Warning = HintUserInputs
+        }
+        word := []rune(longest.token)
+        switch {
+        case unicode.IsUpper(word[0]) && strings.ToLower(longest.token[1:]) == longest.token[1:]:
+            fb.Suggestions = append(fb.Suggestions, HintCapitalization)
+        case strings.ToUpper(longest.token) == longest.token && strings.ToLower(longest.token) != longest.token:
+            fb.Suggestions = append(fb.Suggestions, HintAllUppercase)
+        }
+        if longest.reversed && longest.length() >= 4 {
+            fb.Suggestions = append(fb.Suggestions, HintReversedWords)
+        }
+        if len(longest.l33t) > 0 {
+            fb.Suggestions = append(fb.Suggestions, HintL33t)
+        }
+    case "spatial":
+        fb.Warning = HintKeyboardPattern
+        if longest.turns == 1 {
+            fb.Warning = HintStraightRows
+        }
+        fb.Suggestions = append(fb.Suggestions, HintLongerKeyboard)
+    case "repeat":
+        fb.Warning = HintRepeatPattern
+        if len([]rune(longest.base)) == 1 {
+            fb.Warning = HintRepeatChar
+        }
+        fb.Suggestions = append(fb.Suggestions, HintAvoidRepeats)
+    case "sequence":
+        fb.Warning = HintSequence
+        fb.Suggestions = append(fb.Suggestions, HintAvoidSequences)
+    case "year":
+        fb.Warning = HintRecentYears
+        fb.Suggestions = append(fb.Suggestions, HintAvoidYears)
+    case "date":
+        fb.Warning = HintDates
+        fb.Suggestions = append(fb.Suggestions, HintAvoidDates)
+    }
+    return fb
+}
@@ -0,0 +1,519 @@
+// strength_match.go
+package security
+
+import (
+    "bufio"
+    "embed"
+    "regexp"
+    "strconv"
+    "strings"
+    "sync"
+    "unicode"
+)
+
+// Поиск шаблонов в пароле для EstimateStrength: словари (в том числе l33t и задом
+// наперёд), клавиатурные дорожки, повторы, последовательности, даты и годы.
+// Частотные словари — из zxcvbn (MIT, см. dict/LICENSE), слово на строку по убыванию частоты.
+
+//go:embed dict/*.txt
+var dictFS embed.FS
+
+const (
+    dictPasswords  = "passwords"
+    dictEnglish    = "english"
+    dictNames      = "names"
+    dictSurnames   = "surnames"
+    dictUserInputs = "user_inputs"
+
+    maxDictToken = 32 // длиннее слов в словарях нет
+)
+
+type rankedDict map[string]int
+
+var (
+    dictsOnce sync.Once
+    dicts     map[string]rankedDict
+)
+
+func rankedDicts() map[string]rankedDict {
+    dictsOnce.Do(func() {
+        dicts = map[string]rankedDict{}
+        for _, name := range []string{dictPasswords, dictEnglish, dictNames, dictSurnames} {
+            f, err := dictFS.Open("dict/" + name + ".txt")
+            if err != nil {
+                continue
+            }
+            d := rankedDict{}
+            sc := bufio.NewScanner(f)
+            for sc.Scan() {
+                if w := sc.Text(); w != "" {
+                    if _, ok := d[w]; !ok {
+                        d[w] = len(d) + 1
+                    }
+                }
+            }
+            f.Close()
+            dicts[name] = d
+        }
+    })
+    return dicts
+}
+
+// userInputsDict: название сервиса, логин, ссылка — целиком и по словам
+func userInputsDict(inputs []string) rankedDict {
+    d := rankedDict{}
+    add := func(w string) {
+        if w = strings.ToLower(w); len([]rune(w)) >= 3 {
+            if _, ok := d[w]; !ok {
+                d[w] = len(d) + 1
+            }
+        }
+    }
+    for _, in := range inputs {
+        add(in)
+        for _, w := range strings.FieldsFunc(in, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+            add(w)
+        }
+    }
+    return d
+}
+
+type strengthMatch struct {
+    pattern string
+    i, j    int // руны [i, j] включительно
+    token   string
+    guesses float64
+
+    // dictionary
+    dictionary string
+    rank       int
+    reversed   bool
+    l33t       map[rune]rune // символ в пароле → буква словаря
+
+    // spatial
+    graph   string
+    turns   int
+    shifted int
+
+    // repeat
+    base        string
+    baseGuesses float64
+    repeats     int
+
+    // sequence
+    ascending bool
+
+    // date / year
+    year      int
+    separator string
+}
+
+func (m *strengthMatch) length() int {
+    return m.j - m.i + 1
+}
+
+func findMatches(pw []rune, user rankedDict) []*strengthMatch {
+    var out []*strengthMatch
+    out = append(out, dictionaryMatches(pw, user)...)
+    out = append(out, reverseDictionaryMatches(pw, user)...)
+    out = append(out, l33tMatches(pw, user)...)
+    out = append(out, spatialMatches(pw)...)
+    out = append(out, repeatMatches(pw, user)...)
+    out = append(out, sequenceMatches(pw)...)
+    out = append(out, yearMatches(pw)...)
+    out = append(out, dateMatches(pw)...)
+    return out
+}
+
+func lookupAll(word string, user rankedDict, each func(dict string, rank int)) {
+    for name, d := range rankedDicts() {
+        if rank, ok := d[word]; ok {
+            each(name, rank)
+        }
+    }
+    if rank, ok := user[word]; ok {
+        each(dictUserInputs, rank)
+    }
+}
+
+func dictionaryMatches(pw []rune, user rankedDict) []*strengthMatch {
+    var out []*strengthMatch
+    lower := []rune(strings.ToLower(string(pw)))
+    if len(lower) != len(pw) {
+        lower = pw
+    }
+    for i := range pw {
+        for j := i; j < len(pw) && j-i < maxDictToken; j++ {
+            word := string(lower[i : j+1])
+            lookupAll(word, user, func(dict string, rank int) {
+                out = append(out, &strengthMatch{pattern: "dictionary", i: i, j: j, token: string(pw[i : j+1]), dictionary: dict, rank: rank})
+            })
+        }
+    }
+    return out
+}
+
+func reverseRunes(r []rune) []rune {
+    out := make([]rune, len(r))
+    for i, c := range r {
+        out[len(r)-1-i] = c
+    }
+    return out
+}
+
+func reverseDictionaryMatches(pw []rune, user rankedDict) []*strengthMatch {
+    n := len(pw)
+    var out []*strengthMatch
+    for _, m := range dictionaryMatches(reverseRunes(pw), user) {
+        if m.length() < 2 {
+            continue
+        }
+        m.i, m.j = n-1-m.j, n-1-m.i
+        m.token = string(pw[m.i : m.j+1])
+        m.reversed = true
+        out = append(out, m)
+    }
+    return out
+}
+
+// l33t: символ → возможные буквы
+var l33tTable = map[rune][]rune{
+    '4': {'a'}, '@': {'a'}, '8': {'b'}, '(': {'c'}, '{': {'c'}, '[': {'c'}, '<': {'c'},
+    '3': {'e'}, '6': {'g'}, '9': {'g'}, '1': {'i', 'l'}, '!': {'i'}, '|': {'i', 'l'},
+    '7': {'l', 't'}, '0': {'o'}, '$': {'s'}, '5': {'s'}, '+': {'t'}, '%': {'x'}, '2': {'z'},
+}
+
+const maxL33tVariants = 32
+
+// l33tSubs: все непротиворечивые замены для символов токена (не больше maxL33tVariants)
+func l33tSubs(token []rune) []map[rune]rune {
+    var chars []rune
+    seen := map[rune]bool{}
+    for _, c := range token {
+        if _, ok := l33tTable[c]; ok && !seen[c] {
+            seen[c] = true
+            chars = append(chars, c)
+        }
+    }
+    if len(chars) == 0 {
+        return nil
+    }
+    subs := []map[rune]rune{{}}
+    for _, c := range chars {
+        var next []map[rune]rune
+        for _, s := range subs {
+            for _, letter := range l33tTable[c] {
+                m := map[rune]rune{c: letter}
+                for k, v := range s {
+                    m[k] = v
+                }
+                next = append(next, m)
+                if len(next) >= maxL33tVariants {
+                    break
+                }
+            }
+        }
+        subs = next
+    }
+    return subs
+}
+
+func l33tMatches(pw []rune, user rankedDict) []*strengthMatch {
+    var out []*strengthMatch
+    lower := []rune(strings.ToLower(string(pw)))
+    if len(lower) != len(pw) {
+        lower = pw
+    }
+    for i := range pw {
+        for j := i + 1; j < len(pw) && j-i < maxDictToken; j++ {
+            token := lower[i : j+1]
+            for _, sub := range l33tSubs(token) {
+                word := make([]rune, len(token))
+                used := map[rune]rune{}
+                for k, c := range token {
+                    word[k] = c
+                    if letter, ok := sub[c]; ok {
+                        word[k] = letter
+                        used[c] = letter
+                    }
+                }
+                lookupAll(string(word), user, func(dict string, rank int) {
+                    out = append(out, &strengthMatch{pattern: "dictionary", i: i, j: j, token: string(pw[i : j+1]), dictionary: dict, rank: rank, l33t: used})
+                })
+            }
+        }
+    }
+    return out
+}
+
+// Клавиатуры: у каждой клавиши два символа — без Shift и с ним.
+// Ряды qwerty скошены, соседи — слева, сверху, сверху-справа, справа, снизу, снизу-слева.
+type keyboardGraph struct {
+    name      string
+    adjacency map[rune][]string // соседние клавиши по направлениям ("" — нет)
+    shifted   map[rune]bool     // символ набирается с Shift
+    keys      int               // стартовые позиции
+    degree    float64           // среднее число соседей
+}
+
+var (
+    graphsOnce sync.Once
+    graphs     []*keyboardGraph
+)
+
+func keyboardGraphs() []*keyboardGraph {
+    graphsOnce.Do(func() {
+        graphs = []*keyboardGraph{
+            buildGraph("qwerty", []string{
+                "`~ 1! 2@ 3# 4$ 5% 6^ 7& 8* 9( 0) -_ =+",
+                "qQ wW eE rR tT yY uU iI oO pP [{ ]} \\|",
+                "aA sS dD fF gG hH jJ kK lL ;: '\"",
+                "zZ xX cC vV bB nN mM ,< .> /?",
+            }, []int{0, 1, 1, 1}, true),
+            buildGraph("keypad", []string{
+                "__ /_ *_ -_",
+                "7_ 8_ 9_ +_",
+                "4_ 5_ 6_",
+                "1_ 2_ 3_",
+                "__ 0_ ._",
+            }, []int{0, 0, 0, 0, 0}, false),
+        }
+    })
+    return graphs
+}
+
+func buildGraph(name string, rows []string, offsets []int, slanted bool) *keyboardGraph {
+    type pos struct{ x, y int }
+    keyAt := map[pos]string{}
+    for y, row := range rows {
+        for x, key := range strings.Fields(row) {
+            if key != "__" {
+                keyAt[pos{x + offsets[y], y}] = strings.ReplaceAll(key, "_", "")
+            }
+        }
+    }
+    dirs := []pos{{-1, 0}, {0, -1}, {1, -1}, {1, 0}, {0, 1}, {-1, 1}}
+    if !slanted {
+        dirs = []pos{{-1, 0}, {-1, -1}, {0, -1}, {1, -1}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}}
+    }
+    g := &keyboardGraph{name: name, adjacency: map[rune][]string{}, shifted: map[rune]bool{}}
+    neighbours := 0
+    for p, key := range keyAt {
+        adj := make([]string, len(dirs))
+        for d, dp := range dirs {
+            if k, ok := keyAt[pos{p.x + dp.x, p.y + dp.y}]; ok {
+                adj[d] = k
+                neighbours++
+            }
+        }
+        for k, c := range []rune(key) {
+            g.adjacency[c] = adj
+            g.shifted[c] = k > 0
+        }
+    }
+    g.keys = len(g.adjacency)
+    g.degree = float64(neighbours) / float64(len(keyAt))
+    return g
+}
+
+func spatialMatches(pw []rune) []*strengthMatch {
+    var out []*strengthMatch
+    for _, g := range keyboardGraphs() {
+        i := 0
+        for i < len(pw)-1 {
+            j := i + 1
+            lastDir, turns, shifted := -1, 0, 0
+            if g.shifted[pw[i]] {
+                shifted++
+            }
+            for j < len(pw) {
+                dir, isShifted := -1, false
+                for d, key := range g.adjacency[pw[j-1]] {
+                    if idx := strings.IndexRune(key, pw[j]); key != "" && idx >= 0 {
+                        dir, isShifted = d, idx > 0
+                        break
+                    }
+                }
+                if dir < 0 {
+                    break
+                }
+                if isShifted {
+                    shifted++
+                }
+                if dir != lastDir {
+                    turns++
+                    lastDir = dir
+                }
+                j++
+            }
+            if j-i > 2 {
+                out = append(out, &strengthMatch{pattern: "spatial", i: i, j: j - 1, token: string(pw[i:j]), graph: g.name, turns: turns, shifted: shifted})
+            }
+            i = j
+        }
+    }
+    return out
+}
+
+// repeatMatches: самый длинный отрезок из повторений одного куска; из равных — с коротким куском
+func repeatMatches(pw []rune, user rankedDict) []*strengthMatch {
+    var out []*strengthMatch
+    i := 0
+    for i < len(pw) {
+        bestBase, bestCount := 0, 0
+        for b := 1; i+2*b <= len(pw); b++ {
+            count := 1
+            for i+(count+1)*b <= len(pw) && string(pw[i+count*b:i+(count+1)*b]) == string(pw[i:i+b]) {
+                count++
+            }
+            if count > 1 && b*count > bestBase*bestCount {
+                bestBase, bestCount = b, count
+            }
+        }
+        if bestCount < 2 {
+            i++
+            continue
+        }
+        span := bestBase * bestCount
+        base := pw[i : i+bestBase]
+        out = append(out, &strengthMatch{
+            pattern: "repeat", i: i, j: i + span - 1, token: string(pw[i : i+span]),
+            base: string(base), baseGuesses: mostGuessable(base, user).guesses, repeats: bestCount,
+        })
+        i += span
+    }
+    return out
+}
+
+// sequenceMatches: abc, 6543, ACE — постоянный шаг от 1 до 5, не короче трёх символов
+func sequenceMatches(pw []rune) []*strengthMatch {
+    var out []*strengthMatch
+    emit := func(i, j, delta int) {
+        if j-i < 2 || delta == 0 || delta > 5 || delta < -5 {
+            return
+        }
+        out = append(out, &strengthMatch{pattern: "sequence", i: i, j: j, token: string(pw[i : j+1]), ascending: delta > 0})
+    }
+    if len(pw) < 3 {
+        return nil
+    }
+    i, delta := 0, int(pw[1]-pw[0])
+    for k := 2; k < len(pw); k++ {
+        if d := int(pw[k] - pw[k-1]); d != delta {
+            emit(i, k-1, delta)
+            i, delta = k-1, d
+        }
+    }
+    emit(i, len(pw)-1, delta)
+    return out
+}
+
+const (
+    dateMinYear = 1000
+    dateMaxYear = 2050
+)
+
+var yearRe = regexp.MustCompile(`1[89]\d\d|20\d\d`)
+
+func yearMatches(pw []rune) []*strengthMatch {
+    var out []*strengthMatch
+    s := string(pw)
+    for _, loc := range yearRe.FindAllStringIndex(s, -1) {
+        i := len([]rune(s[:loc[0]]))
+        token := s[loc[0]:loc[1]]
+        year, _ := strconv.Atoi(token)
+        out = append(out, &strengthMatch{pattern: "year", i: i, j: i + len(token) - 1, token: token, year: year})
+    }
+    return out
+}
+
+var dateSepRe = regexp.MustCompile(`^(\d{1,4})([\s/\\_.-])(\d{1,2})([\s/\\_.-])(\d{1,4})$`)
+
+// dateMatches: 1.5.1990, 19900105, 010590 — день, месяц и год в любом из обычных порядков
+func dateMatches(pw []rune) []*strengthMatch {
+    var out []*strengthMatch
+    for i := range pw {
+        for j := i + 3; j < len(pw) && j-i < 10; j++ {
+            token := string(pw[i : j+1])
+            if m := dateSepRe.FindStringSubmatch(token); m != nil && m[2] == m[4] {
+                a, _ := strconv.Atoi(m[1])
+                b, _ := strconv.Atoi(m[3])
+                c, _ := strconv.Atoi(m[5])
+                if year, ok := dateYear([3]int{a, b, c}, [3]int{len(m[1]), len(m[3]), len(m[5])}); ok {
+                    out = append(out, &strengthMatch{pattern: "date", i: i, j: j, token: token, year: year, separator: m[2]})
+                }
+                continue
+            }
+            if j-i > 7 || !allDigits(pw[i:j+1]) {
+                continue
+            }
+            if year, ok := splitDate(token); ok {
+                out = append(out, &strengthMatch{pattern: "date", i: i, j: j, token: token, year: year})
+            }
+        }
+    }
+    return out
+}
+
+func allDigits(r []rune) bool {
+    for _, c := range r {
+        if c < '0' || c > '9' {
+            return false
+        }
+    }
+    return true
+}
+
+// splitDate: разбиения 4–8 цифр на три части; выбирается год, ближайший к текущему
+func splitDate(s string) (int, bool) {
+    splits := map[int][][2]int{
+        4: {{1, 2}, {2, 3}},
+        5: {{1, 3}, {2, 3}},
+        6: {{1, 2}, {2, 4}, {4, 5}},
+        7: {{1, 3}, {2, 3}, {4, 5}, {4, 6}},
+        8: {{2, 4}, {4, 6}},
+    }
+    best, found := 0, false
+    for _, sp := range splits[len(s)] {
+        parts := []string{s[:sp[0]], s[sp[0]:sp[1]], s[sp[1]:]}
+        var v, l [3]int
+        for k, p := range parts {
+            v[k], _ = strconv.Atoi(p)
+            l[k] = len(p)
+        }
+        if year, ok := dateYear(v, l); ok && (!found || absInt(year-referenceYear()) < absInt(best-referenceYear())) {
+            best, found = year, true
+        }
+    }
+    return best, found
+}
+
+// dateYear: год, если части образуют корректную дату (год первым или последним)
+func dateYear(v, l [3]int) (int, bool) {
+    for _, order := range [][3]int{{2, 0, 1}, {2, 1, 0}, {0, 1, 2}, {0, 2, 1}} {
+        y, a, b := v[order[0]], v[order[1]], v[order[2]]
+        if l[order[0]] == 2 {
+            if y > 50 {
+                y += 1900
+            } else {
+                y += 2000
+            }
+        } else if l[order[0]] != 4 {
+            continue
+        }
+        if y < dateMinYear || y > dateMaxYear {
+            continue
+        }
+        if (a >= 1 && a <= 12 && b >= 1 && b <= 31) || (b >= 1 && b <= 12 && a >= 1 && a <= 31) {
+            return y, true
+        }
+    }
+    return 0, false
+}
+
+func absInt(x int) int {
+    if x < 0 {
+        return -x
+    }
+    return x
+}
@@ -0,0 +1,171 @@
+// totp.go
+package security
+
+import (
+    "crypto/hmac"
+    "crypto/sha1"
+    "crypto/sha256"
+    "crypto/sha512"
+    "encoding/base32"
+    "encoding/binary"
+    "errors"
+    "fmt"
+    "hash"
+    "net/url"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// Алгоритмы HMAC для TOTP, как в параметре algorithm у otpauth://
+const (
+    TOTPSHA1   = "SHA1"
+    TOTPSHA256 = "SHA256"
+    TOTPSHA512 = "SHA512"
+)
+
+var ErrInvalidTOTP = errors.New("invalid TOTP secret")
+
+// TOTP: параметры генератора одноразовых кодов (RFC 6238).
+// По умолчанию SHA1, 6 цифр, период 30 секунд.
+type TOTP struct {
+    Secret    []byte
+    Algorithm string
+    Digits    int
+    Period    int
+    Issuer    string
+    Account   string
+}
+
+// ParseTOTP принимает otpauth://totp/... URI или голый base32-секрет
+// (пробелы и регистр не важны, паддинг необязателен).
+func ParseTOTP(s string) (*TOTP, error) {
+    s = strings.TrimSpace(s)
+    if strings.HasPrefix(strings.ToLower(s), "otpauth://") {
+        return parseTOTPURI(s)
+    }
+    secret, err := decodeBase32(s)
+    if err != nil {
+        return nil, err
+    }
+    return &TOTP{Secret: secret, Algorithm: TOTPSHA1, Digits: 6, Period: 30}, nil
+}
+
+func parseTOTPURI(s string) (*TOTP, error) {
+    u, err := url.Parse(s)
+    if err != nil {
+        return nil, fmt.Errorf("%w: %v", ErrInvalidTOTP, err)
+    }
+    if !strings.EqualFold(u.Host, "totp") {
+        return nil, fmt.Errorf("%w: only otpauth://totp is supported", ErrInvalidTOTP)
+    }
+    q := u.Query()
+
+    secret, err := decodeBase32(q.Get("secret"))
+    if err != nil {
+        return nil, err
+    }
+    t := &TOTP{Secret: secret, Algorithm: TOTPSHA1, Digits: 6, Period: 30}
+
+    // Метка: "Issuer:account" или просто "account"
+    label := strings.TrimPrefix(u.Path, "/")
+    if issuer, account, ok := strings.Cut(label, ":"); ok {
+        t.Issuer, t.Account = strings.TrimSpace(issuer), strings.TrimSpace(account)
+    } else {
+        t.Account = label
+    }
+    if issuer := q.Get("issuer"); issuer != "" {
+        t.Issuer = issuer
+    }
+
+    if alg := q.Get("algorithm"); alg != "" {
+        t.Algorithm = strings.ToUpper(alg)
+        if newTOTPHash(t.Algorithm) == nil {
+            return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidTOTP, alg)
+        }
+    }
+    if d := q.Get("digits"); d != "" {
+        t.Digits, err = strconv.Atoi(d)
+        if err != nil || (t.Digits != 6 && t.Digits != 8) {
+            return nil, fmt.Errorf("%w: digits must be 6 or 8", ErrInvalidTOTP)
+        }
+    }
+    if p := q.Get("period"); p != "" {
+        t.Period, err = strconv.Atoi(p)
+        if err != nil || t.Period <= 0 {
+            return nil, fmt.Errorf("%w: invalid period", ErrInvalidTOTP)
+        }
+    }
+    return t, nil
+}
+
+func decodeBase32(s string) ([]byte, error) {
+    s = strings.ToUpper(strings.ReplaceAll(s, " ", ""))
+    s = strings.TrimRight(s, "=")
+    if s == "" {
+        return nil, fmt.Errorf("%w: empty secret", ErrInvalidTOTP)
+    }
+    secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(s)
+    if err != nil {
+        return nil, fmt.Errorf("%w: secret is not base32", ErrInvalidTOTP)
+    }
+    return secret, nil
+}
+
+func newTOTPHash(alg string) func() hash.Hash {
+    switch alg {
+    case TOTPSHA1:
+        return sha1.New
+    case TOTPSHA256:
+        return sha256.New
+    case TOTPSHA512:
+        return sha512.New
+    }
+    return nil
+}
+
+// URI: каноническое представление — в таком виде секрет и хранится
+func (t *TOTP) URI() string {
+    label := t.Account
+    if t.Issuer != "" {
+        label = t.Issuer + ":" + t.Account
+    }
+    q := url.Values{}
+    q.Set("secret", base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(t.Secret))
+    if t.Issuer != "" {
+        q.Set("issuer", t.Issuer)
+    }
+    q.Set("algorithm", t.Algorithm)
+    q.Set("digits", strconv.Itoa(t.Digits))
+    q.Set("period", strconv.Itoa(t.Period))
+    u := url.URL{Scheme: "otpauth", Host: "totp", Path: "/" + label, RawQuery: q.Encode()}
+    return u.String()
+}
+
+// Code: код для момента at (RFC 6238 поверх HOTP из RFC 4226)
+func (t *TOTP) Code(at time.Time) string {
+    counter := uint64(at.Unix()) / uint64(t.Period)
+    var msg [8]byte
+    binary.BigEndian.PutUint64(msg[:], counter)
+
+    mac := hmac.New(newTOTPHash(t.Algorithm), t.Secret)
+    mac.Write(msg[:])
+    sum := mac.Sum(nil)
+
+    // dynamic truncation
+    offset := sum[len(sum)-1] & 0x0f
+    bin := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+    mod := uint32(1)
+    for i := 0; i < t.Digits; i++ {
+        mod *= 10
+    }
+    return fmt.Sprintf("%0*d", t.Digits, bin%mod)
+}
+
+// Remaining: сколько ещё действителен код, выданный в момент at
+func (t *TOTP) Remaining(at time.Time) time.Duration {
+    period := int64(t.Period)
+    left := period - at.Unix()%period
+    return time.Duration(left) * time.Second
+}
@@ -2,43 +2,335 @@
 package utils
 
 import (
-    "crypto/rand"
     "errors"
-    "math/big"
+    "fmt"
     "strings"
 )
 
+const (
+    UpperChars     = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+    LowerChars     = "abcdefghijklmnopqrstuvwxyz"
+    DigitChars     = "0123456789"
+    DefaultSymbols = "!@#$%^&*()-_=+[]{}<>?/|"
+    // Символы, которые легко спутать при чтении и вводе вручную
+    AmbiguousChars = "0O1lI"
+
+    MaxGeneratedLength = 256
+)
+
+// Для произносимого режима: чередование согласных и гласных
+const (
+    pronounceConsonants = "bcdfghjklmnpqrstvwxz"
+    pronounceVowels     = "aeiouy"
+)
+
+var ErrGeneratorPolicy = errors.New("invalid generator policy")
+
+// GeneratorPolicy — правила генерации пароля. Включённый класс символов
+// встречается в пароле не меньше Min* раз и не меньше одного.
+// Хранится вместе с записью, чтобы пароль перегенерировался по тем же правилам.
+type GeneratorPolicy struct {
+    Length           int    `json:"length"`
+    Upper            bool   `json:"upper"`
+    Lower            bool   `json:"lower"`
+    Digits           bool   `json:"digits"`
+    Symbols          bool   `json:"symbols"`
+    MinUpper         int    `json:"min_upper,omitempty"`
+    MinLower         int    `json:"min_lower,omitempty"`
+    MinDigits        int    `json:"min_digits,omitempty"`
+    MinSymbols       int    `json:"min_symbols,omitempty"`
+    SymbolSet        string `json:"symbol_set,omitempty"` // пусто — DefaultSymbols
+    Exclude          string `json:"exclude,omitempty"`
+    ExcludeAmbiguous bool   `json:"exclude_ambiguous,omitempty"`
+    NoRepeat         bool   `json:"no_repeat,omitempty"` // каждый символ не больше одного раза
+    Pronounceable    bool   `json:"pronounceable,omitempty"`
+}
+
+func DefaultGeneratorPolicy() GeneratorPolicy {
+    return GeneratorPolicy{Length: 16, Upper: true, Lower: true, Digits: true, Symbols: true}
+}
+
+// Прежний интерфейс: все включённые классы теперь гарантированно присутствуют
 func GeneratePassword(length int, useUpper, useLower, useDigits, useSymbols bool, exclude string) (string, error) {
-    var charset string
-    if useUpper {
-        charset += "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+    return GenerateWithPolicy(GeneratorPolicy{
+        Length:  length,
+        Upper:   useUpper,
+        Lower:   useLower,
+        Digits:  useDigits,
+        Symbols: useSymbols,
+        Exclude: exclude,
+    })
+}
+
+type charClass struct {
+    name    string
+    enabled bool
+    min     int
+    chars   string
+}
+
+// classes: наборы после исключений; min уже с учётом «хотя бы один»
+func (p GeneratorPolicy) classes() []charClass {
+    symbols := p.SymbolSet
+    if symbols == "" {
+        symbols = DefaultSymbols
     }
-    if useLower {
-        charset += "abcdefghijklmnopqrstuvwxyz"
+    list := []charClass{
+        {"uppercase", p.Upper, p.MinUpper, UpperChars},
+        {"lowercase", p.Lower, p.MinLower, LowerChars},
+        {"digit", p.Digits, p.MinDigits, DigitChars},
+        {"symbol", p.Symbols, p.MinSymbols, symbols},
     }
-    if useDigits {
-        charset += "0123456789"
+    for i := range list {
+        list[i].chars = p.filter(list[i].chars)
+        if list[i].enabled && list[i].min < 1 {
+            list[i].min = 1
+        }
     }
-    if useSymbols {
-        charset += "!@#$%^&*()-_=+[]{}<>?/|"
+    return list
+}
+
+func (p GeneratorPolicy) excluded(c byte) bool {
+    return strings.IndexByte(p.Exclude, c) >= 0 ||
+        (p.ExcludeAmbiguous && strings.IndexByte(AmbiguousChars, c) >= 0)
+}
+
+func (p GeneratorPolicy) filter(chars string) string {
+    var b strings.Builder
+    for i := 0; i < len(chars); i++ {
+        if !p.excluded(chars[i]) {
+            b.WriteByte(chars[i])
+        }
+    }
+    return b.String()
+}
+
+func (p GeneratorPolicy) Validate() error {
+    if p.Length < 1 || p.Length > MaxGeneratedLength {
+        return fmt.Errorf("%w: length must be between 1 and %d", ErrGeneratorPolicy, MaxGeneratedLength)
+    }
+    for _, r := range p.SymbolSet {
+        if r <= ' ' || r >= 0x7f || strings.ContainsRune(UpperChars+LowerChars+DigitChars, r) {
+            return fmt.Errorf("%w: symbol set must contain printable ASCII symbols only", ErrGeneratorPolicy)
+        }
+    }
+
+    required, enabled, pool := 0, 0, 0
+    for _, c := range p.classes() {
+        if c.min < 0 {
+            return fmt.Errorf("%w: negative minimum for %s", ErrGeneratorPolicy, c.name)
+        }
+        if !c.enabled {
+            if c.min > 0 {
+                return fmt.Errorf("%w: minimum set for disabled class %s", ErrGeneratorPolicy, c.name)
+            }
+            continue
+        }
+        if c.chars == "" {
+            return fmt.Errorf("%w: all %s characters are excluded", ErrGeneratorPolicy, c.name)
+        }
+        if p.NoRepeat && c.min > len(c.chars) {
+            return fmt.Errorf("%w: not enough distinct %s characters", ErrGeneratorPolicy, c.name)
+        }
+        enabled++
+        required += c.min
+        pool += len(c.chars)
+    }
+    if enabled == 0 {
+        return fmt.Errorf("%w: no characters available for generation", ErrGeneratorPolicy)
+    }
+    if required > p.Length {
+        return fmt.Errorf("%w: length %d is shorter than the %d required characters", ErrGeneratorPolicy, p.Length, required)
+    }
+    if p.NoRepeat && p.Length > pool {
+        return fmt.Errorf("%w: length exceeds the %d distinct characters available", ErrGeneratorPolicy, pool)
+    }
+    if p.Pronounceable {
+        if p.NoRepeat {
+            return fmt.Errorf("%w: no_repeat is not supported in pronounceable mode", ErrGeneratorPolicy)
+        }
+        if !p.Upper && !p.Lower {
+            return fmt.Errorf("%w: pronounceable mode needs letters", ErrGeneratorPolicy)
+        }
+    }
+    return nil
+}
+
+// GenerateWithPolicy: сначала обязательные символы каждого класса, остальное — из
+// объединения включённых классов, затем перемешивание
+func GenerateWithPolicy(p GeneratorPolicy) (string, error) {
+    if err := p.Validate(); err != nil {
+        return "", err
+    }
+    if p.Pronounceable {
+        return p.pronounceable()
+    }
+
+    var out []byte
+    used := map[byte]bool{}
+    pick := func(chars string) error {
+        if p.NoRepeat {
+            chars = withoutUsed(chars, used)
+        }
+        if chars == "" {
+            return fmt.Errorf("%w: ran out of distinct characters", ErrGeneratorPolicy)
+        }
+        n, err := randInt(len(chars))
+        if err != nil {
+            return err
+        }
+        used[chars[n]] = true
+        out = append(out, chars[n])
+        return nil
     }
 
-    for _, ch := range exclude {
-        charset = strings.ReplaceAll(charset, string(ch), "")
+    all := ""
+    for _, c := range p.classes() {
+        if !c.enabled {
+            continue
+        }
+        all += c.chars
+        for i := 0; i < c.min; i++ {
+            if err := pick(c.chars); err != nil {
+                return "", err
+            }
+        }
+    }
+    for len(out) < p.Length {
+        if err := pick(all); err != nil {
+            return "", err
+        }
+    }
+    if err := shuffle(out); err != nil {
+        return "", err
     }
+    return string(out), nil
+}
 
-    if len(charset) == 0 {
-        return "", errors.New("no characters available for generation")
+// pronounceable: слоги «согласная-гласная» из строчных букв; затем нужное число
+// заглавных и вставка цифр и символов в случайные места
+func (p GeneratorPolicy) pronounceable() (string, error) {
+    cls := p.classes()
+    upper, lower, digits, symbols := cls[0], cls[1], cls[2], cls[3]
+
+    caseOK := func(c byte) bool {
+        if p.Lower {
+            return !p.excluded(c)
+        }
+        return !p.excluded(c - 'a' + 'A')
+    }
+    consonants, vowels := "", ""
+    for i := 0; i < len(pronounceConsonants); i++ {
+        if caseOK(pronounceConsonants[i]) {
+            consonants += pronounceConsonants[i : i+1]
+        }
+    }
+    for i := 0; i < len(pronounceVowels); i++ {
+        if caseOK(pronounceVowels[i]) {
+            vowels += pronounceVowels[i : i+1]
+        }
+    }
+    if consonants == "" || vowels == "" {
+        return "", fmt.Errorf("%w: too many letters excluded for pronounceable mode", ErrGeneratorPolicy)
     }
 
-    password := make([]byte, length)
-    for i := range password {
-        index, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+    extra := 0
+    if digits.enabled {
+        extra += digits.min
+    }
+    if symbols.enabled {
+        extra += symbols.min
+    }
+    letters := make([]byte, p.Length-extra)
+    start, err := randInt(2)
+    if err != nil {
+        return "", err
+    }
+    for i := range letters {
+        set := consonants
+        if (i+start)%2 == 1 {
+            set = vowels
+        }
+        n, err := randInt(len(set))
         if err != nil {
             return "", err
         }
-        password[i] = charset[index.Int64()]
+        letters[i] = set[n]
     }
 
-    return string(password), nil
+    // Регистр: только заглавные, либо строчные с минимумом заглавных
+    switch {
+    case p.Upper && !p.Lower:
+        for i := range letters {
+            letters[i] = letters[i] - 'a' + 'A'
+        }
+    case p.Upper:
+        var eligible []int
+        for i, c := range letters {
+            if !p.excluded(c - 'a' + 'A') {
+                eligible = append(eligible, i)
+            }
+        }
+        if len(eligible) < upper.min || len(letters)-upper.min < lower.min {
+            return "", fmt.Errorf("%w: cannot place the required uppercase letters", ErrGeneratorPolicy)
+        }
+        if err := shuffleInts(eligible); err != nil {
+            return "", err
+        }
+        for _, i := range eligible[:upper.min] {
+            letters[i] = letters[i] - 'a' + 'A'
+        }
+    }
+
+    out := letters
+    for _, c := range []charClass{digits, symbols} {
+        if !c.enabled {
+            continue
+        }
+        for i := 0; i < c.min; i++ {
+            n, err := randInt(len(c.chars))
+            if err != nil {
+                return "", err
+            }
+            pos, err := randInt(len(out) + 1)
+            if err != nil {
+                return "", err
+            }
+            out = append(out[:pos], append([]byte{c.chars[n]}, out[pos:]...)...)
+        }
+    }
+    return string(out), nil
+}
+
+func withoutUsed(chars string, used map[byte]bool) string {
+    var b strings.Builder
+    for i := 0; i < len(chars); i++ {
+        if !used[chars[i]] {
+            b.WriteByte(chars[i])
+        }
+    }
+    return b.String()
+}
+
+// Фишер — Йетс на crypto/rand
+func shuffle(b []byte) error {
+    for i := len(b) - 1; i > 0; i-- {
+        j, err := randInt(i + 1)
+        if err != nil {
+            return err
+        }
+        b[i], b[j] = b[j], b[i]
+    }
+    return nil
+}
+
+func shuffleInts(a []int) error {
+    for i := len(a) - 1; i > 0; i-- {
+        j, err := randInt(i + 1)
+        if err != nil {
+            return err
+        }
+        a[i], a[j] = a[j], a[i]
+    }
+    return nil
 }
@@ -1,24 +1,115 @@
 package utils
 
 import (
-	"fmt"
+	"errors"
+	"sync"
+	"time"
 
 	"fyne.io/fyne/v2"
 )
 
-// CopyToClipboard: принимает base64-шифртекст и CryptoService,
-// расшифровывает и кладёт в буфер обмена plain-пароль.
-// Пустая строка очищает буфер.
-func CopyToClipboard(encB64 string, cryptoSvc *CryptoService) error {
-    if encB64 == "" {
-        fyne.CurrentApp().Clipboard().SetContent("")
-        return nil
+// Буфер обмена для секретов — один сервис на всё приложение (GUI и REST).
+// Буфер очищается через таймаут, переданный при копировании (настройка того
+// хранилища, из которого копировали), но только если в нём всё ещё наш секрет;
+// новое копирование отменяет прежнюю очистку. При блокировке и выходе — Clear.
+// Системный буфер трогается только в главном потоке Fyne (через fyne.Do).
+
+const (
+    DefaultClipboardTimeout = 15 * time.Second
+    MinClipboardTimeout     = 5 * time.Second
+    MaxClipboardTimeout     = 10 * time.Minute
+)
+
+var ErrNoClipboard = errors.New("clipboard is not available without GUI")
+
+type ClipboardService struct {
+    mu     sync.Mutex
+    secret string // последний скопированный секрет; "" — очищать нечего
+    gen    uint64 // номер копирования: таймер старой копии не трогает новую
+    timer  *time.Timer
+}
+
+var Clipboard = &ClipboardService{}
+
+// ClipboardTimeoutOrDefault: таймаут вне допустимого диапазона заменяется на DefaultClipboardTimeout
+func ClipboardTimeoutOrDefault(d time.Duration) time.Duration {
+    if d < MinClipboardTimeout || d > MaxClipboardTimeout {
+        return DefaultClipboardTimeout
     }
-        
-    plain, err := cryptoSvc.Decrypt(encB64)
-    if err != nil {
-        return fmt.Errorf("decrypt password: %w", err)
+    return d
+}
+
+// CopySecret кладёт секрет (пароль, скрытое поле, код TOTP) и планирует
+// очистку через timeout (см. ClipboardTimeoutOrDefault)
+func (c *ClipboardService) CopySecret(secret string, timeout time.Duration) error {
+    return c.copy(secret, ClipboardTimeoutOrDefault(timeout))
+}
+
+// Copy кладёт обычный текст (ссылку); прежняя очистка отменяется — секрета в буфере уже нет
+func (c *ClipboardService) Copy(text string) error {
+    return c.copy(text, 0)
+}
+
+// copy: timeout 0 — не секрет, очищать не нужно
+func (c *ClipboardService) copy(text string, timeout time.Duration) error {
+    a := fyne.CurrentApp()
+    if a == nil {
+        return ErrNoClipboard
     }
-    fyne.CurrentApp().Clipboard().SetContent(plain)
+
+    c.mu.Lock()
+    c.cancelLocked()
+    if timeout > 0 && text != "" {
+        c.secret = text
+        gen := c.gen
+        c.timer = time.AfterFunc(timeout, func() { c.expire(gen) })
+    }
+    c.mu.Unlock()
+
+    fyne.Do(func() { a.Clipboard().SetContent(text) })
     return nil
 }
+
+// Clear немедленно стирает наш секрет, если пользователь не скопировал ничего поверх
+func (c *ClipboardService) Clear() {
+    c.mu.Lock()
+    secret := c.secret
+    c.cancelLocked()
+    c.mu.Unlock()
+    clearIfUnchanged(secret)
+}
+
+func (c *ClipboardService) cancelLocked() {
+    if c.timer != nil {
+        c.timer.Stop()
+        c.timer = nil
+    }
+    c.secret = ""
+    c.gen++
+}
+
+func (c *ClipboardService) expire(gen uint64) {
+    c.mu.Lock()
+    if gen != c.gen {
+        c.mu.Unlock()
+        return
+    }
+    secret := c.secret
+    c.timer = nil
+    c.secret = ""
+    c.mu.Unlock()
+    clearIfUnchanged(secret)
+}
+
+func clearIfUnchanged(secret string) {
+    a := fyne.CurrentApp()
+    if secret == "" || a == nil {
+        return
+    }
+    fyne.Do(func() {
+        cb := a.Clipboard()
+        if cb.Content() == secret {
+            cb.SetContent("")
+        }
+    })
+}
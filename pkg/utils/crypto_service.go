@@ -2,12 +2,18 @@ package utils
 
 import (
 	"encoding/base64"
+	"errors"
 	"strings"
+	"sync"
 
 	"password-manager/pkg/security"
 )
 
+// ErrLocked: ключ стёрт или ещё не выведен из мастер-пароля
+var ErrLocked = errors.New("locked: master password not verified or set")
+
 type CryptoService struct {
+    mu  sync.RWMutex
     key []byte
 }
 
@@ -15,22 +21,68 @@ func NewCryptoService(key []byte) *CryptoService {
     return &CryptoService{key: key}
 }
 
-func (c *CryptoService) Encrypt(plain string) (string, error) {
-    enc, err := security.EncryptAESGCM(c.key, []byte(plain))
+// Encrypt: шифрование, привязанное к ad (ID записи, хранилища, версия формата);
+// nil — без привязки
+func (c *CryptoService) Encrypt(plain string, ad []byte) (string, error) {
+    if c == nil {
+        return "", ErrLocked
+    }
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+    if c.key == nil {
+        return "", ErrLocked
+    }
+    enc, err := security.SealAESGCM(c.key, []byte(plain), ad)
     if err != nil {
         return "", err
     }
     return base64.StdEncoding.EncodeToString(enc), nil
 }
 
-func (c *CryptoService) Decrypt(encB64 string) (string, error) {
+// Decrypt: ad должны совпадать с переданными при шифровании
+func (c *CryptoService) Decrypt(encB64 string, ad []byte) (string, error) {
+    if c == nil {
+        return "", ErrLocked
+    }
     data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encB64))
     if err != nil {
         return "", err
     }
-    pt, err := security.DecryptAESGCM(c.key, data)
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+    if c.key == nil {
+        return "", ErrLocked
+    }
+    pt, err := security.OpenAESGCM(c.key, data, ad)
     if err != nil {
         return "", err
     }
     return string(pt), nil
-}
\ No newline at end of file
+}
+
+// KeyedHash: см. security.KeyedHash — сравнимый отпечаток без расшифровки
+func (c *CryptoService) KeyedHash(purpose string, msg []byte) ([]byte, error) {
+    if c == nil {
+        return nil, ErrLocked
+    }
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+    if c.key == nil {
+        return nil, ErrLocked
+    }
+    return security.KeyedHash(c.key, purpose, msg), nil
+}
+
+// Wipe затирает ключ нулями; после этого Encrypt/Decrypt возвращают ErrLocked.
+// Ждёт завершения текущих операций, чтобы не шифровать нулевым ключом.
+func (c *CryptoService) Wipe() {
+    if c == nil {
+        return
+    }
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    for i := range c.key {
+        c.key[i] = 0
+    }
+    c.key = nil
+}
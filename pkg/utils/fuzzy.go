@@ -0,0 +1,167 @@
+// pkg/utils/fuzzy.go
+package utils
+
+import (
+    "strings"
+    "unicode"
+    "unicode/utf8"
+)
+
+// Нечёткий поиск: каждое слово запроса ищется во всех полях документа,
+// лучшее совпадение умножается на вес поля. Совпадения по убыванию
+// ценности: слово целиком, начало слова, подстрока, слово с опечатками
+// (перестановка соседних букв — одна опечатка), буквы слова по порядку
+// ("gthb" → "github"). Документ подходит, только если нашлись все слова.
+
+const (
+    fuzzyExact      = 100
+    fuzzyPrefix     = 80
+    fuzzySubstring  = 60
+    fuzzyTypo       = 50 // минус fuzzyTypoCost за каждую опечатку
+    fuzzyTypoCost   = 15
+    fuzzySubsequent = 20
+)
+
+type fuzzyField struct {
+    text   string // в нижнем регистре
+    words  []string
+    weight int
+}
+
+// FuzzyDoc — поля одной записи, подготовленные для FuzzyScore
+type FuzzyDoc struct {
+    fields []fuzzyField
+}
+
+// Add добавляет поле с весом (пустые поля пропускаются)
+func (d *FuzzyDoc) Add(text string, weight int) {
+    text = strings.ToLower(strings.TrimSpace(text))
+    if text == "" || weight <= 0 {
+        return
+    }
+    d.fields = append(d.fields, fuzzyField{text: text, words: FuzzyTerms(text), weight: weight})
+}
+
+// FuzzyTerms: слова запроса (буквы и цифры) в нижнем регистре
+func FuzzyTerms(query string) []string {
+    return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+        return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+    })
+}
+
+// Score: 0 — документ не подходит; чем больше, тем выше в выдаче
+func (d *FuzzyDoc) Score(terms []string) int {
+    total := 0
+    for _, term := range terms {
+        best := 0
+        for _, f := range d.fields {
+            if s := fieldScore(term, f) * f.weight; s > best {
+                best = s
+            }
+        }
+        if best == 0 {
+            return 0
+        }
+        total += best
+    }
+    return total
+}
+
+func fieldScore(term string, f fuzzyField) int {
+    best := 0
+    for _, w := range f.words {
+        if s := wordScore(term, w); s > best {
+            best = s
+        }
+    }
+    // подстрока через разделители: "hub.co" в "github.com"
+    if best < fuzzySubstring && utf8.RuneCountInString(term) >= 2 && strings.Contains(f.text, term) {
+        best = fuzzySubstring
+    }
+    return best
+}
+
+func wordScore(term, word string) int {
+    switch {
+    case term == word:
+        return fuzzyExact
+    case strings.HasPrefix(word, term):
+        return fuzzyPrefix
+    case utf8.RuneCountInString(term) >= 2 && strings.Contains(word, term):
+        return fuzzySubstring
+    }
+
+    t, w := []rune(term), []rune(word)
+    if allowed := allowedTypos(len(t)); allowed > 0 {
+        dist := typoDistance(t, w, allowed)
+        // слово ещё печатается: сравниваем с началом такой же длины
+        if len(w) > len(t) {
+            if d := typoDistance(t, w[:len(t)], allowed); d < dist {
+                dist = d
+            }
+        }
+        if dist <= allowed {
+            return fuzzyTypo - fuzzyTypoCost*dist
+        }
+    }
+    if len(t) >= 3 && t[0] == w[0] && isSubsequence(t, w) {
+        return fuzzySubsequent
+    }
+    return 0
+}
+
+// allowedTypos: в коротких словах опечатки не прощаются — слишком много ложных совпадений
+func allowedTypos(n int) int {
+    switch {
+    case n < 4:
+        return 0
+    case n < 7:
+        return 1
+    default:
+        return 2
+    }
+}
+
+// typoDistance — расстояние Дамерау–Левенштейна (вариант OSA); если оно
+// больше limit, возвращается limit+1
+func typoDistance(a, b []rune, limit int) int {
+    if d := len(a) - len(b); d > limit || -d > limit {
+        return limit + 1
+    }
+    prev2 := make([]int, len(b)+1)
+    prev := make([]int, len(b)+1)
+    cur := make([]int, len(b)+1)
+    for j := range prev {
+        prev[j] = j
+    }
+    for i := 1; i <= len(a); i++ {
+        cur[0] = i
+        rowMin := cur[0]
+        for j := 1; j <= len(b); j++ {
+            cost := 1
+            if a[i-1] == b[j-1] {
+                cost = 0
+            }
+            cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+            if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+                cur[j] = min(cur[j], prev2[j-2]+1)
+            }
+            rowMin = min(rowMin, cur[j])
+        }
+        if rowMin > limit {
+            return limit + 1
+        }
+        prev2, prev, cur = prev, cur, prev2
+    }
+    return min(prev[len(b)], limit+1)
+}
+
+func isSubsequence(sub, s []rune) bool {
+    i := 0
+    for _, r := range s {
+        if i < len(sub) && sub[i] == r {
+            i++
+        }
+    }
+    return i == len(sub)
+}
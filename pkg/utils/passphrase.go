@@ -0,0 +1,147 @@
+// pkg/utils/passphrase.go
+package utils
+
+import (
+    "bufio"
+    "crypto/rand"
+    _ "embed"
+    "errors"
+    "fmt"
+    "math"
+    "math/big"
+    "strings"
+    "sync"
+    "unicode"
+    "unicode/utf8"
+)
+
+// Парольные фразы (diceware) по большому словарю EFF: 7776 слов, ~12.9 бит на слово.
+// https://www.eff.org/deeplinks/2016/07/new-wordlists-random-passphrases
+
+//go:embed wordlists/eff_large_wordlist.txt
+var effLargeWordlist string
+
+const (
+    MinPassphraseWords     = 3
+    MaxPassphraseWords     = 20
+    DefaultPassphraseWords = 6
+    MaxPassphraseSeparator = 8
+)
+
+type PassphraseOptions struct {
+    Words      int    // количество слов
+    Separator  string // между словами; может быть пустым
+    Capitalize bool   // каждое слово с заглавной буквы — энтропии не добавляет
+    Digits     int    // сколько случайных слов дополнить случайной цифрой
+}
+
+func DefaultPassphraseOptions() PassphraseOptions {
+    return PassphraseOptions{Words: DefaultPassphraseWords, Separator: "-"}
+}
+
+var (
+    effWordsOnce sync.Once
+    effWords     []string
+)
+
+// Строки словаря: «11111<TAB>abacus»
+func passphraseWords() []string {
+    effWordsOnce.Do(func() {
+        sc := bufio.NewScanner(strings.NewReader(effLargeWordlist))
+        for sc.Scan() {
+            fields := strings.Fields(sc.Text())
+            if len(fields) == 2 {
+                effWords = append(effWords, fields[1])
+            }
+        }
+    })
+    return effWords
+}
+
+func (o PassphraseOptions) Validate() error {
+    if o.Words < MinPassphraseWords || o.Words > MaxPassphraseWords {
+        return fmt.Errorf("words must be between %d and %d", MinPassphraseWords, MaxPassphraseWords)
+    }
+    if utf8.RuneCountInString(o.Separator) > MaxPassphraseSeparator {
+        return fmt.Errorf("separator must be at most %d characters", MaxPassphraseSeparator)
+    }
+    for _, r := range o.Separator {
+        if unicode.IsLetter(r) || unicode.IsControl(r) {
+            return errors.New("separator must not contain letters or control characters")
+        }
+    }
+    if o.Digits < 0 || o.Digits > o.Words {
+        return errors.New("digits must be between 0 and the number of words")
+    }
+    return nil
+}
+
+// Entropy — энтропия фразы в битах при известных параметрах генерации (атакующий знает
+// словарь и опции): words·log2(7776) + выбор слов с цифрой log2(C(words, digits)) + digits·log2(10).
+// Цифры в разделителе её не увеличивают.
+func (o PassphraseOptions) Entropy() float64 {
+    bits := float64(o.Words) * math.Log2(float64(len(passphraseWords())))
+    if o.Digits > 0 {
+        bits += log2Binomial(o.Words, o.Digits) + float64(o.Digits)*math.Log2(10)
+    }
+    return bits
+}
+
+func log2Binomial(n, k int) float64 {
+    bits := 0.0
+    for i := 0; i < k; i++ {
+        bits += math.Log2(float64(n-i)) - math.Log2(float64(i+1))
+    }
+    return bits
+}
+
+// GeneratePassphrase возвращает фразу и её энтропию в битах
+func GeneratePassphrase(o PassphraseOptions) (string, float64, error) {
+    if err := o.Validate(); err != nil {
+        return "", 0, err
+    }
+    list := passphraseWords()
+    if len(list) == 0 {
+        return "", 0, errors.New("passphrase wordlist is empty")
+    }
+
+    words := make([]string, o.Words)
+    for i := range words {
+        n, err := randInt(len(list))
+        if err != nil {
+            return "", 0, err
+        }
+        words[i] = list[n]
+        if o.Capitalize {
+            words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+        }
+    }
+
+    // Цифры дописываются к разным словам: частичная перестановка Фишера — Йетса
+    order := make([]int, o.Words)
+    for i := range order {
+        order[i] = i
+    }
+    for i := 0; i < o.Digits; i++ {
+        j, err := randInt(o.Words - i)
+        if err != nil {
+            return "", 0, err
+        }
+        order[i], order[i+j] = order[i+j], order[i]
+        d, err := randInt(10)
+        if err != nil {
+            return "", 0, err
+        }
+        words[order[i]] += string(rune('0' + d))
+    }
+
+    return strings.Join(words, o.Separator), o.Entropy(), nil
+}
+
+func randInt(n int) (int, error) {
+    v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+    if err != nil {
+        return 0, err
+    }
+    return int(v.Int64()), nil
+}
@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"os"
+)
+
+// CreatePrivateFile создаёт (или обрезает) файл, доступный только владельцу (0600).
+// Права выставляются и для уже существующего файла: umask и старые права не в счёт.
+func CreatePrivateFile(path string) (*os.File, error) {
+    f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+    if err != nil {
+        return nil, err
+    }
+    if err := f.Chmod(0o600); err != nil {
+        f.Close()
+        return nil, err
+    }
+    return f, nil
+}